@@ -0,0 +1,131 @@
+package exif
+
+import (
+	"bytes"
+	"math"
+
+	"encoding/binary"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/mschilli/go-exif/v3/common"
+)
+
+// BigTIFF is the same as TIFF except that offsets and counts are eight bytes.
+// The header has the magic number 43 (instead of 42), the size of an offset
+// (always eight), two reserved bytes, and the eight-byte offset of the first
+// IFD. Each IFD has an eight-byte tag-count, twenty-byte entries (whose
+// value-offsets hold values of up to eight bytes), and an eight-byte next-IFD
+// offset. The LONG8, SLONG8, and IFD8 types are only defined for BigTIFF.
+//
+// BigTIFFs are read by the same enumerator as any other TIFF, which notices
+// the header at the front of the data. Since offsets and counts are 32-bit
+// throughout this package, tags whose offset or count doesn't fit in 32 bits
+// are logged and skipped (ErrBigTiff is returned in strict mode), as are IFDs
+// past the first 4 GiB. BigTIFFs can not be rewritten.
+
+const (
+	// BigTiffHeaderLength is the number of bytes in the BigTIFF header.
+	BigTiffHeaderLength = 16
+
+	bigTiffOffsetSize  = 8
+	bigTiffEntryLength = 2 + 2 + 8 + 8
+)
+
+// isBigTiffHeader returns true if the data starts with a BigTIFF header. Only
+// the first eight bytes are required.
+func isBigTiffHeader(data []byte) bool {
+	if len(data) < 8 {
+		return false
+	}
+
+	if bytes.Equal(data[:4], BigTiffBigEndianSignature[:]) == false && bytes.Equal(data[:4], BigTiffLittleEndianSignature[:]) == false {
+		return false
+	}
+
+	byteOrder := bigTiffByteOrder(data)
+
+	return byteOrder.Uint16(data[4:6]) == bigTiffOffsetSize && byteOrder.Uint16(data[6:8]) == 0
+}
+
+// bigTiffByteOrder returns the byte-order from the marker at the front of the
+// data.
+func bigTiffByteOrder(data []byte) binary.ByteOrder {
+	if data[0] == 'M' {
+		return binary.BigEndian
+	}
+
+	return binary.LittleEndian
+}
+
+// tagIndexType returns the type that the tag-index knows the given type as.
+// The tag-index only has the TIFF types, and LONG8 and IFD8 are what BigTIFF
+// writers store LONGs (like offsets) as.
+func tagIndexType(tagType exifcommon.TagTypePrimitive) exifcommon.TagTypePrimitive {
+	switch tagType {
+	case exifcommon.TypeLong8, exifcommon.TypeIfd8:
+		return exifcommon.TypeLong
+	case exifcommon.TypeSignedLong8:
+		return exifcommon.TypeSignedLong
+	}
+
+	return tagType
+}
+
+// bigTiffValueOffset returns the unit-count and the value-offset of a BigTIFF
+// entry as 32-bit numbers, which is all that IfdTagEntry has room for. Like
+// in TIFF, the value-offset of a value that is stored in the entry is only
+// meaningful for a single integer, so a single LONG8 or IFD8 is read as an
+// eight-byte offset (e.g. of a child IFD) and anything else in the entry as a
+// four-byte one. The type has to be valid. ErrBigTiff is returned (unwrapped)
+// if the unit-count or the value-offset doesn't fit in 32 bits.
+func bigTiffValueOffset(tagType exifcommon.TagTypePrimitive, unitCount uint64, rawValueOffset []byte, byteOrder binary.ByteOrder) (unitCount32 uint32, valueOffset uint32, err error) {
+	if unitCount > math.MaxUint32 {
+		return 0, 0, ErrBigTiff
+	}
+
+	unitSize, _ := tagType.UnitSize()
+	byteLength := uint64(unitSize) * unitCount
+
+	// A value in the entry is only read as an eight-byte offset if it is one.
+	if byteLength <= bigTiffOffsetSize && tagType != exifcommon.TypeLong8 && tagType != exifcommon.TypeIfd8 {
+		return uint32(unitCount), byteOrder.Uint32(rawValueOffset), nil
+	}
+
+	valueOffset64 := byteOrder.Uint64(rawValueOffset)
+	if valueOffset64 > math.MaxUint32 {
+		return 0, 0, ErrBigTiff
+	}
+
+	return uint32(unitCount), uint32(valueOffset64), nil
+}
+
+// uint32sFromValue returns a list of LONGs, or of LONG8s or IFD8s (which are
+// what BigTIFF stores them as), as the former. ErrBigTiff is returned
+// (unwrapped) if one of them doesn't fit in 32 bits.
+func uint32sFromValue(value interface{}) (values []uint32, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	switch t := value.(type) {
+	case []uint32:
+		return t, nil
+	case []uint64:
+		values = make([]uint32, len(t))
+		for i, v := range t {
+			if v > math.MaxUint32 {
+				return nil, ErrBigTiff
+			}
+
+			values[i] = uint32(v)
+		}
+
+		return values, nil
+	}
+
+	log.Panicf("value is not a list of longs: [%T]", value)
+	return nil, nil
+}
@@ -0,0 +1,296 @@
+package exif
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"encoding/binary"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/mschilli/go-exif/v3/common"
+)
+
+// getTestBigTiffData returns a BigTIFF with the given byte-order. IFD0 has an
+// inline LONG8, an entry with an invalid type, a six-byte inline ASCII, an
+// inline SHORT, and an IFD8 that points to the Exif IFD. The Exif IFD has an
+// inline RATIONAL, an external ASCII (at offset 244), and an inline LONG.
+// IFD1 has one SHORT.
+func getTestBigTiffData(byteOrder binary.ByteOrder) []byte {
+	data := make([]byte, 264)
+
+	if byteOrder == binary.BigEndian {
+		copy(data, BigTiffBigEndianSignature[:])
+	} else {
+		copy(data, BigTiffLittleEndianSignature[:])
+	}
+
+	byteOrder.PutUint16(data[4:], 8)
+	byteOrder.PutUint64(data[8:], 16)
+
+	uint64Bytes := func(value uint64) []byte {
+		raw := make([]byte, 8)
+		byteOrder.PutUint64(raw, value)
+
+		return raw
+	}
+
+	uint32Bytes := func(values ...uint32) []byte {
+		raw := make([]byte, 4*len(values))
+		for i, value := range values {
+			byteOrder.PutUint32(raw[i*4:], value)
+		}
+
+		return raw
+	}
+
+	shortBytes := make([]byte, 2)
+	byteOrder.PutUint16(shortBytes, 6)
+
+	// IFD0
+	byteOrder.PutUint64(data[16:], 5)
+	putTestBigTiffIfdEntry(data, byteOrder, 24, 0x0100, uint16(exifcommon.TypeLong8), 1, uint64Bytes(640))
+	putTestBigTiffIfdEntry(data, byteOrder, 44, 0x0101, 99, 1, nil)
+	putTestBigTiffIfdEntry(data, byteOrder, 64, 0x0110, uint16(exifcommon.TypeAscii), 6, []byte("Model\000"))
+	putTestBigTiffIfdEntry(data, byteOrder, 84, 0x0112, uint16(exifcommon.TypeShort), 1, shortBytes)
+	putTestBigTiffIfdEntry(data, byteOrder, 104, exifcommon.IfdExifStandardIfdIdentity.TagId(), uint16(exifcommon.TypeIfd8), 1, uint64Bytes(132))
+	byteOrder.PutUint64(data[124:], 208)
+
+	// Exif IFD
+	byteOrder.PutUint64(data[132:], 3)
+	putTestBigTiffIfdEntry(data, byteOrder, 140, 0x829a, uint16(exifcommon.TypeRational), 1, uint32Bytes(1, 100))
+	putTestBigTiffIfdEntry(data, byteOrder, 160, 0x9003, uint16(exifcommon.TypeAscii), 20, uint64Bytes(244))
+	putTestBigTiffIfdEntry(data, byteOrder, 180, 0xa002, uint16(exifcommon.TypeLong), 1, uint32Bytes(640))
+	byteOrder.PutUint64(data[200:], 0)
+
+	// IFD1
+	byteOrder.PutUint64(data[208:], 1)
+	putTestBigTiffIfdEntry(data, byteOrder, 216, 0x0103, uint16(exifcommon.TypeShort), 1, shortBytes)
+	byteOrder.PutUint64(data[236:], 0)
+
+	copy(data[244:], "2020:01:02 03:04:05\000")
+
+	return data
+}
+
+// collectTestBigTiff collects the tree of the given BigTIFF.
+func collectTestBigTiff(data []byte, strictMode bool) (ie *IfdEnumerate, index IfdIndex, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, eh, err := NewIfdEnumerateWithBytes(im, ti, data)
+	log.PanicIf(err)
+
+	ie.SetStrictMode(strictMode)
+
+	index, err = ie.Collect(eh.FirstIfdOffset)
+	if err != nil {
+		if log.Is(err, ErrBigTiff) == true {
+			return nil, index, ErrBigTiff
+		}
+
+		log.Panic(err)
+	}
+
+	return ie, index, nil
+}
+
+func TestIfdEnumerate_Collect__BigTiff(t *testing.T) {
+	for _, byteOrder := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		ie, index, err := collectTestBigTiff(getTestBigTiffData(byteOrder), false)
+		log.PanicIf(err)
+
+		expected := map[string]map[uint16]interface{}{
+			"IFD": {
+				0x0100: []uint64{640},
+				0x0110: "Model",
+				0x0112: []uint16{6},
+			},
+			"IFD/Exif": {
+				0x829a: []exifcommon.Rational{{Numerator: 1, Denominator: 100}},
+				0x9003: "2020:01:02 03:04:05",
+				0xa002: []uint32{640},
+			},
+			"IFD1": {
+				0x0103: []uint16{6},
+			},
+		}
+
+		for fqIfdPath, expectedValues := range expected {
+			ifd, found := index.Lookup[fqIfdPath]
+			if found == false {
+				t.Fatalf("IFD [%s] not found (%v).", fqIfdPath, byteOrder)
+			}
+
+			for tagId, expectedValue := range expectedValues {
+				results, err := ifd.FindTagWithId(tagId)
+				log.PanicIf(err)
+
+				value, err := ie.TagValue(results[0])
+				log.PanicIf(err)
+
+				if reflect.DeepEqual(value, expectedValue) == false {
+					t.Fatalf("Value for tag (0x%04x) in IFD [%s] not correct (%v): %v != %v", tagId, fqIfdPath, byteOrder, value, expectedValue)
+				}
+			}
+		}
+
+		// The entry with the invalid type is skipped, but the pointer to the
+		// Exif IFD is kept.
+		if len(index.RootIfd.Entries()) != 4 {
+			t.Fatalf("Expected four entries in IFD0 (%v): (%d)", byteOrder, len(index.RootIfd.Entries()))
+		}
+
+		exifIfd := index.Lookup["IFD/Exif"]
+		if exifIfd.Offset() != 132 {
+			t.Fatalf("Exif IFD offset not correct (%v): (%d)", byteOrder, exifIfd.Offset())
+		}
+
+		// The IFDs and the external value are laid out back to back.
+		conflicts, err := ie.ValidateOffsets(index.RootIfd)
+		log.PanicIf(err)
+
+		if len(conflicts) != 0 {
+			t.Fatalf("Expected no conflicts (%v): %v", byteOrder, conflicts)
+		}
+
+		min, max, err := ie.UsedByteRange(index.RootIfd)
+		log.PanicIf(err)
+
+		if min != 16 || max != 264 {
+			t.Fatalf("Used byte-range not correct (%v): (%d) (%d)", byteOrder, min, max)
+		}
+	}
+}
+
+func TestIfdEnumerate_Collect__BigTiffDumpAndFingerprint(t *testing.T) {
+	ie, index, err := collectTestBigTiff(getTestBigTiffData(binary.LittleEndian), false)
+	log.PanicIf(err)
+
+	ifdDump, err := index.RootIfd.Dump()
+	log.PanicIf(err)
+
+	if ifdDump.Tags[0].TagTypeName != "LONG8" || reflect.DeepEqual(ifdDump.Tags[0].Value, []uint64{640}) == false {
+		t.Fatalf("LONG8 dump not correct: %v", ifdDump.Tags[0])
+	} else if len(ifdDump.Children) != 1 || ifdDump.Children[0].IfdPath != "IFD/Exif" {
+		t.Fatalf("Exif IFD not dumped: %v", ifdDump.Children)
+	} else if ifdDump.Next == nil || ifdDump.Next.IfdPath != "IFD1" {
+		t.Fatalf("IFD1 not dumped: %v", ifdDump.Next)
+	}
+
+	visited := make([]string, 0)
+	err = index.RootIfd.Walk(func(ifd *Ifd, depth int) error {
+		visited = append(visited, ifd.ifdIdentity.String())
+		return nil
+	})
+
+	log.PanicIf(err)
+
+	if reflect.DeepEqual(visited, []string{"IFD", "IFD1", "IFD/Exif"}) == false {
+		t.Fatalf("Walk not correct: %v", visited)
+	}
+
+	fingerprint, err := ie.Fingerprint(index.RootIfd)
+	log.PanicIf(err)
+
+	// The external value in the child IFD is part of the fingerprint.
+	data := getTestBigTiffData(binary.LittleEndian)
+	copy(data[244:], "2021")
+
+	ie, index, err = collectTestBigTiff(data, false)
+	log.PanicIf(err)
+
+	changedFingerprint, err := ie.Fingerprint(index.RootIfd)
+	log.PanicIf(err)
+
+	if changedFingerprint == fingerprint {
+		t.Fatalf("Expected the fingerprint to change: (0x%016x)", fingerprint)
+	}
+}
+
+func TestIfdEnumerate_Collect__BigTiffOffsetPast4GiB(t *testing.T) {
+	data := getTestBigTiffData(binary.LittleEndian)
+
+	// Move the DateTimeOriginal value past the first 4 GiB. The entry with
+	// the invalid type is given a valid one so that strict mode gets as far.
+	binary.LittleEndian.PutUint64(data[172:], 0x100000000)
+	binary.LittleEndian.PutUint16(data[46:], uint16(exifcommon.TypeShort))
+
+	_, index, err := collectTestBigTiff(data, false)
+	log.PanicIf(err)
+
+	_, err = index.Lookup["IFD/Exif"].FindTagWithId(0x9003)
+	if log.Is(err, ErrTagNotFound) == false {
+		t.Fatalf("Expected the tag to be skipped: %v", err)
+	}
+
+	_, _, err = collectTestBigTiff(data, true)
+	if err != ErrBigTiff {
+		t.Fatalf("Expected ErrBigTiff in strict mode: %v", err)
+	}
+}
+
+func TestParseExifHeader__BigTiff(t *testing.T) {
+	data := getTestBigTiffData(binary.BigEndian)
+
+	eh, err := ParseExifHeader(data)
+	log.PanicIf(err)
+
+	if eh.IsBigTiff != true {
+		t.Fatalf("Expected a BigTIFF.")
+	} else if eh.ByteOrder != binary.BigEndian {
+		t.Fatalf("Byte-order not correct: %v", eh.ByteOrder)
+	} else if eh.FirstIfdOffset != 16 {
+		t.Fatalf("First IFD offset not correct: (%d)", eh.FirstIfdOffset)
+	}
+
+	_, err = ParseExifHeader(data[:ExifSignatureLength])
+	if err != ErrNoExif {
+		t.Fatalf("Expected ErrNoExif for a short BigTIFF header: %v", err)
+	}
+
+	binary.BigEndian.PutUint64(data[8:], 0x100000000)
+
+	_, err = ParseExifHeader(data)
+	if err != ErrBigTiff {
+		t.Fatalf("Expected ErrBigTiff: %v", err)
+	}
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	_, _, err = NewIfdEnumerateWithBytes(im, ti, data)
+	if err != ErrBigTiff {
+		t.Fatalf("Expected ErrBigTiff from NewIfdEnumerateWithBytes: %v", err)
+	}
+
+	// The offset-size has to be eight.
+	binary.BigEndian.PutUint16(data[4:], 4)
+
+	_, err = ParseExifHeader(data)
+	if err != ErrNoExif {
+		t.Fatalf("Expected ErrNoExif: %v", err)
+	}
+}
+
+func TestSearchAndExtractExif__BigTiff(t *testing.T) {
+	data := getTestBigTiffData(binary.LittleEndian)
+
+	rawExif, err := SearchAndExtractExif(append([]byte("junk"), data...))
+	log.PanicIf(err)
+
+	if bytes.Equal(rawExif, data) != true {
+		t.Fatalf("BigTIFF not found.")
+	}
+}
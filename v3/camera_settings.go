@@ -0,0 +1,150 @@
+package exif
+
+import (
+	"github.com/dsoprea/go-logging"
+
+	"github.com/mschilli/go-exif/v3/common"
+)
+
+const (
+	exposureTimeTagId    = 0x829a
+	fNumberTagId         = 0x829d
+	isoSpeedRatingsTagId = 0x8827
+	flashTagId           = 0x9209
+	focalLengthTagId     = 0x920a
+)
+
+// CameraSetting flags one of the fields of CameraSettings.
+type CameraSetting uint
+
+const (
+	// CameraSettingExposureTime flags `CameraSettings.ExposureTime` and
+	// `CameraSettings.ExposureTimeSeconds`.
+	CameraSettingExposureTime CameraSetting = 1 << iota
+
+	// CameraSettingFNumber flags `CameraSettings.FNumber`.
+	CameraSettingFNumber
+
+	// CameraSettingIsoSpeedRatings flags `CameraSettings.IsoSpeedRatings`.
+	CameraSettingIsoSpeedRatings
+
+	// CameraSettingFocalLength flags `CameraSettings.FocalLength`.
+	CameraSettingFocalLength
+
+	// CameraSettingFlash flags `CameraSettings.Flash`.
+	CameraSettingFlash
+)
+
+// CameraSettings has the common shooting parameters from the Exif IFD. Fields
+// whose tags are missing (or don't have the expected shape) are left zero and
+// are not flagged in `Present`.
+type CameraSettings struct {
+	// ExposureTime is the exposure in seconds, as it was stored.
+	ExposureTime exifcommon.Rational
+
+	// ExposureTimeSeconds is the exposure in seconds.
+	ExposureTimeSeconds float64
+
+	// FNumber is the aperture (e.g. 2.8).
+	FNumber float64
+
+	// IsoSpeedRatings is the (first) ISO speed.
+	IsoSpeedRatings uint16
+
+	// FocalLength is the focal-length in millimeters.
+	FocalLength float64
+
+	// Flash is the raw Flash value. Bit 0 is set if the flash fired.
+	Flash uint16
+
+	// Present flags which of the fields were found.
+	Present CameraSetting
+}
+
+// Has returns true if the given field was found.
+func (cs *CameraSettings) Has(setting CameraSetting) bool {
+	return cs.Present&setting != 0
+}
+
+// CameraSettings returns the common shooting parameters from the Exif IFD.
+// Missing tags are not an error. See `CameraSettings`.
+func (index IfdIndex) CameraSettings() (cs *CameraSettings, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	cs = new(CameraSettings)
+
+	exifIfd, found := index.Lookup[exifcommon.IfdExifStandardIfdIdentity.String()]
+	if found == false {
+		return cs, nil
+	}
+
+	if value, found := cameraSettingValue(exifIfd, exposureTimeTagId); found == true {
+		if r, ok := singleRational(value); ok == true {
+			cs.ExposureTime = r
+			cs.ExposureTimeSeconds = r.Float64()
+			cs.Present |= CameraSettingExposureTime
+		}
+	}
+
+	if value, found := cameraSettingValue(exifIfd, fNumberTagId); found == true {
+		if r, ok := singleRational(value); ok == true {
+			cs.FNumber = r.Float64()
+			cs.Present |= CameraSettingFNumber
+		}
+	}
+
+	if value, found := cameraSettingValue(exifIfd, isoSpeedRatingsTagId); found == true {
+		if values, ok := value.([]uint16); ok == true && len(values) > 0 {
+			cs.IsoSpeedRatings = values[0]
+			cs.Present |= CameraSettingIsoSpeedRatings
+		}
+	}
+
+	if value, found := cameraSettingValue(exifIfd, focalLengthTagId); found == true {
+		if r, ok := singleRational(value); ok == true {
+			cs.FocalLength = r.Float64()
+			cs.Present |= CameraSettingFocalLength
+		}
+	}
+
+	if value, found := cameraSettingValue(exifIfd, flashTagId); found == true {
+		if values, ok := value.([]uint16); ok == true && len(values) == 1 {
+			cs.Flash = values[0]
+			cs.Present |= CameraSettingFlash
+		}
+	}
+
+	return cs, nil
+}
+
+// cameraSettingValue returns the value of the given tag. Values that can't be
+// read are logged and treated as missing.
+func cameraSettingValue(ifd *Ifd, tagId uint16) (value interface{}, found bool) {
+	ite, found := ifd.EntryByTagId(tagId)
+	if found == false {
+		return nil, false
+	}
+
+	value, err := ite.Value()
+	if err != nil {
+		ifdEnumerateLogger.Warningf(nil, "Could not read camera-setting tag (0x%04x) and it will be ignored: %v", tagId, err)
+		return nil, false
+	}
+
+	return value, true
+}
+
+// CameraSettings returns the common shooting parameters from the Exif IFD.
+// See `IfdIndex.CameraSettings()`.
+func (ie *IfdEnumerate) CameraSettings() (cs *CameraSettings, err error) {
+	index, err := ie.getHeaderIndex(false)
+	if err != nil {
+		return nil, err
+	}
+
+	return index.CameraSettings()
+}
@@ -0,0 +1,73 @@
+package exif
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/mschilli/go-exif/v3/common"
+)
+
+func TestIfdEnumerate_CameraSettings(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, _, err := NewIfdEnumerateWithBytes(im, ti, getTestExifData())
+	log.PanicIf(err)
+
+	cs, err := ie.CameraSettings()
+	log.PanicIf(err)
+
+	expected := &CameraSettings{
+		ExposureTime: exifcommon.Rational{
+			Numerator:   1,
+			Denominator: 640,
+		},
+		ExposureTimeSeconds: 1.0 / 640,
+		FNumber:             4,
+		IsoSpeedRatings:     1600,
+		FocalLength:         16,
+		Flash:               16,
+		Present:             CameraSettingExposureTime | CameraSettingFNumber | CameraSettingIsoSpeedRatings | CameraSettingFocalLength | CameraSettingFlash,
+	}
+
+	if reflect.DeepEqual(cs, expected) == false {
+		t.Fatalf("Camera-settings not correct: %v != %v", cs, expected)
+	}
+}
+
+func TestIfdEnumerate_CameraSettings__Missing(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, _, err := NewIfdEnumerateWithBytes(im, ti, getTestOrientationExifData(1))
+	log.PanicIf(err)
+
+	cs, err := ie.CameraSettings()
+	log.PanicIf(err)
+
+	if reflect.DeepEqual(cs, &CameraSettings{}) == false {
+		t.Fatalf("Expected empty camera-settings: %v", cs)
+	} else if cs.Has(CameraSettingFNumber) == true {
+		t.Fatalf("Expected FNumber to not be present.")
+	}
+}
+
+func TestCameraSettings_Has(t *testing.T) {
+	cs := &CameraSettings{
+		Present: CameraSettingFNumber | CameraSettingFlash,
+	}
+
+	if cs.Has(CameraSettingFNumber) != true {
+		t.Fatalf("Expected FNumber.")
+	} else if cs.Has(CameraSettingFlash) != true {
+		t.Fatalf("Expected Flash.")
+	} else if cs.Has(CameraSettingExposureTime) != false {
+		t.Fatalf("Expected no ExposureTime.")
+	}
+}
@@ -0,0 +1,124 @@
+package exif
+
+import (
+	"fmt"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/mschilli/go-exif/v3/common"
+)
+
+const (
+	// colorSpaceTagId is the tag-ID of the ColorSpace tag in the Exif IFD.
+	colorSpaceTagId = 0xa001
+
+	// interopIndexTagId is the tag-ID of the InteroperabilityIndex tag in
+	// the Interoperability IFD.
+	interopIndexTagId = 0x0001
+)
+
+const (
+	// ColorSpaceSRgb is the ColorSpace value for sRGB.
+	ColorSpaceSRgb = 0x0001
+
+	// ColorSpaceUncalibrated is the ColorSpace value for anything other than
+	// sRGB. Adobe RGB is usually written this way, and then it is the
+	// InteroperabilityIndex that tells them apart.
+	ColorSpaceUncalibrated = 0xffff
+
+	// InteropIndexAdobeRgb is the InteroperabilityIndex of an Adobe RGB
+	// (DCF option file) image.
+	InteropIndexAdobeRgb = "R03"
+)
+
+// ColorSpace is the color space of the image, as given by the ColorSpace tag
+// and, for uncalibrated images, the InteroperabilityIndex tag.
+type ColorSpace struct {
+	// Value is the ColorSpace tag as it was stored.
+	Value uint16
+
+	// InteropIndex is the InteroperabilityIndex tag (e.g. "R98" or "R03"),
+	// or empty if there isn't one.
+	InteropIndex string
+}
+
+// IsSRgb returns true if the image is sRGB.
+func (cs ColorSpace) IsSRgb() bool {
+	return cs.Value == ColorSpaceSRgb
+}
+
+// IsAdobeRgb returns true if the image is uncalibrated and the
+// InteroperabilityIndex says that it is Adobe RGB. This is a best effort:
+// the specification doesn't say what an uncalibrated image is.
+func (cs ColorSpace) IsAdobeRgb() bool {
+	return cs.Value == ColorSpaceUncalibrated && cs.InteropIndex == InteropIndexAdobeRgb
+}
+
+// String returns a name for the color space: "sRGB", "Adobe RGB",
+// "Uncalibrated", or the value for anything else.
+func (cs ColorSpace) String() string {
+	if cs.IsSRgb() == true {
+		return "sRGB"
+	} else if cs.IsAdobeRgb() == true {
+		return "Adobe RGB"
+	} else if cs.Value == ColorSpaceUncalibrated {
+		return "Uncalibrated"
+	}
+
+	return fmt.Sprintf("ColorSpace<%d>", cs.Value)
+}
+
+// ColorSpace returns the ColorSpace tag from the Exif IFD along with the
+// InteroperabilityIndex tag, if there is one. ErrTagNotFound is returned
+// (unwrapped) if there isn't a ColorSpace tag.
+func (index IfdIndex) ColorSpace() (cs ColorSpace, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	ite, _, err := index.FindTag(exifcommon.IfdExifStandardIfdIdentity.String(), colorSpaceTagId)
+	if err != nil {
+		if err == ErrTagNotFound {
+			return cs, err
+		}
+
+		log.Panic(err)
+	}
+
+	valueRaw, err := ite.Value()
+	log.PanicIf(err)
+
+	values, ok := valueRaw.([]uint16)
+	if ok == false || len(values) != 1 {
+		log.Panicf("color-space tag is not a single SHORT: [%s] (%d)", ite.TagType(), ite.UnitCount())
+	}
+
+	cs.Value = values[0]
+
+	ite, _, err = index.FindTag(exifcommon.IfdExifIopStandardIfdIdentity.String(), interopIndexTagId)
+	if err == nil {
+		valueRaw, err := ite.Value()
+		if err != nil {
+			ifdEnumerateLogger.Warningf(nil, "Could not read the InteroperabilityIndex tag and it will be ignored: %v", err)
+		} else if interopIndex, ok := valueRaw.(string); ok == true {
+			cs.InteropIndex = interopIndex
+		}
+	} else if err != ErrTagNotFound {
+		log.Panic(err)
+	}
+
+	return cs, nil
+}
+
+// ColorSpace returns the color space of the image. See
+// `IfdIndex.ColorSpace()`.
+func (ie *IfdEnumerate) ColorSpace() (cs ColorSpace, err error) {
+	index, err := ie.getHeaderIndex(false)
+	if err != nil {
+		return cs, err
+	}
+
+	return index.ColorSpace()
+}
@@ -0,0 +1,126 @@
+package exif
+
+import (
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/mschilli/go-exif/v3/common"
+)
+
+// getTestColorSpaceExifData returns an EXIF blob with the given ColorSpace
+// and, if it isn't empty, InteroperabilityIndex.
+func getTestColorSpaceExifData(colorSpace uint16, interopIndex string) []byte {
+	exifIfd := testBuilderIfd{
+		ii: exifcommon.IfdExifStandardIfdIdentity,
+		tags: []testBuilderTag{
+			{tagId: colorSpaceTagId, value: []uint16{colorSpace}},
+		},
+	}
+
+	if interopIndex != "" {
+		exifIfd.children = []testBuilderIfd{
+			{
+				ii: exifcommon.IfdExifIopStandardIfdIdentity,
+				tags: []testBuilderTag{
+					{tagId: interopIndexTagId, value: interopIndex},
+				},
+			},
+		}
+	}
+
+	return getTestBuilderExifData(exifcommon.TestDefaultByteOrder, nil, exifIfd)
+}
+
+func TestColorSpace_String(t *testing.T) {
+	cases := []struct {
+		cs       ColorSpace
+		expected string
+	}{
+		{ColorSpace{Value: ColorSpaceSRgb}, "sRGB"},
+		{ColorSpace{Value: ColorSpaceUncalibrated, InteropIndex: "R03"}, "Adobe RGB"},
+		{ColorSpace{Value: ColorSpaceUncalibrated, InteropIndex: "R98"}, "Uncalibrated"},
+		{ColorSpace{Value: ColorSpaceUncalibrated}, "Uncalibrated"},
+		{ColorSpace{Value: 2}, "ColorSpace<2>"},
+	}
+
+	for _, c := range cases {
+		if c.cs.String() != c.expected {
+			t.Fatalf("String() not correct for %v: [%s] != [%s]", c.cs, c.cs.String(), c.expected)
+		}
+	}
+}
+
+func TestIfdEnumerate_ColorSpace(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, _, err := NewIfdEnumerateWithBytes(im, ti, getTestExifData())
+	log.PanicIf(err)
+
+	cs, err := ie.ColorSpace()
+	log.PanicIf(err)
+
+	if cs.IsSRgb() == false {
+		t.Fatalf("Color space not correct: %v", cs)
+	} else if cs.String() != "sRGB" {
+		t.Fatalf("String() not correct: [%s]", cs.String())
+	}
+}
+
+func TestIfdEnumerate_ColorSpace__AdobeRgb(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, _, err := NewIfdEnumerateWithBytes(im, ti, getTestColorSpaceExifData(ColorSpaceUncalibrated, "R03"))
+	log.PanicIf(err)
+
+	cs, err := ie.ColorSpace()
+	log.PanicIf(err)
+
+	if cs.Value != ColorSpaceUncalibrated || cs.InteropIndex != "R03" {
+		t.Fatalf("Color space not correct: %v", cs)
+	} else if cs.IsAdobeRgb() == false {
+		t.Fatalf("Color space should be Adobe RGB.")
+	}
+}
+
+func TestIfdEnumerate_ColorSpace__Uncalibrated(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, _, err := NewIfdEnumerateWithBytes(im, ti, getTestColorSpaceExifData(ColorSpaceUncalibrated, ""))
+	log.PanicIf(err)
+
+	cs, err := ie.ColorSpace()
+	log.PanicIf(err)
+
+	if cs.Value != ColorSpaceUncalibrated || cs.InteropIndex != "" {
+		t.Fatalf("Color space not correct: %v", cs)
+	} else if cs.IsAdobeRgb() == true {
+		t.Fatalf("Color space should not be Adobe RGB.")
+	} else if cs.String() != "Uncalibrated" {
+		t.Fatalf("String() not correct: [%s]", cs.String())
+	}
+}
+
+func TestIfdEnumerate_ColorSpace__NotFound(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, _, err := NewIfdEnumerateWithBytes(im, ti, getTestOrientationExifData(0))
+	log.PanicIf(err)
+
+	_, err = ie.ColorSpace()
+	if err != ErrTagNotFound {
+		t.Fatalf("Expected not-found error: %v", err)
+	}
+}
@@ -43,6 +43,28 @@ func (p *Parser) ParseBytes(data []byte, unitCount uint32) (value []uint8, err e
 	return value, nil
 }
 
+// ParseSignedBytes knows how to parse a signed-byte-type value.
+func (p *Parser) ParseSignedBytes(data []byte, unitCount uint32) (value []int8, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	count := int(unitCount)
+
+	if len(data) < (TypeSignedByte.Size() * count) {
+		log.Panic(ErrNotEnoughData)
+	}
+
+	value = make([]int8, count)
+	for i := 0; i < count; i++ {
+		value[i] = int8(data[i])
+	}
+
+	return value, nil
+}
+
 // ParseAscii returns a string and auto-strips the trailing NUL character that
 // should be at the end of the encoding.
 func (p *Parser) ParseAscii(data []byte, unitCount uint32) (value string, err error) {
@@ -60,7 +82,12 @@ func (p *Parser) ParseAscii(data []byte, unitCount uint32) (value string, err er
 		log.Panic(ErrNotEnoughData)
 	}
 
-	if len(data) == 0 || data[count-1] != 0 {
+	// An empty value is valid (and doesn't even have the NUL).
+	if count == 0 {
+		return "", nil
+	}
+
+	if data[count-1] != 0 {
 		s := string(data[:count])
 		parserLogger.Warningf(nil, "ASCII not terminated with NUL as expected: [%v]", s)
 
@@ -152,6 +179,52 @@ func (p *Parser) ParseLongs(data []byte, unitCount uint32, byteOrder binary.Byte
 	return value, nil
 }
 
+// ParseLong8s knows how to parse an encoded list of eight-byte unsigned
+// integers (LONG8 and IFD8, which are only defined for BigTIFF).
+func (p *Parser) ParseLong8s(data []byte, unitCount uint32, byteOrder binary.ByteOrder) (value []uint64, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	count := int(unitCount)
+
+	if len(data) < (TypeLong8.Size() * count) {
+		log.Panic(ErrNotEnoughData)
+	}
+
+	value = make([]uint64, count)
+	for i := 0; i < count; i++ {
+		value[i] = byteOrder.Uint64(data[i*8:])
+	}
+
+	return value, nil
+}
+
+// ParseSignedLong8s knows how to parse an encoded list of eight-byte signed
+// integers (SLONG8, which is only defined for BigTIFF).
+func (p *Parser) ParseSignedLong8s(data []byte, unitCount uint32, byteOrder binary.ByteOrder) (value []int64, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	count := int(unitCount)
+
+	if len(data) < (TypeSignedLong8.Size() * count) {
+		log.Panic(ErrNotEnoughData)
+	}
+
+	value = make([]int64, count)
+	for i := 0; i < count; i++ {
+		value[i] = int64(byteOrder.Uint64(data[i*8:]))
+	}
+
+	return value, nil
+}
+
 // ParseFloats knows how to encode an encoded list of floats.
 func (p *Parser) ParseFloats(data []byte, unitCount uint32, byteOrder binary.ByteOrder) (value []float32, err error) {
 	defer func() {
@@ -72,6 +72,25 @@ func TestParser_ParseAscii(t *testing.T) {
 	}
 }
 
+func TestParser_ParseAscii__Empty(t *testing.T) {
+	p := new(Parser)
+
+	// The data might be longer than the value (e.g. the value-offset bytes).
+	value, err := p.ParseAscii([]byte{'a', 'b', 0, 0}, 0)
+	log.PanicIf(err)
+
+	if value != "" {
+		t.Fatalf("Expected an empty string: [%s]", value)
+	}
+
+	value, err = p.ParseAscii([]byte{}, 0)
+	log.PanicIf(err)
+
+	if value != "" {
+		t.Fatalf("Expected an empty string for empty data: [%s]", value)
+	}
+}
+
 func TestParser_ParseAsciiNoNul(t *testing.T) {
 	p := new(Parser)
 
@@ -171,6 +190,40 @@ func TestParser_ParseLongs__Multiple(t *testing.T) {
 	}
 }
 
+func TestParser_ParseLong8s(t *testing.T) {
+	p := new(Parser)
+
+	encoded := []byte{
+		0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02,
+	}
+
+	value, err := p.ParseLong8s(encoded, 2, TestDefaultByteOrder)
+	log.PanicIf(err)
+
+	if reflect.DeepEqual(value, []uint64{0x100000000, 2}) != true {
+		t.Fatalf("Encoding not correct: %v", value)
+	}
+
+	_, err = p.ParseLong8s(encoded, 3, TestDefaultByteOrder)
+	if log.Is(err, ErrNotEnoughData) == false {
+		t.Fatalf("Expected ErrNotEnoughData: %v", err)
+	}
+}
+
+func TestParser_ParseSignedLong8s(t *testing.T) {
+	p := new(Parser)
+
+	encoded := []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xfe}
+
+	value, err := p.ParseSignedLong8s(encoded, 1, TestDefaultByteOrder)
+	log.PanicIf(err)
+
+	if reflect.DeepEqual(value, []int64{-2}) != true {
+		t.Fatalf("Encoding not correct: %v", value)
+	}
+}
+
 func TestParser_ParseFloats__Single(t *testing.T) {
 	p := new(Parser)
 
@@ -3,6 +3,7 @@ package exifcommon
 import (
 	"errors"
 	"fmt"
+	"math"
 	"reflect"
 	"strconv"
 	"strings"
@@ -52,6 +53,9 @@ const (
 	// TypeRational describes an encoded list of rationals.
 	TypeRational TagTypePrimitive = 5
 
+	// TypeSignedByte describes an encoded list of signed bytes.
+	TypeSignedByte TagTypePrimitive = 6
+
 	// TypeUndefined describes an encoded value that has a complex/non-clearcut
 	// interpretation.
 	TypeUndefined TagTypePrimitive = 7
@@ -70,6 +74,18 @@ const (
 	// TypeDouble describes an encoded list of doubles.
 	TypeDouble TagTypePrimitive = 12
 
+	// TypeLong8 describes an encoded list of eight-byte unsigned integers.
+	// This is only defined for BigTIFF.
+	TypeLong8 TagTypePrimitive = 16
+
+	// TypeSignedLong8 describes an encoded list of eight-byte signed
+	// integers. This is only defined for BigTIFF.
+	TypeSignedLong8 TagTypePrimitive = 17
+
+	// TypeIfd8 describes an encoded list of eight-byte IFD offsets. It is
+	// decoded like TypeLong8. This is only defined for BigTIFF.
+	TypeIfd8 TagTypePrimitive = 18
+
 	// TypeAsciiNoNul is just a pseudo-type, for our own purposes.
 	TypeAsciiNoNul TagTypePrimitive = 0xf0
 )
@@ -79,24 +95,36 @@ func (typeType TagTypePrimitive) String() string {
 	return TypeNames[typeType]
 }
 
-// Size returns the size of one atomic unit of the type.
+// Name returns the name of the type (e.g. "SHORT") or "UNKNOWN" if it is not a
+// type that we know.
+func (tagType TagTypePrimitive) Name() string {
+	name, found := TypeNames[tagType]
+	if found == false {
+		return "UNKNOWN"
+	}
+
+	return name
+}
+
+// UnitSize returns the size of one atomic unit of the type in bytes. `found`
+// is false if it is not a type that we know. UNDEFINED values are treated as
+// bytes.
+func (tagType TagTypePrimitive) UnitSize() (size int, found bool) {
+	size, found = typeSizes[tagType]
+	return size, found
+}
+
+// Size returns the size of one atomic unit of the type. This will panic for
+// UNDEFINED and unknown types. See `UnitSize()`.
 func (tagType TagTypePrimitive) Size() int {
-	switch tagType {
-	case TypeByte, TypeAscii, TypeAsciiNoNul:
-		return 1
-	case TypeShort:
-		return 2
-	case TypeLong, TypeSignedLong, TypeFloat:
-		return 4
-	case TypeRational, TypeSignedRational, TypeDouble:
-		return 8
-	default:
+	size, found := tagType.UnitSize()
+	if found == false || tagType == TypeUndefined {
 		log.Panicf("can not determine tag-value size for type (%d): [%s]",
 			tagType,
 			TypeNames[tagType])
-		// Never called.
-		return 0
 	}
+
+	return size
 }
 
 // IsValid returns true if tagType is a valid type.
@@ -110,11 +138,21 @@ func (tagType TagTypePrimitive) IsValid() bool {
 		tagType == TypeShort ||
 		tagType == TypeLong ||
 		tagType == TypeRational ||
+		tagType == TypeSignedByte ||
 		tagType == TypeSignedLong ||
 		tagType == TypeSignedRational ||
 		tagType == TypeFloat ||
 		tagType == TypeDouble ||
-		tagType == TypeUndefined
+		tagType == TypeUndefined ||
+		tagType.IsBigTiffOnly() == true
+}
+
+// IsBigTiffOnly returns true if tagType is only defined for BigTIFF (LONG8,
+// SLONG8, and IFD8).
+func (tagType TagTypePrimitive) IsBigTiffOnly() bool {
+	return tagType == TypeLong8 ||
+		tagType == TypeSignedLong8 ||
+		tagType == TypeIfd8
 }
 
 var (
@@ -125,16 +163,39 @@ var (
 		TypeShort:          "SHORT",
 		TypeLong:           "LONG",
 		TypeRational:       "RATIONAL",
+		TypeSignedByte:     "SBYTE",
 		TypeUndefined:      "UNDEFINED",
 		TypeSignedLong:     "SLONG",
 		TypeSignedRational: "SRATIONAL",
 		TypeFloat:          "FLOAT",
 		TypeDouble:         "DOUBLE",
+		TypeLong8:          "LONG8",
+		TypeSignedLong8:    "SLONG8",
+		TypeIfd8:           "IFD8",
 
 		TypeAsciiNoNul: "_ASCII_NO_NUL",
 	}
 
 	typeNamesR = map[string]TagTypePrimitive{}
+
+	typeSizes = map[TagTypePrimitive]int{
+		TypeByte:           1,
+		TypeAscii:          1,
+		TypeShort:          2,
+		TypeLong:           4,
+		TypeRational:       8,
+		TypeSignedByte:     1,
+		TypeUndefined:      1,
+		TypeSignedLong:     4,
+		TypeSignedRational: 8,
+		TypeFloat:          4,
+		TypeDouble:         8,
+		TypeLong8:          8,
+		TypeSignedLong8:    8,
+		TypeIfd8:           8,
+
+		TypeAsciiNoNul: 1,
+	}
 )
 
 // Rational describes an unsigned rational value.
@@ -155,6 +216,100 @@ type SignedRational struct {
 	Denominator int32
 }
 
+// Float64 returns the value as a float. A zero denominator returns zero
+// rather than infinity (or NaN).
+func (r Rational) Float64() float64 {
+	if r.Denominator == 0 {
+		return 0
+	}
+
+	return float64(r.Numerator) / float64(r.Denominator)
+}
+
+// String returns the value as a fraction (e.g. "3/10"). It is not reduced.
+func (r Rational) String() string {
+	return fmt.Sprintf("%d/%d", r.Numerator, r.Denominator)
+}
+
+// Reduce returns the value in lowest terms (e.g. 10/100 becomes 1/10). A zero
+// denominator can't be reduced, so the value is returned as-is, and a zero
+// numerator becomes 0/1.
+func (r Rational) Reduce() Rational {
+	if r.Denominator == 0 {
+		return r
+	}
+
+	divisor := gcd(uint64(r.Numerator), uint64(r.Denominator))
+
+	return Rational{
+		Numerator:   uint32(uint64(r.Numerator) / divisor),
+		Denominator: uint32(uint64(r.Denominator) / divisor),
+	}
+}
+
+// Float64 returns the value as a float. A zero denominator returns zero
+// rather than infinity (or NaN).
+func (sr SignedRational) Float64() float64 {
+	if sr.Denominator == 0 {
+		return 0
+	}
+
+	return float64(sr.Numerator) / float64(sr.Denominator)
+}
+
+// String returns the value as a fraction (e.g. "-3/10"). It is not reduced.
+func (sr SignedRational) String() string {
+	return fmt.Sprintf("%d/%d", sr.Numerator, sr.Denominator)
+}
+
+// Reduce returns the value in lowest terms with the sign on the numerator
+// (e.g. 10/-100 becomes -1/10). A zero denominator can't be reduced, so the
+// value is returned as-is, and a zero numerator becomes 0/1. A value that
+// can't be represented once the sign has moved (e.g. -2147483648/-1) is also
+// returned as-is.
+func (sr SignedRational) Reduce() SignedRational {
+	if sr.Denominator == 0 {
+		return sr
+	}
+
+	numerator := int64(sr.Numerator)
+	denominator := int64(sr.Denominator)
+
+	if denominator < 0 {
+		numerator = -numerator
+		denominator = -denominator
+	}
+
+	absNumerator := numerator
+	if absNumerator < 0 {
+		absNumerator = -absNumerator
+	}
+
+	divisor := int64(gcd(uint64(absNumerator), uint64(denominator)))
+
+	numerator /= divisor
+	denominator /= divisor
+
+	if numerator > math.MaxInt32 || denominator > math.MaxInt32 {
+		return sr
+	}
+
+	return SignedRational{
+		Numerator:   int32(numerator),
+		Denominator: int32(denominator),
+	}
+}
+
+// gcd returns the greatest common divisor of the two values. `b` must not be
+// zero.
+func gcd(a, b uint64) uint64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+
+	return a
+}
+
 func isPrintableText(s string) bool {
 	for _, c := range s {
 		// unicode.IsPrint() returns false for newline characters.
@@ -199,7 +354,7 @@ func FormatFromType(value interface{}, justFirst bool) (phrase string, err error
 		}
 
 		return t, nil
-	case []uint16, []uint32, []int32, []float64, []float32:
+	case []int8, []uint16, []uint32, []int32, []uint64, []int64, []float64, []float32:
 		val := reflect.ValueOf(t)
 
 		if val.Len() == 0 {
@@ -348,6 +503,11 @@ func FormatFromBytes(rawBytes []byte, tagType TagTypePrimitive, justFirst bool,
 
 		value, err = parser.ParseRationals(rawBytes, unitCount, byteOrder)
 		log.PanicIf(err)
+	case TypeSignedByte:
+		var err error
+
+		value, err = parser.ParseSignedBytes(rawBytes, unitCount)
+		log.PanicIf(err)
 	case TypeSignedLong:
 		var err error
 
@@ -358,6 +518,16 @@ func FormatFromBytes(rawBytes []byte, tagType TagTypePrimitive, justFirst bool,
 
 		value, err = parser.ParseSignedRationals(rawBytes, unitCount, byteOrder)
 		log.PanicIf(err)
+	case TypeLong8, TypeIfd8:
+		var err error
+
+		value, err = parser.ParseLong8s(rawBytes, unitCount, byteOrder)
+		log.PanicIf(err)
+	case TypeSignedLong8:
+		var err error
+
+		value, err = parser.ParseSignedLong8s(rawBytes, unitCount, byteOrder)
+		log.PanicIf(err)
 	default:
 		// Affects only "unknown" values, in general.
 		log.Panicf("value of type [%s] can not be formatted into string", tagType.String())
@@ -422,6 +592,11 @@ func TranslateStringToType(tagType TagTypePrimitive, valueString string) (value
 			Numerator:   uint32(numerator),
 			Denominator: uint32(denominator),
 		}, nil
+	} else if tagType == TypeSignedByte {
+		n, err := strconv.ParseInt(valueString, 10, 8)
+		log.PanicIf(err)
+
+		return int8(n), nil
 	} else if tagType == TypeSignedLong {
 		n, err := strconv.ParseInt(valueString, 10, 32)
 		log.PanicIf(err)
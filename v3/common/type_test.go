@@ -43,6 +43,12 @@ func TestTypeRational_String(t *testing.T) {
 	}
 }
 
+func TestTypeSignedByte_String(t *testing.T) {
+	if TypeSignedByte.String() != "SBYTE" {
+		t.Fatalf("Type name not correct (signed byte): [%s]", TypeSignedByte.String())
+	}
+}
+
 func TestTypeSignedLong_String(t *testing.T) {
 	if TypeSignedLong.String() != "SLONG" {
 		t.Fatalf("Type name not correct (signed long): [%s]", TypeSignedLong.String())
@@ -103,6 +109,12 @@ func TestTypeRational_Size(t *testing.T) {
 	}
 }
 
+func TestTypeSignedByte_Size(t *testing.T) {
+	if TypeSignedByte.Size() != 1 {
+		t.Fatalf("Type size not correct (signed byte): (%d)", TypeSignedByte.Size())
+	}
+}
+
 func TestTypeSignedLong_Size(t *testing.T) {
 	if TypeSignedLong.Size() != 4 {
 		t.Fatalf("Type size not correct (signed long): (%d)", TypeSignedLong.Size())
@@ -127,6 +139,51 @@ func TestTypeDouble_Size(t *testing.T) {
 	}
 }
 
+func TestTagTypePrimitive_Name(t *testing.T) {
+	if TypeShort.Name() != "SHORT" {
+		t.Fatalf("Type name not correct (short): [%s]", TypeShort.Name())
+	} else if TagTypePrimitive(0x99).Name() != "UNKNOWN" {
+		t.Fatalf("Type name not correct (unknown): [%s]", TagTypePrimitive(0x99).Name())
+	}
+}
+
+func TestTagTypePrimitive_UnitSize(t *testing.T) {
+	size, found := TypeRational.UnitSize()
+	if found != true {
+		t.Fatalf("Expected size for rational.")
+	} else if size != 8 {
+		t.Fatalf("Type size not correct (rational): (%d)", size)
+	}
+
+	size, found = TypeUndefined.UnitSize()
+	if found != true {
+		t.Fatalf("Expected size for undefined.")
+	} else if size != 1 {
+		t.Fatalf("Type size not correct (undefined): (%d)", size)
+	}
+
+	size, found = TagTypePrimitive(0x99).UnitSize()
+	if found != false {
+		t.Fatalf("Expected no size for unknown type: (%d)", size)
+	}
+}
+
+func TestTagTypePrimitive_IsBigTiffOnly(t *testing.T) {
+	for _, tagType := range []TagTypePrimitive{TypeLong8, TypeSignedLong8, TypeIfd8} {
+		if tagType.IsBigTiffOnly() != true {
+			t.Fatalf("Expected BigTIFF-only type: [%s]", tagType)
+		} else if tagType.IsValid() != true {
+			t.Fatalf("Expected valid type: [%s]", tagType)
+		} else if tagType.Size() != 8 {
+			t.Fatalf("Type size not correct: [%s] (%d)", tagType, tagType.Size())
+		}
+	}
+
+	if TypeLong.IsBigTiffOnly() != false {
+		t.Fatalf("LONG is not BigTIFF-only.")
+	}
+}
+
 func TestFormat__Byte(t *testing.T) {
 	r := []byte{1, 2, 3, 4, 5, 6, 7, 8}
 
@@ -171,6 +228,17 @@ func TestFormat__Short(t *testing.T) {
 	}
 }
 
+func TestFormat__SignedByte(t *testing.T) {
+	r := []byte{0x01, 0xff}
+
+	s, err := FormatFromBytes(r, TypeSignedByte, false, TestDefaultByteOrder)
+	log.PanicIf(err)
+
+	if s != "[1 -1]" {
+		t.Fatalf("Format output not correct (signed bytes): [%s]", s)
+	}
+}
+
 func TestFormat__Long(t *testing.T) {
 	r := []byte{0, 0, 0, 1, 0, 0, 0, 2}
 
@@ -182,6 +250,17 @@ func TestFormat__Long(t *testing.T) {
 	}
 }
 
+func TestFormat__Long8(t *testing.T) {
+	r := []byte{0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 2}
+
+	s, err := FormatFromBytes(r, TypeLong8, false, TestDefaultByteOrder)
+	log.PanicIf(err)
+
+	if s != "[4294967296 2]" {
+		t.Fatalf("Format output not correct (long8s): [%s]", s)
+	}
+}
+
 func TestFormat__Float(t *testing.T) {
 	r := []byte{0x3f, 0x80, 0x00, 0x00,
 		0x40, 0x00, 0x00, 0x00}
@@ -435,3 +514,95 @@ func TestIsPrintableText_punctuationAndSymbols(t *testing.T) {
 		t.Fatalf("Printable text interpreted as nonprintable.")
 	}
 }
+
+func TestRational_Float64(t *testing.T) {
+	r := Rational{Numerator: 3, Denominator: 10}
+	if r.Float64() != 0.3 {
+		t.Fatalf("Float not correct: (%f)", r.Float64())
+	}
+
+	r = Rational{Numerator: 3, Denominator: 0}
+	if r.Float64() != 0 {
+		t.Fatalf("Float for zero denominator not correct: (%f)", r.Float64())
+	}
+
+	r = Rational{Numerator: 0, Denominator: 0}
+	if r.Float64() != 0 {
+		t.Fatalf("Float for 0/0 not correct: (%f)", r.Float64())
+	}
+}
+
+func TestRational_String(t *testing.T) {
+	r := Rational{Numerator: 10, Denominator: 100}
+	if r.String() != "10/100" {
+		t.Fatalf("String not correct: [%s]", r.String())
+	}
+}
+
+func TestRational_Reduce(t *testing.T) {
+	cases := []struct {
+		value    Rational
+		expected Rational
+	}{
+		{Rational{10, 100}, Rational{1, 10}},
+		{Rational{1, 640}, Rational{1, 640}},
+		{Rational{28, 7}, Rational{4, 1}},
+		{Rational{0, 5}, Rational{0, 1}},
+		{Rational{5, 0}, Rational{5, 0}},
+		{Rational{math.MaxUint32, math.MaxUint32}, Rational{1, 1}},
+	}
+
+	for _, c := range cases {
+		reduced := c.value.Reduce()
+		if reduced != c.expected {
+			t.Fatalf("Reduction of [%s] not correct: [%s] != [%s]", c.value, reduced, c.expected)
+		}
+	}
+}
+
+func TestSignedRational_Float64(t *testing.T) {
+	sr := SignedRational{Numerator: -3, Denominator: 10}
+	if sr.Float64() != -0.3 {
+		t.Fatalf("Float not correct: (%f)", sr.Float64())
+	}
+
+	sr = SignedRational{Numerator: 3, Denominator: -10}
+	if sr.Float64() != -0.3 {
+		t.Fatalf("Float for negative denominator not correct: (%f)", sr.Float64())
+	}
+
+	sr = SignedRational{Numerator: -3, Denominator: 0}
+	if sr.Float64() != 0 {
+		t.Fatalf("Float for zero denominator not correct: (%f)", sr.Float64())
+	}
+}
+
+func TestSignedRational_String(t *testing.T) {
+	sr := SignedRational{Numerator: -3, Denominator: 10}
+	if sr.String() != "-3/10" {
+		t.Fatalf("String not correct: [%s]", sr.String())
+	}
+}
+
+func TestSignedRational_Reduce(t *testing.T) {
+	cases := []struct {
+		value    SignedRational
+		expected SignedRational
+	}{
+		{SignedRational{10, 100}, SignedRational{1, 10}},
+		{SignedRational{-10, 100}, SignedRational{-1, 10}},
+		{SignedRational{10, -100}, SignedRational{-1, 10}},
+		{SignedRational{-10, -100}, SignedRational{1, 10}},
+		{SignedRational{0, -5}, SignedRational{0, 1}},
+		{SignedRational{-5, 0}, SignedRational{-5, 0}},
+		{SignedRational{math.MinInt32, 2}, SignedRational{math.MinInt32 / 2, 1}},
+		{SignedRational{math.MinInt32, -1}, SignedRational{math.MinInt32, -1}},
+	}
+
+	for _, c := range cases {
+		reduced := c.value.Reduce()
+		if reduced != c.expected {
+			t.Fatalf("Reduction of [%s] not correct: [%s] != [%s]", c.value, reduced, c.expected)
+		}
+	}
+}
@@ -13,6 +13,10 @@ var (
 	parser *Parser
 )
 
+var (
+	valueContextLogger = log.NewLogger("exifcommon.value_context")
+)
+
 var (
 	// ErrNotFarValue indicates that an offset-based lookup was attempted for a
 	// non-offset-based (embedded) value.
@@ -36,6 +40,14 @@ type ValueContext struct {
 
 	ifdPath string
 	tagId   uint16
+
+	// baseOffset is where, in the data, the value-offset is relative to. See
+	// SetBaseOffset().
+	baseOffset uint32
+
+	// inlineLength is how many bytes a value can have and still be stored in
+	// the entry itself. See SetInlineLength().
+	inlineLength int
 }
 
 // TODO(dustin): We can update newValueContext() to derive `valueOffset` itself (from `rawValueOffset`).
@@ -53,9 +65,32 @@ func NewValueContext(ifdPath string, tagId uint16, unitCount, valueOffset uint32
 
 		ifdPath: ifdPath,
 		tagId:   tagId,
+
+		inlineLength: 4,
 	}
 }
 
+// SetBaseOffset sets the position in the data that the value-offset is
+// relative to. This is zero (the start of the data) unless the tag is in a
+// block whose offsets have a different origin (e.g. a MakerNote whose offsets
+// are relative to the start of the MakerNote rather than to the TIFF header).
+func (vc *ValueContext) SetBaseOffset(baseOffset uint32) {
+	vc.baseOffset = baseOffset
+}
+
+// BaseOffset returns the position in the data that the value-offset is
+// relative to. See SetBaseOffset().
+func (vc *ValueContext) BaseOffset() uint32 {
+	return vc.baseOffset
+}
+
+// SetInlineLength sets how many bytes a value can have and still be stored in
+// the value-offset bytes of the entry itself. This is four unless the tag is
+// from a BigTIFF, whose entries have eight.
+func (vc *ValueContext) SetInlineLength(inlineLength int) {
+	vc.inlineLength = inlineLength
+}
+
 // SetUndefinedValueType sets the effective type if this is an unknown-type tag.
 func (vc *ValueContext) SetUndefinedValueType(tagType TagTypePrimitive) {
 	if vc.tagType != TypeUndefined {
@@ -111,7 +146,21 @@ func (vc *ValueContext) TagId() uint16 {
 func (vc *ValueContext) isEmbedded() bool {
 	tagType := vc.effectiveValueType()
 
-	return (tagType.Size() * int(vc.unitCount)) <= 4
+	return (tagType.Size() * int(vc.unitCount)) <= vc.inlineLength
+}
+
+// IsInline returns whether a value of the given type and our unit-count is
+// stored in the value-offset bytes of the entry itself (see
+// SetInlineLength()) rather than at an offset. The type is a parameter so that the effective type of an UNDEFINED
+// tag can be given, though UNDEFINED itself is treated as bytes. An error is
+// returned for unknown types.
+func (vc *ValueContext) IsInline(tagType TagTypePrimitive) (isInline bool, err error) {
+	size, found := tagType.UnitSize()
+	if found == false {
+		return false, log.Errorf("can not determine tag-value size for type (%d)", tagType)
+	}
+
+	return uint64(size)*uint64(vc.unitCount) <= uint64(vc.inlineLength), nil
 }
 
 // SizeInBytes returns the number of bytes that this value requires. The
@@ -149,26 +198,153 @@ func (vc *ValueContext) readRawEncoded() (rawBytes []byte, err error) {
 
 	tagType := vc.effectiveValueType()
 
-	unitSizeRaw := uint32(tagType.Size())
+	rawBytes, err = vc.EffectiveValueBytes(tagType)
+	if err != nil {
+		if err == ErrNotEnoughData {
+			return nil, err
+		}
+
+		log.Panic(err)
+	}
 
-	if vc.isEmbedded() == true {
-		byteLength := unitSizeRaw * vc.unitCount
-		return vc.rawValueOffset[:byteLength], nil
+	return rawBytes, nil
+}
+
+// EffectiveValueBytes returns the bytes of a value of the given type and our
+// unit-count, whether they are stored in the value-offset bytes themselves
+// (see `IsInline()`) or at the value-offset. The type is a parameter so that
+// the effective type of an UNDEFINED tag can be given. Inline bytes are
+// returned exactly as they were stored, in the byte-order of the data (e.g.
+// two inline SHORTs are not swapped), so they decode with ByteOrder() just
+// like bytes that were read from the value-offset. ErrNotEnoughData is
+// returned (unwrapped) if the value runs past the end of the data.
+func (vc *ValueContext) EffectiveValueBytes(tagType TagTypePrimitive) (rawBytes []byte, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	isInline, err := vc.IsInline(tagType)
+	log.PanicIf(err)
+
+	byteLength := vc.byteLength(tagType)
+
+	if isInline == true {
+		err := vc.checkBounds(0, byteLength, int64(len(vc.rawValueOffset)))
+		if err != nil {
+			return nil, err
+		}
+
+		rawBytes = make([]byte, byteLength)
+		copy(rawBytes, vc.rawValueOffset)
+
+		return rawBytes, nil
+	}
+
+	rawBytes, err = vc.readFar(byteLength)
+	if err != nil {
+		if err == ErrNotEnoughData {
+			return nil, err
+		}
+
+		log.Panic(err)
+	}
+
+	return rawBytes, nil
+}
+
+// CheckValueBounds returns ErrNotEnoughData (unwrapped) if a value of the
+// given type and our unit-count is stored at the value-offset and runs past
+// the end of the data. This is the same check that every read makes, but it
+// allocates nothing, so it can be made before handing the value to code that
+// would wrap the error (e.g. an undefined-type decoder).
+func (vc *ValueContext) CheckValueBounds(tagType TagTypePrimitive) (err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	isInline, err := vc.IsInline(tagType)
+	log.PanicIf(err)
+
+	if isInline == true {
+		return nil
+	}
+
+	dataLength, err := vc.rs.Seek(0, io.SeekEnd)
+	log.PanicIf(err)
+
+	return vc.checkBounds(vc.farPosition(), vc.byteLength(tagType), dataLength)
+}
+
+// byteLength returns the length of a value of the given type and our
+// unit-count. It's widened first so that an enormous unit-count can't wrap
+// around.
+func (vc *ValueContext) byteLength(tagType TagTypePrimitive) int64 {
+	unitSize, _ := tagType.UnitSize()
+	return int64(vc.unitCount) * int64(unitSize)
+}
+
+// farPosition returns the position of the value in the data: the value-offset
+// relative to the base offset.
+func (vc *ValueContext) farPosition() int64 {
+	return int64(vc.baseOffset) + int64(vc.valueOffset)
+}
+
+// readFar reads `byteLength` bytes from the value-offset (relative to the
+// base offset). ErrNotEnoughData is returned if the data ends before that.
+func (vc *ValueContext) readFar(byteLength int64) (rawBytes []byte, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	// Check before allocating, since the length comes from the data and
+	// might be enormous.
+
+	dataLength, err := vc.rs.Seek(0, io.SeekEnd)
+	log.PanicIf(err)
+
+	position := vc.farPosition()
+
+	err = vc.checkBounds(position, byteLength, dataLength)
+	if err != nil {
+		return nil, err
 	}
 
-	_, err = vc.rs.Seek(int64(vc.valueOffset), io.SeekStart)
+	_, err = vc.rs.Seek(position, io.SeekStart)
 	log.PanicIf(err)
 
-	rawBytes = make([]byte, vc.unitCount*unitSizeRaw)
+	rawBytes = make([]byte, byteLength)
 
 	_, err = io.ReadFull(vc.rs, rawBytes)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		valueContextLogger.Warningf(nil, "Value for tag (0x%04x) in IFD [%s] runs past the end of the data: OFFSET=(0x%08x) LENGTH=(%d)", vc.tagId, vc.ifdPath, position, len(rawBytes))
+		return nil, ErrNotEnoughData
+	}
+
 	log.PanicIf(err)
 
 	return rawBytes, nil
 }
 
+// checkBounds returns ErrNotEnoughData if a value of `length` bytes at
+// `offset` would run past `dataLength`. All value reads go through this.
+func (vc *ValueContext) checkBounds(offset int64, length int64, dataLength int64) error {
+	if offset+length > dataLength {
+		valueContextLogger.Warningf(nil, "Value for tag (0x%04x) in IFD [%s] at offset (0x%x) length (%d) exceeds data length (%d).", vc.tagId, vc.ifdPath, offset, length, dataLength)
+		return ErrNotEnoughData
+	}
+
+	return nil
+}
+
 // GetFarOffset returns the offset if the value is not embedded [within the
-// pointer itself] or an error if an embedded value.
+// pointer itself] or an error if an embedded value. The offset is as it was
+// stored, so it is relative to the base offset. See SetBaseOffset().
 func (vc *ValueContext) GetFarOffset() (offset uint32, err error) {
 	if vc.isEmbedded() == true {
 		return 0, ErrNotFarValue
@@ -245,6 +421,40 @@ func (vc *ValueContext) ReadBytes() (value []byte, err error) {
 	return value, nil
 }
 
+// ReadSignedBytes parses the encoded signed-byte-array from the value-context.
+func (vc *ValueContext) ReadSignedBytes() (value []int8, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	rawValue, err := vc.readRawEncoded()
+	log.PanicIf(err)
+
+	value, err = parser.ParseSignedBytes(rawValue, vc.unitCount)
+	log.PanicIf(err)
+
+	return value, nil
+}
+
+// ReadUndefined returns the raw bytes of an UNDEFINED-type value. Each unit is
+// one byte, so this is exactly UnitCount bytes. ErrNotEnoughData is returned
+// if the value runs past the end of the data.
+func (vc *ValueContext) ReadUndefined() (value []byte, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	if vc.tagType != TypeUndefined {
+		log.Panicf("not an undefined-type value: [%s]", vc.tagType)
+	}
+
+	return vc.EffectiveValueBytes(TypeUndefined)
+}
+
 // ReadAscii parses the encoded NUL-terminated ASCII string from the value-
 // context.
 func (vc *ValueContext) ReadAscii() (value string, err error) {
@@ -315,6 +525,42 @@ func (vc *ValueContext) ReadLongs() (value []uint32, err error) {
 	return value, nil
 }
 
+// ReadLong8s parses the list of encoded, eight-byte unsigned integers (LONG8
+// or IFD8) from the value-context.
+func (vc *ValueContext) ReadLong8s() (value []uint64, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	rawValue, err := vc.readRawEncoded()
+	log.PanicIf(err)
+
+	value, err = parser.ParseLong8s(rawValue, vc.unitCount, vc.byteOrder)
+	log.PanicIf(err)
+
+	return value, nil
+}
+
+// ReadSignedLong8s parses the list of encoded, eight-byte signed integers
+// (SLONG8) from the value-context.
+func (vc *ValueContext) ReadSignedLong8s() (value []int64, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	rawValue, err := vc.readRawEncoded()
+	log.PanicIf(err)
+
+	value, err = parser.ParseSignedLong8s(rawValue, vc.unitCount, vc.byteOrder)
+	log.PanicIf(err)
+
+	return value, nil
+}
+
 // ReadFloats parses the list of encoded, floats from the value-context.
 func (vc *ValueContext) ReadFloats() (value []float32, err error) {
 	defer func() {
@@ -433,6 +679,9 @@ func (vc *ValueContext) Values() (values interface{}, err error) {
 	} else if vc.tagType == TypeRational {
 		values, err = vc.ReadRationals()
 		log.PanicIf(err)
+	} else if vc.tagType == TypeSignedByte {
+		values, err = vc.ReadSignedBytes()
+		log.PanicIf(err)
 	} else if vc.tagType == TypeSignedLong {
 		values, err = vc.ReadSignedLongs()
 		log.PanicIf(err)
@@ -445,6 +694,12 @@ func (vc *ValueContext) Values() (values interface{}, err error) {
 	} else if vc.tagType == TypeDouble {
 		values, err = vc.ReadDoubles()
 		log.PanicIf(err)
+	} else if vc.tagType == TypeLong8 || vc.tagType == TypeIfd8 {
+		values, err = vc.ReadLong8s()
+		log.PanicIf(err)
+	} else if vc.tagType == TypeSignedLong8 {
+		values, err = vc.ReadSignedLong8s()
+		log.PanicIf(err)
 	} else if vc.tagType == TypeUndefined {
 		log.Panicf("will not parse undefined-type value")
 
@@ -6,6 +6,7 @@ import (
 	"reflect"
 	"testing"
 
+	"encoding/binary"
 	"io/ioutil"
 
 	"github.com/dsoprea/go-logging"
@@ -331,6 +332,116 @@ func TestValueContext_isEmbedded__False(t *testing.T) {
 	}
 }
 
+func TestValueContext_IsInline(t *testing.T) {
+	cases := []struct {
+		unitCount uint32
+		tagType   TagTypePrimitive
+		isInline  bool
+	}{
+		{4, TypeByte, true},
+		{5, TypeByte, false},
+		{4, TypeAscii, true},
+		{2, TypeShort, true},
+		{3, TypeShort, false},
+		{1, TypeLong, true},
+		{2, TypeLong, false},
+		{1, TypeRational, false},
+		{4, TypeUndefined, true},
+		{5, TypeUndefined, false},
+		{0, TypeDouble, true},
+	}
+
+	for _, c := range cases {
+		vc := NewValueContext(
+			"aa/bb",
+			0x1234,
+			c.unitCount,
+			0,
+			[]byte{0, 0, 0, 0},
+			nil,
+			c.tagType,
+			TestDefaultByteOrder)
+
+		isInline, err := vc.IsInline(c.tagType)
+		log.PanicIf(err)
+
+		if isInline != c.isInline {
+			t.Fatalf("IsInline() not correct for (%d) x [%s]: %v", c.unitCount, c.tagType, isInline)
+		}
+	}
+}
+
+func TestValueContext_SetInlineLength(t *testing.T) {
+	rawValueOffset := []byte{0, 0, 0, 1, 0, 0, 0, 100}
+
+	vc := NewValueContext(
+		"aa/bb",
+		0x1234,
+		1,
+		0,
+		rawValueOffset,
+		nil,
+		TypeRational,
+		TestDefaultByteOrder)
+
+	vc.SetInlineLength(8)
+
+	isInline, err := vc.IsInline(TypeRational)
+	log.PanicIf(err)
+
+	if isInline != true {
+		t.Fatalf("Expected a rational to be inline in eight bytes.")
+	}
+
+	value, err := vc.ReadRationals()
+	log.PanicIf(err)
+
+	if reflect.DeepEqual(value, []Rational{{Numerator: 1, Denominator: 100}}) != true {
+		t.Fatalf("Value not correct: %v", value)
+	}
+
+	_, err = vc.GetFarOffset()
+	if err != ErrNotFarValue {
+		t.Fatalf("Expected ErrNotFarValue: %v", err)
+	}
+}
+
+func TestValueContext_IsInline__LargeUnitCount(t *testing.T) {
+	vc := NewValueContext(
+		"aa/bb",
+		0x1234,
+		0x80000000,
+		0,
+		[]byte{0, 0, 0, 0},
+		nil,
+		TypeLong,
+		TestDefaultByteOrder)
+
+	isInline, err := vc.IsInline(TypeLong)
+	log.PanicIf(err)
+
+	if isInline != false {
+		t.Fatalf("Expected a large unit-count to not overflow into an inline value.")
+	}
+}
+
+func TestValueContext_IsInline__UnknownType(t *testing.T) {
+	vc := NewValueContext(
+		"aa/bb",
+		0x1234,
+		1,
+		0,
+		[]byte{0, 0, 0, 0},
+		nil,
+		TypeByte,
+		TestDefaultByteOrder)
+
+	_, err := vc.IsInline(TagTypePrimitive(99))
+	if err == nil {
+		t.Fatalf("Expected error for unknown type.")
+	}
+}
+
 func TestValueContext_readRawEncoded__IsEmbedded(t *testing.T) {
 	unitCount := uint32(4)
 
@@ -392,6 +503,268 @@ func TestValueContext_readRawEncoded__IsRelative(t *testing.T) {
 	}
 }
 
+func TestValueContext_EffectiveValueBytes__Inline(t *testing.T) {
+	rawValueOffset := []byte{0x11, 0x22, 0x33, 0x44}
+
+	vc := NewValueContext(
+		"aa/bb",
+		0x1234,
+		2,
+		0x11223344,
+		rawValueOffset,
+		nil,
+		TypeShort,
+		TestDefaultByteOrder)
+
+	recovered, err := vc.EffectiveValueBytes(TypeShort)
+	log.PanicIf(err)
+
+	if bytes.Equal(recovered, rawValueOffset) != true {
+		t.Fatalf("Inline value bytes not correct: %v", recovered)
+	}
+
+	// Only as many bytes as the value has are returned.
+	vc = NewValueContext(
+		"aa/bb",
+		0x1234,
+		1,
+		0x11223344,
+		rawValueOffset,
+		nil,
+		TypeShort,
+		TestDefaultByteOrder)
+
+	recovered, err = vc.EffectiveValueBytes(TypeShort)
+	log.PanicIf(err)
+
+	if bytes.Equal(recovered, rawValueOffset[:2]) != true {
+		t.Fatalf("Partial inline value bytes not correct: %v", recovered)
+	}
+}
+
+func TestValueContext_EffectiveValueBytes__InlineByteOrder(t *testing.T) {
+	cases := []struct {
+		byteOrder      binary.ByteOrder
+		tagType        TagTypePrimitive
+		unitCount      uint32
+		rawValueOffset []byte
+		expected       interface{}
+	}{
+		// Two SHORTs.
+		{binary.LittleEndian, TypeShort, 2, []byte{0x01, 0x00, 0x02, 0x00}, []uint16{1, 2}},
+		{binary.BigEndian, TypeShort, 2, []byte{0x00, 0x01, 0x00, 0x02}, []uint16{1, 2}},
+
+		// One LONG.
+		{binary.LittleEndian, TypeLong, 1, []byte{0x04, 0x03, 0x02, 0x01}, []uint32{0x01020304}},
+		{binary.BigEndian, TypeLong, 1, []byte{0x01, 0x02, 0x03, 0x04}, []uint32{0x01020304}},
+	}
+
+	for _, c := range cases {
+		vc := NewValueContext(
+			"aa/bb",
+			0x1234,
+			c.unitCount,
+			c.byteOrder.Uint32(c.rawValueOffset),
+			c.rawValueOffset,
+			nil,
+			c.tagType,
+			c.byteOrder)
+
+		recovered, err := vc.EffectiveValueBytes(c.tagType)
+		log.PanicIf(err)
+
+		if bytes.Equal(recovered, c.rawValueOffset) != true {
+			t.Fatalf("Inline value bytes not correct for [%s] %s: %v", c.tagType, c.byteOrder, recovered)
+		}
+
+		value, err := vc.Values()
+		log.PanicIf(err)
+
+		if reflect.DeepEqual(value, c.expected) != true {
+			t.Fatalf("Inline value not correct for [%s] %s: %v", c.tagType, c.byteOrder, value)
+		}
+	}
+}
+
+func TestValueContext_EffectiveValueBytes__External(t *testing.T) {
+	data := []byte{5, 6, 7, 8, 9, 10, 11, 12}
+
+	addressableData := []byte{1, 2, 3, 4}
+	addressableData = append(addressableData, data...)
+	sb := rifs.NewSeekableBufferWithBytes(addressableData)
+
+	vc := NewValueContext(
+		"aa/bb",
+		0x1234,
+		2,
+		4,
+		[]byte{0, 0, 0, 4},
+		sb,
+		TypeLong,
+		TestDefaultByteOrder)
+
+	recovered, err := vc.EffectiveValueBytes(TypeLong)
+	log.PanicIf(err)
+
+	if bytes.Equal(recovered, data) != true {
+		t.Fatalf("External value bytes not correct: %v", recovered)
+	}
+}
+
+func TestValueContext_SetBaseOffset(t *testing.T) {
+	// The value is at offset (4) relative to a block that starts at offset
+	// (6) of the data.
+	addressableData := []byte{0xee, 0xee, 0xee, 0xee, 0xee, 0xee, 0, 0, 0, 0, 0x11, 0x22, 0x33, 0x44, 0x55, 0x66, 0x77, 0x88}
+	sb := rifs.NewSeekableBufferWithBytes(addressableData)
+
+	vc := NewValueContext(
+		"aa/bb",
+		0x1234,
+		2,
+		4,
+		[]byte{0, 0, 0, 4},
+		sb,
+		TypeLong,
+		TestDefaultByteOrder)
+
+	vc.SetBaseOffset(6)
+
+	if vc.BaseOffset() != 6 {
+		t.Fatalf("Base offset not correct: (%d)", vc.BaseOffset())
+	}
+
+	value, err := vc.ReadLongs()
+	log.PanicIf(err)
+
+	expected := []uint32{0x11223344, 0x55667788}
+	if reflect.DeepEqual(value, expected) != true {
+		t.Fatalf("Value not correct: %v != %v", value, expected)
+	}
+
+	// The stored offset is unchanged.
+	farOffset, err := vc.GetFarOffset()
+	log.PanicIf(err)
+
+	if farOffset != 4 {
+		t.Fatalf("Far offset not correct: (%d)", farOffset)
+	}
+
+	// The base counts toward the bounds.
+	vc.SetBaseOffset(7)
+
+	_, err = vc.ReadLongs()
+	if log.Is(err, ErrNotEnoughData) == false {
+		t.Fatalf("Expected ErrNotEnoughData: %v", err)
+	}
+}
+
+func TestValueContext_EffectiveValueBytes__Undefined(t *testing.T) {
+	data := []byte{5, 6, 7, 8, 9}
+
+	addressableData := []byte{1, 2, 3, 4}
+	addressableData = append(addressableData, data...)
+	sb := rifs.NewSeekableBufferWithBytes(addressableData)
+
+	vc := NewValueContext(
+		"aa/bb",
+		0x1234,
+		5,
+		4,
+		[]byte{0, 0, 0, 4},
+		sb,
+		TypeUndefined,
+		TestDefaultByteOrder)
+
+	// UNDEFINED is sized like bytes.
+	recovered, err := vc.EffectiveValueBytes(TypeUndefined)
+	log.PanicIf(err)
+
+	if bytes.Equal(recovered, data) != true {
+		t.Fatalf("Undefined value bytes not correct: %v", recovered)
+	}
+}
+
+func TestValueContext_EffectiveValueBytes__PastEnd(t *testing.T) {
+	sb := rifs.NewSeekableBufferWithBytes([]byte{1, 2, 3, 4, 5, 6, 7, 8})
+
+	vc := NewValueContext(
+		"aa/bb",
+		0x1234,
+		0x40000000,
+		4,
+		[]byte{0, 0, 0, 4},
+		sb,
+		TypeLong,
+		TestDefaultByteOrder)
+
+	_, err := vc.EffectiveValueBytes(TypeLong)
+	if err != ErrNotEnoughData {
+		t.Fatalf("Expected ErrNotEnoughData: %v", err)
+	}
+}
+
+func TestValueContext_EffectiveValueBytes__UnknownType(t *testing.T) {
+	vc := NewValueContext(
+		"aa/bb",
+		0x1234,
+		1,
+		0,
+		[]byte{0, 0, 0, 0},
+		nil,
+		TypeByte,
+		TestDefaultByteOrder)
+
+	_, err := vc.EffectiveValueBytes(TagTypePrimitive(99))
+	if err == nil {
+		t.Fatalf("Expected error for unknown type.")
+	}
+}
+
+func TestValueContext_CheckValueBounds(t *testing.T) {
+	sb := rifs.NewSeekableBufferWithBytes([]byte{1, 2, 3, 4, 5, 6, 7, 8})
+
+	vc := NewValueContext(
+		"aa/bb",
+		0x1234,
+		2,
+		0,
+		[]byte{0, 0, 0, 0},
+		sb,
+		TypeLong,
+		TestDefaultByteOrder)
+
+	// Two LONGs at (0) end exactly at the end of the data.
+	err := vc.CheckValueBounds(TypeLong)
+	log.PanicIf(err)
+
+	// Two DOUBLEs don't fit.
+	err = vc.CheckValueBounds(TypeDouble)
+	if err != ErrNotEnoughData {
+		t.Fatalf("Expected ErrNotEnoughData: %v", err)
+	}
+
+	vc.SetBaseOffset(2)
+
+	err = vc.CheckValueBounds(TypeLong)
+	if err != ErrNotEnoughData {
+		t.Fatalf("Expected ErrNotEnoughData with the base offset: %v", err)
+	}
+
+	// Inline values are never out of bounds.
+	vc = NewValueContext(
+		"aa/bb",
+		0x1234,
+		2,
+		0xffffffff,
+		[]byte{0, 1, 0, 2},
+		sb,
+		TypeShort,
+		TestDefaultByteOrder)
+
+	err = vc.CheckValueBounds(TypeShort)
+	log.PanicIf(err)
+}
+
 func TestValueContext_Format__Byte(t *testing.T) {
 	unitCount := uint32(8)
 
@@ -832,14 +1205,13 @@ func TestValueContext_ReadBytes(t *testing.T) {
 	}
 }
 
-func TestValueContext_ReadAscii(t *testing.T) {
-	unitCount := uint32(8)
+func TestValueContext_ReadSignedBytes(t *testing.T) {
+	unitCount := uint32(6)
 
 	rawValueOffset := []byte{0, 0, 0, 4}
 	valueOffset := uint32(4)
 
-	data := []byte{'a', 'b', 'c', 'd', 'e', 'f', 'g', 0}
-
+	data := []byte{0x00, 0x01, 0x7f, 0x80, 0xfe, 0xff}
 	addressableData := []byte{0, 0, 0, 0}
 	addressableData = append(addressableData, data...)
 	sb := rifs.NewSeekableBufferWithBytes(addressableData)
@@ -851,24 +1223,73 @@ func TestValueContext_ReadAscii(t *testing.T) {
 		valueOffset,
 		rawValueOffset,
 		sb,
-		TypeAscii,
+		TypeSignedByte,
 		TestDefaultByteOrder)
 
-	value, err := vc.ReadAscii()
+	value, err := vc.ReadSignedBytes()
 	log.PanicIf(err)
 
-	if value != "abcdefg" {
-		t.Fatalf("ReadAscii not correct: [%s]", value)
+	expected := []int8{0, 1, 127, -128, -2, -1}
+	if reflect.DeepEqual(value, expected) != true {
+		t.Fatalf("ReadSignedBytes not correct: %v", value)
+	}
+
+	values, err := vc.Values()
+	log.PanicIf(err)
+
+	if reflect.DeepEqual(values, expected) != true {
+		t.Fatalf("Values not correct: %v", values)
 	}
 }
 
-func TestValueContext_ReadAsciiNoNul(t *testing.T) {
+func TestValueContext_ReadSignedBytes__Embedded(t *testing.T) {
+	vc := NewValueContext(
+		"aa/bb",
+		0x1234,
+		3,
+		0,
+		[]byte{0xff, 0x02, 0x80, 0x00},
+		nil,
+		TypeSignedByte,
+		TestDefaultByteOrder)
+
+	value, err := vc.ReadSignedBytes()
+	log.PanicIf(err)
+
+	expected := []int8{-1, 2, -128}
+	if reflect.DeepEqual(value, expected) != true {
+		t.Fatalf("ReadSignedBytes not correct: %v", value)
+	}
+}
+
+func TestValueContext_ReadSignedBytes__PastEnd(t *testing.T) {
+	addressableData := []byte{0, 0, 0, 0, 1, 2, 3, 4, 5}
+	sb := rifs.NewSeekableBufferWithBytes(addressableData)
+
+	vc := NewValueContext(
+		"aa/bb",
+		0x1234,
+		8,
+		4,
+		[]byte{0, 0, 0, 4},
+		sb,
+		TypeSignedByte,
+		TestDefaultByteOrder)
+
+	_, err := vc.ReadSignedBytes()
+	if log.Is(err, ErrNotEnoughData) == false {
+		t.Fatalf("Expected not-enough-data error: %v", err)
+	}
+}
+
+func TestValueContext_ReadAscii(t *testing.T) {
 	unitCount := uint32(8)
 
 	rawValueOffset := []byte{0, 0, 0, 4}
 	valueOffset := uint32(4)
 
-	data := []byte{'a', 'b', 'c', 'd', 'e', 'f', 'g', 'h'}
+	data := []byte{'a', 'b', 'c', 'd', 'e', 'f', 'g', 0}
+
 	addressableData := []byte{0, 0, 0, 0}
 	addressableData = append(addressableData, data...)
 	sb := rifs.NewSeekableBufferWithBytes(addressableData)
@@ -880,27 +1301,26 @@ func TestValueContext_ReadAsciiNoNul(t *testing.T) {
 		valueOffset,
 		rawValueOffset,
 		sb,
-		TypeAsciiNoNul,
+		TypeAscii,
 		TestDefaultByteOrder)
 
-	value, err := vc.ReadAsciiNoNul()
+	value, err := vc.ReadAscii()
 	log.PanicIf(err)
 
-	if value != "abcdefgh" {
-		t.Fatalf("ReadAsciiNoNul not correct: [%s]", value)
+	if value != "abcdefg" {
+		t.Fatalf("ReadAscii not correct: [%s]", value)
 	}
 }
 
-func TestValueContext_ReadShorts(t *testing.T) {
+func TestValueContext_ReadAscii__Embedded(t *testing.T) {
 	unitCount := uint32(4)
 
-	rawValueOffset := []byte{0, 0, 0, 4}
-	valueOffset := uint32(4)
+	rawValueOffset := []byte{'a', 'b', 'c', 0}
 
-	data := []byte{0, 1, 0, 2, 0, 3, 0, 4}
+	// Ignored, in this case.
+	valueOffset := uint32(0)
 
-	addressableData := []byte{0, 0, 0, 0}
-	addressableData = append(addressableData, data...)
+	addressableData := []byte{}
 	sb := rifs.NewSeekableBufferWithBytes(addressableData)
 
 	vc := NewValueContext(
@@ -910,24 +1330,25 @@ func TestValueContext_ReadShorts(t *testing.T) {
 		valueOffset,
 		rawValueOffset,
 		sb,
-		TypeShort,
+		TypeAscii,
 		TestDefaultByteOrder)
 
-	value, err := vc.ReadShorts()
+	value, err := vc.ReadAscii()
 	log.PanicIf(err)
 
-	if reflect.DeepEqual(value, []uint16{1, 2, 3, 4}) != true {
-		t.Fatalf("ReadShorts not correct: %v", value)
+	if value != "abc" {
+		t.Fatalf("ReadAscii not correct: [%s]", value)
 	}
 }
 
-func TestValueContext_ReadLongs(t *testing.T) {
-	unitCount := uint32(2)
+func TestValueContext_ReadAscii__PastEnd(t *testing.T) {
+	unitCount := uint32(8)
 
 	rawValueOffset := []byte{0, 0, 0, 4}
 	valueOffset := uint32(4)
 
-	data := []byte{0, 0, 0, 1, 0, 0, 0, 2}
+	// Three bytes short of the declared count.
+	data := []byte{'a', 'b', 'c', 'd', 0}
 
 	addressableData := []byte{0, 0, 0, 0}
 	addressableData = append(addressableData, data...)
@@ -940,7 +1361,157 @@ func TestValueContext_ReadLongs(t *testing.T) {
 		valueOffset,
 		rawValueOffset,
 		sb,
-		TypeLong,
+		TypeAscii,
+		TestDefaultByteOrder)
+
+	_, err := vc.ReadAscii()
+	if err == nil {
+		t.Fatalf("Expected error for value past the end of the data.")
+	} else if log.Is(err, ErrNotEnoughData) == false {
+		t.Fatalf("Error not expected: [%s]", err.Error())
+	}
+}
+
+func TestValueContext_ReadAscii__Empty(t *testing.T) {
+	// The value-offset points past the end of the data, but nothing should
+	// be read from there.
+	vc := NewValueContext(
+		"aa/bb",
+		0x1234,
+		0,
+		0xffffffff,
+		[]byte{'a', 'b', 'c', 0},
+		rifs.NewSeekableBufferWithBytes([]byte{}),
+		TypeAscii,
+		TestDefaultByteOrder)
+
+	value, err := vc.ReadAscii()
+	log.PanicIf(err)
+
+	if value != "" {
+		t.Fatalf("Expected an empty string: [%s]", value)
+	}
+
+	rawBytes, err := vc.EffectiveValueBytes(TypeAscii)
+	log.PanicIf(err)
+
+	if len(rawBytes) != 0 {
+		t.Fatalf("Expected no value bytes: %v", rawBytes)
+	}
+}
+
+func TestValueContext_ReadShorts__Empty(t *testing.T) {
+	vc := NewValueContext(
+		"aa/bb",
+		0x1234,
+		0,
+		0xffffffff,
+		[]byte{0x11, 0x22, 0x33, 0x44},
+		rifs.NewSeekableBufferWithBytes([]byte{}),
+		TypeShort,
+		TestDefaultByteOrder)
+
+	value, err := vc.ReadShorts()
+	log.PanicIf(err)
+
+	if value == nil || len(value) != 0 {
+		t.Fatalf("Expected an empty (non-nil) slice: %v", value)
+	}
+
+	values, err := vc.Values()
+	log.PanicIf(err)
+
+	if reflect.DeepEqual(values, []uint16{}) != true {
+		t.Fatalf("Values not correct: %v", values)
+	}
+
+	phrase, err := vc.Format()
+	log.PanicIf(err)
+
+	if phrase != "" {
+		t.Fatalf("Format not correct: [%s]", phrase)
+	}
+}
+
+func TestValueContext_ReadAsciiNoNul(t *testing.T) {
+	unitCount := uint32(8)
+
+	rawValueOffset := []byte{0, 0, 0, 4}
+	valueOffset := uint32(4)
+
+	data := []byte{'a', 'b', 'c', 'd', 'e', 'f', 'g', 'h'}
+	addressableData := []byte{0, 0, 0, 0}
+	addressableData = append(addressableData, data...)
+	sb := rifs.NewSeekableBufferWithBytes(addressableData)
+
+	vc := NewValueContext(
+		"aa/bb",
+		0x1234,
+		unitCount,
+		valueOffset,
+		rawValueOffset,
+		sb,
+		TypeAsciiNoNul,
+		TestDefaultByteOrder)
+
+	value, err := vc.ReadAsciiNoNul()
+	log.PanicIf(err)
+
+	if value != "abcdefgh" {
+		t.Fatalf("ReadAsciiNoNul not correct: [%s]", value)
+	}
+}
+
+func TestValueContext_ReadShorts(t *testing.T) {
+	unitCount := uint32(4)
+
+	rawValueOffset := []byte{0, 0, 0, 4}
+	valueOffset := uint32(4)
+
+	data := []byte{0, 1, 0, 2, 0, 3, 0, 4}
+
+	addressableData := []byte{0, 0, 0, 0}
+	addressableData = append(addressableData, data...)
+	sb := rifs.NewSeekableBufferWithBytes(addressableData)
+
+	vc := NewValueContext(
+		"aa/bb",
+		0x1234,
+		unitCount,
+		valueOffset,
+		rawValueOffset,
+		sb,
+		TypeShort,
+		TestDefaultByteOrder)
+
+	value, err := vc.ReadShorts()
+	log.PanicIf(err)
+
+	if reflect.DeepEqual(value, []uint16{1, 2, 3, 4}) != true {
+		t.Fatalf("ReadShorts not correct: %v", value)
+	}
+}
+
+func TestValueContext_ReadLongs(t *testing.T) {
+	unitCount := uint32(2)
+
+	rawValueOffset := []byte{0, 0, 0, 4}
+	valueOffset := uint32(4)
+
+	data := []byte{0, 0, 0, 1, 0, 0, 0, 2}
+
+	addressableData := []byte{0, 0, 0, 0}
+	addressableData = append(addressableData, data...)
+	sb := rifs.NewSeekableBufferWithBytes(addressableData)
+
+	vc := NewValueContext(
+		"aa/bb",
+		0x1234,
+		unitCount,
+		valueOffset,
+		rawValueOffset,
+		sb,
+		TypeLong,
 		TestDefaultByteOrder)
 
 	value, err := vc.ReadLongs()
@@ -951,6 +1522,122 @@ func TestValueContext_ReadLongs(t *testing.T) {
 	}
 }
 
+func TestValueContext_ReadShorts__Embedded(t *testing.T) {
+	unitCount := uint32(2)
+
+	rawValueOffset := []byte{0, 1, 0, 2}
+
+	// Ignored, in this case.
+	valueOffset := uint32(0)
+
+	sb := rifs.NewSeekableBufferWithBytes([]byte{})
+
+	vc := NewValueContext(
+		"aa/bb",
+		0x1234,
+		unitCount,
+		valueOffset,
+		rawValueOffset,
+		sb,
+		TypeShort,
+		TestDefaultByteOrder)
+
+	value, err := vc.ReadShorts()
+	log.PanicIf(err)
+
+	if reflect.DeepEqual(value, []uint16{1, 2}) != true {
+		t.Fatalf("ReadShorts not correct: %v", value)
+	}
+}
+
+func TestValueContext_ReadShorts__PastEnd(t *testing.T) {
+	unitCount := uint32(4)
+
+	rawValueOffset := []byte{0, 0, 0, 4}
+	valueOffset := uint32(4)
+
+	data := []byte{0, 1, 0, 2, 0, 3}
+
+	addressableData := []byte{0, 0, 0, 0}
+	addressableData = append(addressableData, data...)
+	sb := rifs.NewSeekableBufferWithBytes(addressableData)
+
+	vc := NewValueContext(
+		"aa/bb",
+		0x1234,
+		unitCount,
+		valueOffset,
+		rawValueOffset,
+		sb,
+		TypeShort,
+		TestDefaultByteOrder)
+
+	_, err := vc.ReadShorts()
+	if err == nil {
+		t.Fatalf("Expected error for value past the end of the data.")
+	} else if log.Is(err, ErrNotEnoughData) == false {
+		t.Fatalf("Error not expected: [%s]", err.Error())
+	}
+}
+
+func TestValueContext_ReadLongs__Embedded(t *testing.T) {
+	unitCount := uint32(1)
+
+	rawValueOffset := []byte{0, 0, 1, 2}
+
+	// Ignored, in this case.
+	valueOffset := uint32(0)
+
+	sb := rifs.NewSeekableBufferWithBytes([]byte{})
+
+	vc := NewValueContext(
+		"aa/bb",
+		0x1234,
+		unitCount,
+		valueOffset,
+		rawValueOffset,
+		sb,
+		TypeLong,
+		TestDefaultByteOrder)
+
+	value, err := vc.ReadLongs()
+	log.PanicIf(err)
+
+	if reflect.DeepEqual(value, []uint32{0x0102}) != true {
+		t.Fatalf("ReadLongs not correct: %v", value)
+	}
+}
+
+func TestValueContext_ReadLongs__PastEnd(t *testing.T) {
+	unitCount := uint32(2)
+
+	rawValueOffset := []byte{0, 0, 0, 4}
+	valueOffset := uint32(4)
+
+	data := []byte{0, 0, 0, 1}
+
+	addressableData := []byte{0, 0, 0, 0}
+	addressableData = append(addressableData, data...)
+	sb := rifs.NewSeekableBufferWithBytes(addressableData)
+
+	vc := NewValueContext(
+		"aa/bb",
+		0x1234,
+		unitCount,
+		valueOffset,
+		rawValueOffset,
+		sb,
+		TypeLong,
+		TestDefaultByteOrder)
+
+	_, err := vc.ReadLongs()
+	if err == nil {
+		t.Fatalf("Expected error for value past the end of the data.")
+	} else if log.Is(err, ErrNotEnoughData) == false {
+		t.Fatalf("Error not expected: [%s]", err.Error())
+	}
+}
+
 func TestValueContext_ReadFloats(t *testing.T) {
 	unitCount := uint32(2)
 
@@ -983,6 +1670,29 @@ func TestValueContext_ReadFloats(t *testing.T) {
 		}
 	}
 }
+
+func TestValueContext_ReadFloats__Embedded(t *testing.T) {
+	// A single float fits in the offset field itself.
+	rawValueOffset := []byte{0xdb, 0x0f, 0x49, 0x40}
+
+	vc := NewValueContext(
+		"aa/bb",
+		0x1234,
+		1,
+		binary.LittleEndian.Uint32(rawValueOffset),
+		rawValueOffset,
+		nil,
+		TypeFloat,
+		binary.LittleEndian)
+
+	value, err := vc.ReadFloats()
+	log.PanicIf(err)
+
+	if len(value) != 1 || value[0] != float32(3.14159265) {
+		t.Fatalf("ReadFloats not correct: %v", value)
+	}
+}
+
 func TestValueContext_ReadDoubles(t *testing.T) {
 	unitCount := uint32(2)
 
@@ -1017,6 +1727,52 @@ func TestValueContext_ReadDoubles(t *testing.T) {
 	}
 }
 
+func TestValueContext_ReadDoubles__LittleEndian(t *testing.T) {
+	// A double never fits in the offset field, even if there is only one.
+	data := []byte{0xf1, 0xd4, 0xc8, 0x53, 0xfb, 0x21, 0x09, 0x40}
+
+	addressableData := []byte{0, 0, 0, 0}
+	addressableData = append(addressableData, data...)
+	sb := rifs.NewSeekableBufferWithBytes(addressableData)
+
+	vc := NewValueContext(
+		"aa/bb",
+		0x1234,
+		1,
+		4,
+		[]byte{4, 0, 0, 0},
+		sb,
+		TypeDouble,
+		binary.LittleEndian)
+
+	value, err := vc.ReadDoubles()
+	log.PanicIf(err)
+
+	if len(value) != 1 || value[0] != 3.14159265 {
+		t.Fatalf("ReadDoubles not correct: %v", value)
+	}
+}
+
+func TestValueContext_ReadDoubles__PastEnd(t *testing.T) {
+	addressableData := []byte{0, 0, 0, 0, 1, 2, 3, 4, 5, 6, 7}
+	sb := rifs.NewSeekableBufferWithBytes(addressableData)
+
+	vc := NewValueContext(
+		"aa/bb",
+		0x1234,
+		1,
+		4,
+		[]byte{0, 0, 0, 4},
+		sb,
+		TypeDouble,
+		TestDefaultByteOrder)
+
+	_, err := vc.ReadDoubles()
+	if log.Is(err, ErrNotEnoughData) == false {
+		t.Fatalf("Expected not-enough-data error: %v", err)
+	}
+}
+
 func TestValueContext_ReadRationals(t *testing.T) {
 	unitCount := uint32(2)
 
@@ -1055,6 +1811,42 @@ func TestValueContext_ReadRationals(t *testing.T) {
 	}
 }
 
+func TestValueContext_ReadRationals__ZeroDenominator(t *testing.T) {
+	unitCount := uint32(1)
+
+	rawValueOffset := []byte{0, 0, 0, 4}
+	valueOffset := uint32(4)
+
+	data := []byte{0, 0, 0, 5, 0, 0, 0, 0}
+
+	addressableData := []byte{0, 0, 0, 0}
+	addressableData = append(addressableData, data...)
+	sb := rifs.NewSeekableBufferWithBytes(addressableData)
+
+	vc := NewValueContext(
+		"aa/bb",
+		0x1234,
+		unitCount,
+		valueOffset,
+		rawValueOffset,
+		sb,
+		TypeRational,
+		TestDefaultByteOrder)
+
+	value, err := vc.ReadRationals()
+	log.PanicIf(err)
+
+	// The raw value is preserved. It's up to the caller to decide what a zero
+	// denominator means.
+	expected := []Rational{
+		{Numerator: 5, Denominator: 0},
+	}
+
+	if reflect.DeepEqual(value, expected) != true {
+		t.Fatalf("ReadRationals not correct: %v", value)
+	}
+}
+
 func TestValueContext_ReadSignedLongs(t *testing.T) {
 	unitCount := uint32(2)
 
@@ -1115,6 +1907,40 @@ func TestValueContext_ReadSignedRationals(t *testing.T) {
 	}
 }
 
+func TestValueContext_ReadSignedRationals__ZeroDenominator(t *testing.T) {
+	unitCount := uint32(1)
+
+	rawValueOffset := []byte{0, 0, 0, 4}
+	valueOffset := uint32(4)
+
+	data := []byte{0xff, 0xff, 0xff, 0xfb, 0, 0, 0, 0}
+
+	addressableData := []byte{0, 0, 0, 0}
+	addressableData = append(addressableData, data...)
+	sb := rifs.NewSeekableBufferWithBytes(addressableData)
+
+	vc := NewValueContext(
+		"aa/bb",
+		0x1234,
+		unitCount,
+		valueOffset,
+		rawValueOffset,
+		sb,
+		TypeSignedRational,
+		TestDefaultByteOrder)
+
+	value, err := vc.ReadSignedRationals()
+	log.PanicIf(err)
+
+	expected := []SignedRational{
+		{Numerator: -5, Denominator: 0},
+	}
+
+	if reflect.DeepEqual(value, expected) != true {
+		t.Fatalf("ReadSignedRationals not correct: %v", value)
+	}
+}
+
 func TestValueContext_Values__Byte(t *testing.T) {
 	unitCount := uint32(8)
 
@@ -1306,3 +2132,108 @@ func TestValueContext_Values__SignedRational(t *testing.T) {
 		t.Fatalf("Values not correct (signed rationals): %v", value)
 	}
 }
+
+func TestValueContext_ReadUndefined__Embedded(t *testing.T) {
+	rawValueOffset := []byte{0x30, 0x32, 0x33, 0x30}
+
+	vc := NewValueContext(
+		"aa/bb",
+		0x9000,
+		4,
+		0,
+		rawValueOffset,
+		nil,
+		TypeUndefined,
+		TestDefaultByteOrder)
+
+	value, err := vc.ReadUndefined()
+	log.PanicIf(err)
+
+	if bytes.Equal(value, rawValueOffset) != true {
+		t.Fatalf("Value not correct: %v", value)
+	}
+}
+
+func TestValueContext_ReadUndefined__Far(t *testing.T) {
+	data := []byte{1, 2, 3, 4, 5, 6}
+
+	addressableData := []byte{0, 0, 0, 0}
+	addressableData = append(addressableData, data...)
+	sb := rifs.NewSeekableBufferWithBytes(addressableData)
+
+	vc := NewValueContext(
+		"aa/bb",
+		0x1234,
+		uint32(len(data)),
+		4,
+		[]byte{0, 0, 0, 4},
+		sb,
+		TypeUndefined,
+		TestDefaultByteOrder)
+
+	value, err := vc.ReadUndefined()
+	log.PanicIf(err)
+
+	if bytes.Equal(value, data) != true {
+		t.Fatalf("Value not correct: %v", value)
+	}
+}
+
+func TestValueContext_ReadUndefined__PastEnd(t *testing.T) {
+	addressableData := []byte{0, 0, 0, 0, 1, 2, 3, 4, 5}
+	sb := rifs.NewSeekableBufferWithBytes(addressableData)
+
+	vc := NewValueContext(
+		"aa/bb",
+		0x1234,
+		8,
+		4,
+		[]byte{0, 0, 0, 4},
+		sb,
+		TypeUndefined,
+		TestDefaultByteOrder)
+
+	_, err := vc.ReadUndefined()
+	if err != ErrNotEnoughData {
+		t.Fatalf("Expected not-enough-data error: %v", err)
+	}
+}
+
+func TestValueContext_ReadLongs__HugeUnitCount(t *testing.T) {
+	addressableData := []byte{0, 0, 0, 0, 1, 2, 3, 4}
+	sb := rifs.NewSeekableBufferWithBytes(addressableData)
+
+	// Four times this overflows a uint32. It must not wrap around to a small,
+	// satisfiable length.
+	vc := NewValueContext(
+		"aa/bb",
+		0x1234,
+		0x40000001,
+		4,
+		[]byte{0, 0, 0, 4},
+		sb,
+		TypeLong,
+		TestDefaultByteOrder)
+
+	_, err := vc.ReadLongs()
+	if log.Is(err, ErrNotEnoughData) == false {
+		t.Fatalf("Expected not-enough-data error: %v", err)
+	}
+}
+
+func TestValueContext_ReadShorts__ShortRawValueOffset(t *testing.T) {
+	vc := NewValueContext(
+		"aa/bb",
+		0x1234,
+		2,
+		0,
+		[]byte{0, 1},
+		nil,
+		TypeShort,
+		TestDefaultByteOrder)
+
+	_, err := vc.ReadShorts()
+	if log.Is(err, ErrNotEnoughData) == false {
+		t.Fatalf("Expected not-enough-data error: %v", err)
+	}
+}
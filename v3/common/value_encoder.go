@@ -44,6 +44,18 @@ func (ve *ValueEncoder) encodeBytes(value []uint8) (ed EncodedData, err error) {
 	return ed, nil
 }
 
+func (ve *ValueEncoder) encodeSignedBytes(value []int8) (ed EncodedData, err error) {
+	ed.Type = TypeSignedByte
+	ed.Encoded = make([]byte, len(value))
+	ed.UnitCount = uint32(len(value))
+
+	for i, b := range value {
+		ed.Encoded[i] = byte(b)
+	}
+
+	return ed, nil
+}
+
 func (ve *ValueEncoder) encodeAscii(value string) (ed EncodedData, err error) {
 	ed.Type = TypeAscii
 
@@ -233,6 +245,9 @@ func (ve *ValueEncoder) Encode(value interface{}) (ed EncodedData, err error) {
 	case []byte:
 		ed, err = ve.encodeBytes(t)
 		log.PanicIf(err)
+	case []int8:
+		ed, err = ve.encodeSignedBytes(t)
+		log.PanicIf(err)
 	case string:
 		ed, err = ve.encodeAscii(t)
 		log.PanicIf(err)
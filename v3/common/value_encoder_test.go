@@ -39,6 +39,35 @@ func TestValueEncoder_encodeBytes__Cycle(t *testing.T) {
 	}
 }
 
+func TestValueEncoder_encodeSignedBytes__Cycle(t *testing.T) {
+	byteOrder := TestDefaultByteOrder
+	ve := NewValueEncoder(byteOrder)
+
+	original := []int8{-128, -1, 0, 1, 127}
+
+	ed, err := ve.encodeSignedBytes(original)
+	log.PanicIf(err)
+
+	if ed.Type != TypeSignedByte {
+		t.Fatalf("IFD type not expected.")
+	}
+
+	expected := []byte{0x80, 0xff, 0x00, 0x01, 0x7f}
+
+	if reflect.DeepEqual(ed.Encoded, expected) != true {
+		t.Fatalf("Data not encoded correctly.")
+	} else if ed.UnitCount != 5 {
+		t.Fatalf("Unit-count not correct.")
+	}
+
+	recovered, err := parser.ParseSignedBytes(ed.Encoded, ed.UnitCount)
+	log.PanicIf(err)
+
+	if reflect.DeepEqual(recovered, original) != true {
+		t.Fatalf("Value not recovered correctly.")
+	}
+}
+
 func TestValueEncoder_encodeAscii__Cycle(t *testing.T) {
 	byteOrder := TestDefaultByteOrder
 	ve := NewValueEncoder(byteOrder)
@@ -427,6 +456,28 @@ func TestValueEncoder_Encode__Byte(t *testing.T) {
 	}
 }
 
+func TestValueEncoder_Encode__SignedByte(t *testing.T) {
+	byteOrder := TestDefaultByteOrder
+	ve := NewValueEncoder(byteOrder)
+
+	original := []int8{-2, 3}
+
+	ed, err := ve.Encode(original)
+	log.PanicIf(err)
+
+	if ed.Type != TypeSignedByte {
+		t.Fatalf("IFD type not expected.")
+	}
+
+	expected := []byte{0xfe, 0x03}
+
+	if reflect.DeepEqual(ed.Encoded, expected) != true {
+		t.Fatalf("Data not encoded correctly.")
+	} else if ed.UnitCount != 2 {
+		t.Fatalf("Unit-count not correct.")
+	}
+}
+
 func TestValueEncoder_Encode__Ascii(t *testing.T) {
 	byteOrder := TestDefaultByteOrder
 	ve := NewValueEncoder(byteOrder)
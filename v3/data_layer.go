@@ -1,12 +1,28 @@
 package exif
 
 import (
+	"bytes"
+	"errors"
 	"io"
 
+	"io/ioutil"
+
 	"github.com/dsoprea/go-logging"
 	"github.com/dsoprea/go-utility/v2/filesystem"
 )
 
+var (
+	// ErrExifTooLarge means that more data was given than we are willing to
+	// buffer.
+	ErrExifTooLarge = errors.New("EXIF data too large")
+)
+
+var (
+	// MaxBufferedExifLength is the most data that will be buffered from a
+	// plain `io.Reader`.
+	MaxBufferedExifLength = 64 * 1024 * 1024
+)
+
 type ExifBlobSeeker interface {
 	GetReadSeeker(initialOffset int64) (rs io.ReadSeeker, err error)
 }
@@ -16,21 +32,68 @@ type ExifBlobSeeker interface {
 // EXIF data).
 type ExifReadSeeker struct {
 	rs io.ReadSeeker
+
+	// baseOffset is where the EXIF data starts in `rs`.
+	baseOffset int64
+
+	// exifData is the EXIF data if we were given it as bytes. It is never
+	// written to.
+	exifData []byte
 }
 
+// NewExifReadSeeker returns an ExifReadSeeker for a stream that begins with
+// the EXIF data.
 func NewExifReadSeeker(rs io.ReadSeeker) *ExifReadSeeker {
+	return NewExifReadSeekerWithBaseOffset(rs, 0)
+}
+
+// NewExifReadSeekerWithBaseOffset returns an ExifReadSeeker for EXIF data that
+// starts somewhere in the middle of a larger stream (e.g. a whole image file).
+// Every IFD and value offset is relative to the EXIF header, so they are all
+// applied relative to `baseOffset`.
+func NewExifReadSeekerWithBaseOffset(rs io.ReadSeeker, baseOffset int64) *ExifReadSeeker {
 	return &ExifReadSeeker{
-		rs: rs,
+		rs:         rs,
+		baseOffset: baseOffset,
 	}
 }
 
+// NewExifReadSeekerWithBytes returns an ExifReadSeeker for EXIF data that is
+// already in memory. The data is read in place rather than copied, and it is
+// never written to, so it may be read-only (e.g. a memory-mapped file). The
+// caller must not change it while it is still being read.
 func NewExifReadSeekerWithBytes(exifData []byte) *ExifReadSeeker {
-	sb := rifs.NewSeekableBufferWithBytes(exifData)
-	edbs := NewExifReadSeeker(sb)
+	edbs := NewExifReadSeeker(bytes.NewReader(exifData))
+	edbs.exifData = exifData
 
 	return edbs
 }
 
+// NewExifReadSeekerWithReader buffers all of the EXIF data from the given
+// reader. Values are randomly accessed during the parse so there's no way to
+// avoid this for a reader that can't seek. ErrExifTooLarge is returned if the
+// stream is longer than MaxBufferedExifLength.
+func NewExifReadSeekerWithReader(r io.Reader) (edbs *ExifReadSeeker, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	lr := io.LimitReader(r, int64(MaxBufferedExifLength)+1)
+
+	exifData, err := ioutil.ReadAll(lr)
+	log.PanicIf(err)
+
+	if len(exifData) > MaxBufferedExifLength {
+		return nil, ErrExifTooLarge
+	}
+
+	edbs = NewExifReadSeekerWithBytes(exifData)
+
+	return edbs, nil
+}
+
 // Fork creates a new ReadSeeker instead that wraps a BouncebackReader to
 // maintain its own position in the stream.
 func (edbs *ExifReadSeeker) GetReadSeeker(initialOffset int64) (rs io.ReadSeeker, err error) {
@@ -43,8 +106,41 @@ func (edbs *ExifReadSeeker) GetReadSeeker(initialOffset int64) (rs io.ReadSeeker
 	br, err := rifs.NewBouncebackReader(edbs.rs)
 	log.PanicIf(err)
 
-	_, err = br.Seek(initialOffset, io.SeekStart)
+	rs = br
+	if edbs.baseOffset != 0 {
+		rs = &offsetReadSeeker{
+			rs:         br,
+			baseOffset: edbs.baseOffset,
+		}
+	}
+
+	_, err = rs.Seek(initialOffset, io.SeekStart)
 	log.PanicIf(err)
 
-	return br, nil
+	return rs, nil
+}
+
+// offsetReadSeeker presents a larger stream as if it started at `baseOffset`.
+type offsetReadSeeker struct {
+	rs         io.ReadSeeker
+	baseOffset int64
+}
+
+// Read reads from the underlying stream.
+func (ors *offsetReadSeeker) Read(p []byte) (n int, err error) {
+	return ors.rs.Read(p)
+}
+
+// Seek seeks relative to the base-offset.
+func (ors *offsetReadSeeker) Seek(offset int64, whence int) (position int64, err error) {
+	if whence == io.SeekStart {
+		offset += ors.baseOffset
+	}
+
+	position, err = ors.rs.Seek(offset, whence)
+	if err != nil {
+		return 0, err
+	}
+
+	return position - ors.baseOffset, nil
 }
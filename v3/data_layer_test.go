@@ -0,0 +1,43 @@
+package exif
+
+import (
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+	"github.com/dsoprea/go-utility/v2/filesystem"
+
+	"github.com/mschilli/go-exif/v3/common"
+)
+
+func TestNewExifReadSeekerWithBaseOffset(t *testing.T) {
+	exifData := getExifSimpleTestIbBytes()
+
+	prefix := []byte("some other container data")
+
+	data := make([]byte, 0)
+	data = append(data, prefix...)
+	data = append(data, exifData...)
+
+	sb := rifs.NewSeekableBufferWithBytes(data)
+	ebs := NewExifReadSeekerWithBaseOffset(sb, int64(len(prefix)))
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie := NewIfdEnumerate(im, ti, ebs, exifcommon.TestDefaultByteOrder)
+
+	index, err := ie.Collect(ExifDefaultFirstIfdOffset)
+	log.PanicIf(err)
+
+	ite, _, err := index.FindTag("IFD", 0x000b)
+	log.PanicIf(err)
+
+	value, err := ite.Value()
+	log.PanicIf(err)
+
+	if value.(string) != "asciivalue" {
+		t.Fatalf("Value not correct: [%v]", value)
+	}
+}
@@ -0,0 +1,118 @@
+package exif
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dsoprea/go-logging"
+)
+
+const (
+	// ExifDateTimeLayout is the layout of the EXIF date-time tags (e.g.
+	// DateTimeOriginal).
+	ExifDateTimeLayout = "2006:01:02 15:04:05"
+
+	// exifOffsetTimeLayout is the layout of the EXIF offset-time tags (e.g.
+	// OffsetTimeOriginal).
+	exifOffsetTimeLayout = "-07:00"
+)
+
+var (
+	// ErrDateTimeNotValid means that a date-time tag could not be parsed.
+	ErrDateTimeNotValid = errors.New("date-time not valid")
+)
+
+// asciiTagValue returns the value of an ASCII tag.
+func asciiTagValue(ite *IfdTagEntry) (value string, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	valueRaw, err := ite.Value()
+	log.PanicIf(err)
+
+	value, ok := valueRaw.(string)
+	if ok == false {
+		log.Panicf("tag (0x%04x) is not ASCII: [%s]", ite.TagId(), ite.TagType())
+	}
+
+	return value, nil
+}
+
+// isDateTimeUnset returns true if the value is empty or only has the spaces
+// (and colons) that some writers store when they don't know the time.
+func isDateTimeUnset(value string) bool {
+	return strings.Trim(value, " :\000") == ""
+}
+
+// parseExifDateTime parses an EXIF date-time phrase (e.g. "2018:11:30
+// 13:01:49"). If `offsetPhrase` is not empty (e.g. "+01:00"), it is used as
+// the timezone. Otherwise, the time is returned as UTC.
+func parseExifDateTime(dateTimePhrase, offsetPhrase string) (t time.Time, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	dateTimePhrase = strings.TrimRight(dateTimePhrase, "\000")
+
+	location := time.UTC
+
+	offsetPhrase = strings.TrimSpace(strings.TrimRight(offsetPhrase, "\000"))
+	if offsetPhrase != "" {
+		offsetTime, err := time.Parse(exifOffsetTimeLayout, offsetPhrase)
+		if err != nil {
+			ifdEnumerateLogger.Warningf(nil, "Time offset not valid and will be ignored: [%s]", offsetPhrase)
+		} else {
+			_, offsetSeconds := offsetTime.Zone()
+			location = time.FixedZone(fmt.Sprintf("UTC%s", offsetPhrase), offsetSeconds)
+		}
+	}
+
+	t, err = time.ParseInLocation(ExifDateTimeLayout, dateTimePhrase, location)
+	if err != nil {
+		ifdEnumerateLogger.Warningf(nil, "Date-time not valid: [%s]: %v", dateTimePhrase, err)
+		return time.Time{}, ErrDateTimeNotValid
+	}
+
+	return t, nil
+}
+
+// parseExifSubSecTime parses an EXIF sub-second phrase (e.g. "30"). The
+// digits are the fraction of a second after the decimal point, so "30" and
+// "300" are both 300 milliseconds. Digits beyond nanoseconds are dropped.
+// ErrDateTimeNotValid is returned (unwrapped) if the phrase has anything other
+// than digits.
+func parseExifSubSecTime(subSecPhrase string) (d time.Duration, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	subSecPhrase = strings.TrimRight(subSecPhrase, " \000")
+
+	for _, c := range subSecPhrase {
+		if c < '0' || c > '9' {
+			ifdEnumerateLogger.Warningf(nil, "Sub-second time not valid: [%s]", subSecPhrase)
+			return 0, ErrDateTimeNotValid
+		}
+	}
+
+	if len(subSecPhrase) > 9 {
+		subSecPhrase = subSecPhrase[:9]
+	}
+
+	subSecPhrase += strings.Repeat("0", 9-len(subSecPhrase))
+
+	nanoseconds, err := strconv.Atoi(subSecPhrase)
+	log.PanicIf(err)
+
+	return time.Duration(nanoseconds), nil
+}
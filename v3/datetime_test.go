@@ -0,0 +1,198 @@
+package exif
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/mschilli/go-exif/v3/common"
+)
+
+func TestParseExifDateTime(t *testing.T) {
+	timestamp, err := parseExifDateTime("2018:11:30 13:01:49", "")
+	log.PanicIf(err)
+
+	expected := time.Date(2018, 11, 30, 13, 1, 49, 0, time.UTC)
+	if timestamp.Equal(expected) == false {
+		t.Fatalf("Timestamp not correct: %v", timestamp)
+	} else if timestamp.Location() != time.UTC {
+		t.Fatalf("Location not correct: %v", timestamp.Location())
+	}
+}
+
+func TestParseExifDateTime__WithOffset(t *testing.T) {
+	timestamp, err := parseExifDateTime("2018:11:30 13:01:49", "+09:00")
+	log.PanicIf(err)
+
+	expected := time.Date(2018, 11, 30, 4, 1, 49, 0, time.UTC)
+	if timestamp.Equal(expected) == false {
+		t.Fatalf("Timestamp not correct: %v", timestamp)
+	}
+
+	_, offset := timestamp.Zone()
+	if offset != 9*60*60 {
+		t.Fatalf("Offset not correct: (%d)", offset)
+	}
+}
+
+func TestParseExifDateTime__NotValid(t *testing.T) {
+	_, err := parseExifDateTime("2018:11:30", "")
+	if err != ErrDateTimeNotValid {
+		t.Fatalf("Expected invalid date-time error: %v", err)
+	}
+}
+
+func TestParseExifSubSecTime(t *testing.T) {
+	cases := map[string]time.Duration{
+		"30":         300 * time.Millisecond,
+		"300":        300 * time.Millisecond,
+		"05":         50 * time.Millisecond,
+		"123456789":  123456789 * time.Nanosecond,
+		"1234567891": 123456789 * time.Nanosecond,
+		"00":         0,
+		"7\000":      700 * time.Millisecond,
+		"250 ":       250 * time.Millisecond,
+	}
+
+	for phrase, expected := range cases {
+		d, err := parseExifSubSecTime(phrase)
+		log.PanicIf(err)
+
+		if d != expected {
+			t.Fatalf("Sub-second time for [%s] not correct: %v != %v", phrase, d, expected)
+		}
+	}
+}
+
+func TestParseExifSubSecTime__NotValid(t *testing.T) {
+	for _, phrase := range []string{"3a", "-5", " 30", "1.5"} {
+		_, err := parseExifSubSecTime(phrase)
+		if err != ErrDateTimeNotValid {
+			t.Fatalf("Expected invalid date-time error for [%s]: %v", phrase, err)
+		}
+	}
+}
+
+func TestIsDateTimeUnset(t *testing.T) {
+	if isDateTimeUnset("    :  :     :  :  ") != true {
+		t.Fatalf("Placeholder should be unset.")
+	} else if isDateTimeUnset("") != true {
+		t.Fatalf("Empty should be unset.")
+	} else if isDateTimeUnset("2018:11:30 13:01:49") != false {
+		t.Fatalf("Timestamp should be set.")
+	}
+}
+
+func TestIfdIndex_DateTimeOriginal(t *testing.T) {
+	rawExif, err := SearchFileAndExtractExif(getTestImageFilepath())
+	log.PanicIf(err)
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	_, index, err := Collect(im, ti, rawExif)
+	log.PanicIf(err)
+
+	timestamp, err := index.DateTimeOriginal()
+	log.PanicIf(err)
+
+	expected := time.Date(2017, 12, 2, 8, 18, 50, 0, time.UTC)
+	if timestamp.Equal(expected) == false {
+		t.Fatalf("Timestamp not correct: %v", timestamp)
+	}
+}
+
+func TestIfdIndex_DateTimeOriginal__NotFound(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	_, index, err := Collect(im, ti, getExifSimpleTestIbBytes())
+	log.PanicIf(err)
+
+	_, err = index.DateTimeOriginal()
+	if err != ErrTagNotFound {
+		t.Fatalf("Expected tag-not-found error: %v", err)
+	}
+}
+
+func getTestSubSecTimeExifData(subSecPhrase string) []byte {
+	exifIfd := testBuilderIfd{
+		ii: exifcommon.IfdExifStandardIfdIdentity,
+		tags: []testBuilderTag{
+			{tagId: 0x9003, value: "2018:11:30 13:01:49"},
+			{tagId: 0x9291, value: subSecPhrase},
+		},
+	}
+
+	return getTestBuilderExifData(exifcommon.TestDefaultByteOrder, nil, exifIfd)
+}
+
+func TestIfdIndex_DateTimeOriginal__SubSecTime(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	_, index, err := Collect(im, ti, getTestSubSecTimeExifData("30"))
+	log.PanicIf(err)
+
+	timestamp, err := index.DateTimeOriginal()
+	log.PanicIf(err)
+
+	expected := time.Date(2018, 11, 30, 13, 1, 49, 300000000, time.UTC)
+	if timestamp.Equal(expected) == false {
+		t.Fatalf("Timestamp not correct: %v", timestamp)
+	}
+
+	d, err := index.SubSecTimeOriginal()
+	log.PanicIf(err)
+
+	if d != 300*time.Millisecond {
+		t.Fatalf("Sub-second time not correct: %v", d)
+	}
+}
+
+func TestIfdIndex_DateTimeOriginal__SubSecTimeNotValid(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	_, index, err := Collect(im, ti, getTestSubSecTimeExifData("xx"))
+	log.PanicIf(err)
+
+	// The sub-second time is ignored.
+
+	timestamp, err := index.DateTimeOriginal()
+	log.PanicIf(err)
+
+	expected := time.Date(2018, 11, 30, 13, 1, 49, 0, time.UTC)
+	if timestamp.Equal(expected) == false {
+		t.Fatalf("Timestamp not correct: %v", timestamp)
+	}
+
+	_, err = index.SubSecTimeOriginal()
+	if err != ErrDateTimeNotValid {
+		t.Fatalf("Expected invalid date-time error: %v", err)
+	}
+}
+
+func TestIfdIndex_SubSecTimeOriginal__NotFound(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	_, index, err := Collect(im, ti, getExifSimpleTestIbBytes())
+	log.PanicIf(err)
+
+	_, err = index.SubSecTimeOriginal()
+	if err != ErrTagNotFound {
+		t.Fatalf("Expected tag-not-found error: %v", err)
+	}
+}
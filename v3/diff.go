@@ -0,0 +1,222 @@
+package exif
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/dsoprea/go-logging"
+)
+
+var (
+	diffLogger = newPackageLogger("exif.diff")
+)
+
+// TagDiffKind describes how a tag differs between two trees.
+type TagDiffKind int
+
+const (
+	// TagDiffAdded means that the tag is only in the second tree.
+	TagDiffAdded TagDiffKind = iota + 1
+
+	// TagDiffRemoved means that the tag is only in the first tree.
+	TagDiffRemoved
+
+	// TagDiffChanged means that the tag is in both trees but with different
+	// values.
+	TagDiffChanged
+)
+
+// String returns the name of the kind.
+func (kind TagDiffKind) String() string {
+	switch kind {
+	case TagDiffAdded:
+		return "added"
+	case TagDiffRemoved:
+		return "removed"
+	case TagDiffChanged:
+		return "changed"
+	}
+
+	return fmt.Sprintf("TagDiffKind<%d>", int(kind))
+}
+
+// TagDiff describes one tag that differs between two trees.
+type TagDiff struct {
+	Kind TagDiffKind
+
+	// IfdPath is the path of the IFD that has the tag (see `Ifd.Path()`).
+	IfdPath string
+
+	TagId   uint16
+	TagName string
+
+	// Before is the value in the first tree. It is nil if the tag was added.
+	Before interface{}
+
+	// After is the value in the second tree. It is nil if the tag was
+	// removed.
+	After interface{}
+}
+
+// String returns a descriptive string.
+func (td TagDiff) String() string {
+	return fmt.Sprintf("TagDiff<KIND=[%s] IFD-PATH=[%s] TAG-ID=(0x%04x) TAG-NAME=[%s] BEFORE=[%v] AFTER=[%v]>", td.Kind, td.IfdPath, td.TagId, td.TagName, td.Before, td.After)
+}
+
+// tagDiffKey identifies a tag across two trees.
+type tagDiffKey struct {
+	ifdPath string
+	tagId   uint16
+}
+
+// tagDiffEntry is a tag and the enumerator that can read its value.
+type tagDiffEntry struct {
+	ie  *IfdEnumerate
+	ite *IfdTagEntry
+}
+
+// DiffExif collects the trees of both enumerators and returns the tags that
+// were added, removed, or changed from `a` to `b`. Tags are matched by the
+// path of their IFD (see `Ifd.Path()`) and their tag-ID. If an IFD has a tag
+// more than once, only the first is compared. The tags that point to child
+// IFDs are not compared since their values are only offsets. The diffs are
+// ordered by IFD-path and then tag-ID.
+//
+// Values are decoded with `IfdEnumerate.TagValue()`. If a value can't be
+// decoded, its raw bytes are compared (and reported) instead.
+func DiffExif(a, b *IfdEnumerate) (diffs []TagDiff, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	aEntries, err := diffEntries(a)
+	log.PanicIf(err)
+
+	bEntries, err := diffEntries(b)
+	log.PanicIf(err)
+
+	diffs = make([]TagDiff, 0)
+
+	for key, aEntry := range aEntries {
+		before := aEntry.value()
+
+		bEntry, found := bEntries[key]
+		if found == false {
+			td := TagDiff{
+				Kind:    TagDiffRemoved,
+				IfdPath: key.ifdPath,
+				TagId:   key.tagId,
+				TagName: aEntry.ite.TagName(),
+				Before:  before,
+			}
+
+			diffs = append(diffs, td)
+			continue
+		}
+
+		after := bEntry.value()
+
+		if reflect.DeepEqual(before, after) == false {
+			td := TagDiff{
+				Kind:    TagDiffChanged,
+				IfdPath: key.ifdPath,
+				TagId:   key.tagId,
+				TagName: aEntry.ite.TagName(),
+				Before:  before,
+				After:   after,
+			}
+
+			diffs = append(diffs, td)
+		}
+	}
+
+	for key, bEntry := range bEntries {
+		if _, found := aEntries[key]; found == true {
+			continue
+		}
+
+		td := TagDiff{
+			Kind:    TagDiffAdded,
+			IfdPath: key.ifdPath,
+			TagId:   key.tagId,
+			TagName: bEntry.ite.TagName(),
+			After:   bEntry.value(),
+		}
+
+		diffs = append(diffs, td)
+	}
+
+	sort.Slice(diffs, func(i, j int) bool {
+		if diffs[i].IfdPath != diffs[j].IfdPath {
+			return diffs[i].IfdPath < diffs[j].IfdPath
+		}
+
+		return diffs[i].TagId < diffs[j].TagId
+	})
+
+	return diffs, nil
+}
+
+// diffEntries collects the tree of the enumerator and returns the first entry
+// for each tag in each IFD.
+func diffEntries(ie *IfdEnumerate) (entries map[tagDiffKey]tagDiffEntry, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	collectIe, index, err := ie.collectFromHeader(nil)
+	log.PanicIf(err)
+
+	entries = make(map[tagDiffKey]tagDiffEntry)
+
+	for _, ifd := range index.Ifds {
+		ifdPath := ifd.Path()
+
+		for _, ite := range ifd.Entries() {
+			if ite.ChildIfdPath() != "" {
+				continue
+			}
+
+			key := tagDiffKey{
+				ifdPath: ifdPath,
+				tagId:   ite.TagId(),
+			}
+
+			if _, found := entries[key]; found == true {
+				continue
+			}
+
+			entries[key] = tagDiffEntry{
+				ie:  collectIe,
+				ite: ite,
+			}
+		}
+	}
+
+	return entries, nil
+}
+
+// value returns the decoded value, the raw bytes if it can't be decoded, or
+// nil if neither can be read.
+func (tde tagDiffEntry) value() interface{} {
+	value, err := tde.ie.TagValue(tde.ite)
+	if err == nil {
+		return value
+	}
+
+	diffLogger.Debugf(nil, "Could not decode tag (0x%04x) in IFD [%s] for the diff. Comparing raw bytes: %v", tde.ite.TagId(), tde.ite.IfdPath(), err)
+
+	rawBytes, err := tde.ite.GetRawBytes()
+	if err == nil {
+		return rawBytes
+	}
+
+	diffLogger.Warningf(nil, "Could not read tag (0x%04x) in IFD [%s] for the diff: %v", tde.ite.TagId(), tde.ite.IfdPath(), err)
+
+	return nil
+}
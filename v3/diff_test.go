@@ -0,0 +1,98 @@
+package exif
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"encoding/binary"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/mschilli/go-exif/v3/common"
+)
+
+func TestDiffExif(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	aExifData := getTestSegmentExifData(binary.BigEndian, "first make", exifcommon.IfdExifStandardIfdIdentity)
+	bExifData := getTestSegmentExifData(binary.LittleEndian, "second make", exifcommon.IfdGpsInfoStandardIfdIdentity)
+
+	a, _, err := NewIfdEnumerateWithBytes(im, ti, aExifData)
+	log.PanicIf(err)
+
+	b, _, err := NewIfdEnumerateWithBytes(im, ti, bExifData)
+	log.PanicIf(err)
+
+	diffs, err := DiffExif(a, b)
+	log.PanicIf(err)
+
+	actual := make([]string, len(diffs))
+	for i, td := range diffs {
+		actual[i] = fmt.Sprintf("%s %s 0x%04x %s %v %v", td.Kind, td.IfdPath, td.TagId, td.TagName, td.Before, td.After)
+	}
+
+	expected := []string{
+		"changed IFD 0x010f Make first make second make",
+		"added IFD 0x0131 Software <nil> some software",
+		"removed IFD 0x013b Artist some artist <nil>",
+		"removed IFD/Exif 0x9003 DateTimeOriginal 2020:01:02 03:04:05 <nil>",
+		"added IFD/GPSInfo 0x0000 GPSVersionID <nil> [2 2 0 0]",
+	}
+
+	if reflect.DeepEqual(actual, expected) != true {
+		for i, phrase := range actual {
+			fmt.Printf("(%d) %s\n", i, phrase)
+		}
+
+		t.Fatalf("Diffs not correct.")
+	}
+}
+
+func TestDiffExif__Same(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	a, _, err := NewIfdEnumerateWithBytes(im, ti, getTestExifData())
+	log.PanicIf(err)
+
+	b, _, err := NewIfdEnumerateWithBytes(im, ti, getTestExifData())
+	log.PanicIf(err)
+
+	diffs, err := DiffExif(a, b)
+	log.PanicIf(err)
+
+	if len(diffs) != 0 {
+		t.Fatalf("Expected no diffs: %v", diffs)
+	}
+}
+
+func TestDiffExif__ByteOrder(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	// The same tags in different byte-orders are not a difference.
+
+	aExifData := getTestSegmentExifData(binary.BigEndian, "some make", exifcommon.IfdExifStandardIfdIdentity)
+	bExifData := getTestSegmentExifData(binary.LittleEndian, "some make", exifcommon.IfdExifStandardIfdIdentity)
+
+	a, _, err := NewIfdEnumerateWithBytes(im, ti, aExifData)
+	log.PanicIf(err)
+
+	b, _, err := NewIfdEnumerateWithBytes(im, ti, bExifData)
+	log.PanicIf(err)
+
+	diffs, err := DiffExif(a, b)
+	log.PanicIf(err)
+
+	if len(diffs) != 0 {
+		t.Fatalf("Expected no diffs: %v", diffs)
+	}
+}
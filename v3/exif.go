@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"os"
 
 	"encoding/binary"
@@ -13,7 +14,7 @@ import (
 
 	"github.com/dsoprea/go-logging"
 
-	"github.com/dsoprea/go-exif/v3/common"
+	"github.com/mschilli/go-exif/v3/common"
 )
 
 const (
@@ -37,15 +38,27 @@ const (
 )
 
 var (
-	exifLogger = log.NewLogger("exif.exif")
+	exifLogger = newPackageLogger("exif.exif")
 
 	ExifBigEndianSignature    = [4]byte{'M', 'M', 0x00, 0x2a}
 	ExifLittleEndianSignature = [4]byte{'I', 'I', 0x2a, 0x00}
+
+	// BigTiffBigEndianSignature and BigTiffLittleEndianSignature are the
+	// byte-order marker and magic number at the front of a BigTIFF.
+	BigTiffBigEndianSignature    = [4]byte{'M', 'M', 0x00, 0x2b}
+	BigTiffLittleEndianSignature = [4]byte{'I', 'I', 0x2b, 0x00}
 )
 
 var (
 	ErrNoExif          = errors.New("no exif data")
 	ErrExifHeaderError = errors.New("exif header error")
+
+	// ErrBigTiff means that an offset or a count in a BigTIFF doesn't fit in
+	// 32 bits. BigTIFFs are otherwise read like any other TIFF, but offsets
+	// and counts are 32-bit throughout this package (e.g. `Ifd.Offset()` and
+	// `IfdTagEntry.UnitCount()`), so nothing past the first 4 GiB can be
+	// reached.
+	ErrBigTiff = errors.New("BigTIFF offset or count does not fit in 32 bits")
 )
 
 // SearchAndExtractExif searches for an EXIF blob in the byte-slice.
@@ -144,6 +157,12 @@ func searchAndExtractExifWithReaderWithDiscarded(r io.Reader) (rawExif []byte, d
 			log.Panic(err)
 		}
 
+		if isBigTiffHeader(window) == true {
+			// The BigTIFF header is longer than the window, so it's parsed
+			// by the caller.
+			break
+		}
+
 		_, err = ParseExifHeader(window)
 		if err != nil {
 			if log.Is(err, ErrNoExif) == true {
@@ -222,6 +241,10 @@ func SearchFileAndExtractExif(filepath string) (rawExif []byte, err error) {
 type ExifHeader struct {
 	ByteOrder      binary.ByteOrder
 	FirstIfdOffset uint32
+
+	// IsBigTiff is true if the data is a BigTIFF, whose header is
+	// BigTiffHeaderLength bytes long rather than ExifSignatureLength.
+	IsBigTiff bool
 }
 
 func (eh ExifHeader) String() string {
@@ -231,7 +254,9 @@ func (eh ExifHeader) String() string {
 // ParseExifHeader parses the bytes at the very top of the header.
 //
 // This will panic with ErrNoExif on any data errors so that we can double as
-// an EXIF-detection routine.
+// an EXIF-detection routine. A BigTIFF header is parsed as well, but it needs
+// BigTiffHeaderLength bytes. ErrBigTiff is returned (unwrapped) if its first
+// IFD is beyond the first 4 GiB.
 func ParseExifHeader(data []byte) (eh ExifHeader, err error) {
 	defer func() {
 		if state := recover(); state != nil {
@@ -255,17 +280,69 @@ func ParseExifHeader(data []byte) (eh ExifHeader, err error) {
 	} else if bytes.Equal(data[:4], ExifLittleEndianSignature[:]) == true {
 		eh.ByteOrder = binary.LittleEndian
 		exifLogger.Debugf(nil, "Byte-order is little-endian.")
+	} else if isBigTiffHeader(data) == true {
+		eh.ByteOrder = bigTiffByteOrder(data)
+		eh.IsBigTiff = true
+		exifLogger.Debugf(nil, "Data is BigTIFF.")
 	} else {
 		return eh, ErrNoExif
 	}
 
+	if eh.IsBigTiff == true {
+		if len(data) < BigTiffHeaderLength {
+			exifLogger.Warningf(nil, "Not enough data for BigTIFF header: (%d)", len(data))
+			return eh, ErrNoExif
+		}
+
+		firstIfdOffset := eh.ByteOrder.Uint64(data[8:16])
+		if firstIfdOffset > math.MaxUint32 {
+			exifLogger.Warningf(nil, "First-IFD offset (0x%016x) of BigTIFF does not fit in 32 bits.", firstIfdOffset)
+			return eh, ErrBigTiff
+		}
+
+		eh.FirstIfdOffset = uint32(firstIfdOffset)
+
+		return eh, nil
+	}
+
 	eh.FirstIfdOffset = eh.ByteOrder.Uint32(data[4:8])
 
 	return eh, nil
 }
 
-// Visit recursively invokes a callback for every tag.
-func Visit(rootIfdIdentity *exifcommon.IfdIdentity, ifdMapping *exifcommon.IfdMapping, tagIndex *TagIndex, exifData []byte, visitor TagVisitorFn, so *ScanOptions) (eh ExifHeader, furthestOffset uint32, err error) {
+// ParseTiffHeader reads the byte-order marker, the magic number, and the
+// offset of the root IFD from the TIFF header at the front of `data`. This is
+// the same as ParseExifHeader() but returns plain values. ErrNoExif is
+// returned (unwrapped) if the header is not valid and ErrBigTiff if the root
+// IFD of a BigTIFF is beyond the first 4 GiB.
+func ParseTiffHeader(data []byte) (byteOrder binary.ByteOrder, rootIfdOffset uint32, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	eh, err := ParseExifHeader(data)
+	if err != nil {
+		if err == ErrNoExif || err == ErrBigTiff {
+			return nil, 0, err
+		}
+
+		log.Panic(err)
+	}
+
+	return eh.ByteOrder, eh.FirstIfdOffset, nil
+}
+
+// NewIfdEnumerateWithBytes returns an IfdEnumerate for the given EXIF data
+// using the byte-order from the EXIF header at the front of it. The header is
+// also returned in order to provide the offset of the first IFD. ErrNoExif is
+// returned if the header is not valid and ErrBigTiff if the root IFD of a
+// BigTIFF is beyond the first 4 GiB.
+// The data is read in place and is never written to by the enumerator or by
+// anything that it returns, so it may be read-only (e.g. a memory-mapped
+// file). See NewExifReadSeekerWithBytes().
+func NewIfdEnumerateWithBytes(ifdMapping *exifcommon.IfdMapping, tagIndex *TagIndex, exifData []byte) (ie *IfdEnumerate, eh ExifHeader, err error) {
 	defer func() {
 		if state := recover(); state != nil {
 			err = log.Wrap(state.(error))
@@ -273,10 +350,101 @@ func Visit(rootIfdIdentity *exifcommon.IfdIdentity, ifdMapping *exifcommon.IfdMa
 	}()
 
 	eh, err = ParseExifHeader(exifData)
-	log.PanicIf(err)
+	if err != nil {
+		if err == ErrNoExif || err == ErrBigTiff {
+			return nil, eh, err
+		}
+
+		log.Panic(err)
+	}
 
 	ebs := NewExifReadSeekerWithBytes(exifData)
-	ie := NewIfdEnumerate(ifdMapping, tagIndex, ebs, eh.ByteOrder)
+	ie = NewIfdEnumerate(ifdMapping, tagIndex, ebs, eh.ByteOrder)
+
+	err = ie.ValidateHeader()
+	if err != nil {
+		if err == ErrNoExif {
+			return nil, eh, err
+		}
+
+		log.Panic(err)
+	}
+
+	return ie, eh, nil
+}
+
+// ValidateHeader checks that the data starts with a TIFF header that has the
+// byte-order of this enumerator and a first-IFD offset that falls within the
+// data. This is cheap, and running it before Collect() turns garbage data into
+// a clean error rather than a confusing one from the middle of the parse.
+// ErrNoExif is returned (unwrapped) if the header is not valid and ErrBigTiff
+// if the root IFD of a BigTIFF is beyond the first 4 GiB.
+// NewIfdEnumerateWithBytes() runs this automatically.
+func (ie *IfdEnumerate) ValidateHeader() (err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	rs, err := ie.ebs.GetReadSeeker(0)
+	log.PanicIf(err)
+
+	// A BigTIFF header is longer, but the data might be shorter than that.
+	headerData := make([]byte, BigTiffHeaderLength)
+
+	n, err := io.ReadAtLeast(rs, headerData, ExifSignatureLength)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		exifLogger.Warningf(nil, "Not enough data for EXIF header.")
+		return ErrNoExif
+	}
+
+	log.PanicIf(err)
+
+	eh, err := ParseExifHeader(headerData[:n])
+	if err != nil {
+		if err == ErrNoExif || err == ErrBigTiff {
+			return err
+		}
+
+		log.Panic(err)
+	}
+
+	if eh.ByteOrder != ie.byteOrder {
+		exifLogger.Warningf(nil, "EXIF header byte-order [%v] does not match the enumerator's byte-order [%v].", eh.ByteOrder, ie.byteOrder)
+		return ErrNoExif
+	}
+
+	size, err := rs.Seek(0, io.SeekEnd)
+	log.PanicIf(err)
+
+	// The first IFD has to follow the header and at least have room for its
+	// tag-count.
+	headerLength := ExifDefaultFirstIfdOffset
+	tagCountLength := int64(2)
+	if eh.IsBigTiff == true {
+		headerLength = BigTiffHeaderLength
+		tagCountLength = bigTiffOffsetSize
+	}
+
+	if eh.FirstIfdOffset < headerLength || int64(eh.FirstIfdOffset)+tagCountLength > size {
+		exifLogger.Warningf(nil, "First-IFD offset (0x%08x) is not within the EXIF data: (%d)", eh.FirstIfdOffset, size)
+		return ErrNoExif
+	}
+
+	return nil
+}
+
+// Visit recursively invokes a callback for every tag.
+func Visit(rootIfdIdentity *exifcommon.IfdIdentity, ifdMapping *exifcommon.IfdMapping, tagIndex *TagIndex, exifData []byte, visitor TagVisitorFn, so *ScanOptions) (eh ExifHeader, furthestOffset uint32, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	ie, eh, err := NewIfdEnumerateWithBytes(ifdMapping, tagIndex, exifData)
+	log.PanicIf(err)
 
 	_, err = ie.Scan(rootIfdIdentity, eh.FirstIfdOffset, visitor, so)
 	log.PanicIf(err)
@@ -294,12 +462,9 @@ func Collect(ifdMapping *exifcommon.IfdMapping, tagIndex *TagIndex, exifData []b
 		}
 	}()
 
-	eh, err = ParseExifHeader(exifData)
+	ie, eh, err := NewIfdEnumerateWithBytes(ifdMapping, tagIndex, exifData)
 	log.PanicIf(err)
 
-	ebs := NewExifReadSeekerWithBytes(exifData)
-	ie := NewIfdEnumerate(ifdMapping, tagIndex, ebs, eh.ByteOrder)
-
 	index, err = ie.Collect(eh.FirstIfdOffset)
 	log.PanicIf(err)
 
@@ -13,7 +13,7 @@ import (
 
 	"github.com/dsoprea/go-logging"
 
-	"github.com/dsoprea/go-exif/v3/common"
+	"github.com/mschilli/go-exif/v3/common"
 )
 
 func TestVisit(t *testing.T) {
@@ -395,6 +395,169 @@ func TestParseExifHeader(t *testing.T) {
 	}
 }
 
+func TestParseTiffHeader__LittleEndian(t *testing.T) {
+	data := []byte{'I', 'I', 0x2a, 0x00, 0x10, 0x00, 0x00, 0x00}
+
+	byteOrder, rootIfdOffset, err := ParseTiffHeader(data)
+	log.PanicIf(err)
+
+	if byteOrder != binary.LittleEndian {
+		t.Fatalf("Byte-order not correct: %v", byteOrder)
+	} else if rootIfdOffset != 0x10 {
+		t.Fatalf("Root IFD offset not correct: (0x%08x)", rootIfdOffset)
+	}
+}
+
+func TestParseTiffHeader__BigEndian(t *testing.T) {
+	data := []byte{'M', 'M', 0x00, 0x2a, 0x00, 0x00, 0x00, 0x08}
+
+	byteOrder, rootIfdOffset, err := ParseTiffHeader(data)
+	log.PanicIf(err)
+
+	if byteOrder != binary.BigEndian {
+		t.Fatalf("Byte-order not correct: %v", byteOrder)
+	} else if rootIfdOffset != 0x08 {
+		t.Fatalf("Root IFD offset not correct: (0x%08x)", rootIfdOffset)
+	}
+}
+
+func TestParseTiffHeader__InvalidMagic(t *testing.T) {
+	data := []byte{'M', 'M', 0x00, 0x2b, 0x00, 0x00, 0x00, 0x08}
+
+	_, _, err := ParseTiffHeader(data)
+	if err != ErrNoExif {
+		t.Fatalf("Expected no-EXIF error for bad magic: %v", err)
+	}
+
+	_, _, err = ParseTiffHeader(data[:4])
+	if err != ErrNoExif {
+		t.Fatalf("Expected no-EXIF error for short data: %v", err)
+	}
+}
+
+func TestNewIfdEnumerateWithBytes__LittleEndian(t *testing.T) {
+	testExifData := getTestExifData()
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, eh, err := NewIfdEnumerateWithBytes(im, ti, testExifData)
+	log.PanicIf(err)
+
+	if eh.ByteOrder != binary.LittleEndian {
+		t.Fatalf("Byte-order of EXIF header not correct.")
+	} else if ie.byteOrder != binary.LittleEndian {
+		t.Fatalf("Byte-order of enumerator not correct.")
+	}
+
+	index, err := ie.Collect(eh.FirstIfdOffset)
+	log.PanicIf(err)
+
+	if len(index.Ifds) != 5 {
+		t.Fatalf("IFD count not correct: (%d)", len(index.Ifds))
+	}
+}
+
+func TestNewIfdEnumerateWithBytes__BigEndian(t *testing.T) {
+	exifData := getExifSimpleTestIbBytes()
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, eh, err := NewIfdEnumerateWithBytes(im, ti, exifData)
+	log.PanicIf(err)
+
+	if eh.ByteOrder != binary.BigEndian {
+		t.Fatalf("Byte-order of EXIF header not correct.")
+	} else if ie.byteOrder != binary.BigEndian {
+		t.Fatalf("Byte-order of enumerator not correct.")
+	} else if eh.FirstIfdOffset != ExifDefaultFirstIfdOffset {
+		t.Fatalf("First IFD offset not correct: (%d)", eh.FirstIfdOffset)
+	}
+}
+
+func TestNewIfdEnumerateWithBytes__Invalid(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	exifData := []byte{'M', 'M', 0x00, 0x2b, 0, 0, 0, 8}
+
+	_, _, err = NewIfdEnumerateWithBytes(im, ti, exifData)
+	if err != ErrNoExif {
+		t.Fatalf("Expected no-EXIF error for bad magic: %v", err)
+	}
+
+	exifData = []byte{'M', 'I', 0x00, 0x2a, 0, 0, 0, 8}
+
+	_, _, err = NewIfdEnumerateWithBytes(im, ti, exifData)
+	if err != ErrNoExif {
+		t.Fatalf("Expected no-EXIF error for bad byte-order: %v", err)
+	}
+}
+
+func TestNewIfdEnumerateWithBytes__FirstIfdOffsetOutOfBounds(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	exifData := []byte{'M', 'M', 0x00, 0x2a, 0, 0, 0x10, 0}
+
+	_, _, err = NewIfdEnumerateWithBytes(im, ti, exifData)
+	if err != ErrNoExif {
+		t.Fatalf("Expected no-EXIF error for out-of-bounds first-IFD offset: %v", err)
+	}
+}
+
+func TestIfdEnumerate_ValidateHeader(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ebs := NewExifReadSeekerWithBytes(getTestExifData())
+	ie := NewIfdEnumerate(im, ti, ebs, binary.LittleEndian)
+
+	err = ie.ValidateHeader()
+	log.PanicIf(err)
+}
+
+func TestIfdEnumerate_ValidateHeader__Invalid(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	cases := []struct {
+		description string
+		data        []byte
+		byteOrder   binary.ByteOrder
+	}{
+		{"not EXIF", []byte("this is not EXIF data at all"), binary.BigEndian},
+		{"short", []byte{'M', 'M', 0x00}, binary.BigEndian},
+		{"bad magic", []byte{'M', 'M', 0x00, 0x2b, 0, 0, 0, 8, 0, 0}, binary.BigEndian},
+		{"wrong byte-order", getTestExifData(), binary.BigEndian},
+		{"first-IFD offset inside header", []byte{'M', 'M', 0x00, 0x2a, 0, 0, 0, 4, 0, 0}, binary.BigEndian},
+		{"first-IFD offset past end", []byte{'M', 'M', 0x00, 0x2a, 0, 0, 0, 9, 0, 0}, binary.BigEndian},
+	}
+
+	for _, c := range cases {
+		ebs := NewExifReadSeekerWithBytes(c.data)
+		ie := NewIfdEnumerate(im, ti, ebs, c.byteOrder)
+
+		err := ie.ValidateHeader()
+		if err != ErrNoExif {
+			t.Fatalf("Expected ErrNoExif for [%s]: %v", c.description, err)
+		}
+	}
+}
+
 func TestExif_BuildAndParseExifHeader(t *testing.T) {
 	headerBytes, err := BuildExifHeader(exifcommon.TestDefaultByteOrder, 0x11223344)
 	log.PanicIf(err)
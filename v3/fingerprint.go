@@ -0,0 +1,81 @@
+package exif
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/mschilli/go-exif/v3/common"
+)
+
+// Fingerprint returns a 64-bit FNV-1a hash of the tags in the given tree: the
+// IFD path, ID, type, and count of each tag and the bytes of its value, in the
+// order that Walk() visits the IFDs and then by tag-ID. Offsets aren't hashed
+// (the values of tags that point to child IFDs are skipped and the thumbnail's
+// data is hashed instead of its offset), so the same metadata gives the same
+// fingerprint no matter where it is stored or what image data surrounds it.
+// Values are hashed as stored, so the byte-order matters. Tags whose values run
+// past the end of the data are hashed without their values.
+// ErrValueBudgetExceeded is returned (unwrapped) if reading the values would
+// exceed the limit set with SetMaxValueBytes().
+func (ie *IfdEnumerate) Fingerprint(rootIfd *Ifd) (fingerprint uint64, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	h := fnv.New64a()
+
+	header := make([]byte, 12)
+
+	visitor := func(ifd *Ifd, depth int) error {
+		fqIfdPath := ifd.ifdIdentity.String()
+
+		// Writers don't all store the tags in the same order.
+		for _, ite := range ifd.SortedEntries() {
+			var value []byte
+
+			if ite.TagType().IsValid() == false || ite.ChildIfdPath() != "" || ite.TagId() == SubIfdsTagId {
+				// There is nothing to read, or the value is an offset.
+			} else if ite.IsThumbnailOffset() == true {
+				value, _ = ifd.Thumbnail()
+			} else {
+				var err error
+
+				value, err = ite.effectiveValueBytes()
+				if err == exifcommon.ErrNotEnoughData {
+					ifdEnumerateLogger.Warningf(nil, "Value for tag (0x%04x) in IFD [%s] could not be read and won't be fingerprinted.", ite.TagId(), fqIfdPath)
+					value = nil
+				} else if err != nil {
+					return err
+				}
+			}
+
+			h.Write([]byte(fqIfdPath))
+			h.Write([]byte{0})
+
+			binary.BigEndian.PutUint16(header[0:], ite.TagId())
+			binary.BigEndian.PutUint16(header[2:], uint16(ite.TagType()))
+			binary.BigEndian.PutUint32(header[4:], ite.UnitCount())
+			binary.BigEndian.PutUint32(header[8:], uint32(len(value)))
+
+			h.Write(header)
+			h.Write(value)
+		}
+
+		return nil
+	}
+
+	err = rootIfd.Walk(visitor)
+	if err != nil {
+		if err == ErrValueBudgetExceeded {
+			return 0, err
+		}
+
+		log.Panic(err)
+	}
+
+	return h.Sum64(), nil
+}
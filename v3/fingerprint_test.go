@@ -0,0 +1,85 @@
+package exif
+
+import (
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/mschilli/go-exif/v3/common"
+)
+
+func getTestFingerprint(exifData []byte) uint64 {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, eh, err := NewIfdEnumerateWithBytes(im, ti, exifData)
+	log.PanicIf(err)
+
+	index, err := ie.Collect(eh.FirstIfdOffset)
+	log.PanicIf(err)
+
+	fingerprint, err := ie.Fingerprint(index.RootIfd)
+	log.PanicIf(err)
+
+	return fingerprint
+}
+
+func TestIfdEnumerate_Fingerprint(t *testing.T) {
+	fingerprint1 := getTestFingerprint(getTestExifData())
+	fingerprint2 := getTestFingerprint(getTestExifData())
+
+	if fingerprint1 != fingerprint2 {
+		t.Fatalf("Fingerprint not stable: (0x%016x) != (0x%016x)", fingerprint1, fingerprint2)
+	} else if fingerprint1 == 0 {
+		t.Fatalf("Fingerprint is zero.")
+	}
+}
+
+func TestIfdEnumerate_Fingerprint__Reencoded(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	_, index, err := Collect(im, ti, getTestExifData())
+	log.PanicIf(err)
+
+	ib := NewIfdBuilderFromExistingChain(index.RootIfd)
+
+	ibe := NewIfdByteEncoder()
+
+	exifData, err := ibe.EncodeToExif(ib)
+	log.PanicIf(err)
+
+	// Trailing data that isn't referenced by the tree doesn't matter either.
+	exifData = append(exifData, 1, 2, 3, 4)
+
+	original := getTestFingerprint(getTestExifData())
+	reencoded := getTestFingerprint(exifData)
+
+	if reencoded != original {
+		t.Fatalf("Fingerprint changed when the layout changed: (0x%016x) != (0x%016x)", reencoded, original)
+	}
+}
+
+func TestIfdEnumerate_Fingerprint__Changed(t *testing.T) {
+	exifData := make([]byte, len(getTestExifData()))
+	copy(exifData, getTestExifData())
+
+	eh, err := ParseExifHeader(exifData)
+	log.PanicIf(err)
+
+	// Change the first character of the Model tag (the second entry in IFD0).
+	valueOffsetPosition := eh.FirstIfdOffset + 2 + IfdTagEntrySize + 8
+	valueOffset := eh.ByteOrder.Uint32(exifData[valueOffsetPosition:])
+	exifData[valueOffset] = 'X'
+
+	original := getTestFingerprint(getTestExifData())
+	changed := getTestFingerprint(exifData)
+
+	if changed == original {
+		t.Fatalf("Fingerprint did not change.")
+	}
+}
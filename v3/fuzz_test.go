@@ -0,0 +1,123 @@
+//go:build go1.18
+// +build go1.18
+
+package exif
+
+import (
+	"testing"
+
+	"io/ioutil"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/mschilli/go-exif/v3/common"
+	"github.com/mschilli/go-exif/v3/undefined"
+)
+
+const (
+	// fuzzMaxValueBytes is the value budget given to each enumerator that is
+	// fuzzed.
+	fuzzMaxValueBytes = 1024 * 1024
+)
+
+// isFuzzValueError returns true if `err` is one of the errors that reading a
+// value from arbitrary data is allowed to return.
+func isFuzzValueError(err error) bool {
+	if asKnownReadError(err) != nil {
+		return true
+	}
+
+	return err == exifcommon.ErrNotEnoughData ||
+		err == exifcommon.ErrUnhandledUndefinedTypedTag ||
+		err == exifundefined.ErrUnparseableValue
+}
+
+func FuzzIfdEnumerate(f *testing.F) {
+	f.Add(getTestExifData())
+	f.Add(getTestOrientationExifData(6))
+	f.Add(getTestIfdChainExifData(8+18, 8))
+
+	exifData, _ := getTestSharedChildIfdExifData()
+	f.Add(exifData)
+
+	f.Add(getTestSubIfdsExifData())
+	f.Add(getTestUnknownIfdExifData())
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		ie, eh, err := NewIfdEnumerateWithBytes(im, ti, data)
+		if err != nil {
+			return
+		}
+
+		ie.SetMaxValueBytes(fuzzMaxValueBytes)
+
+		co := &CollectOptions{
+			KeepUnknownIfds: true,
+		}
+
+		index, err := ie.CollectWithOptions(eh.FirstIfdOffset, co)
+		if err != nil {
+			if asKnownReadError(err) == nil {
+				t.Fatalf("Collect returned an unexpected error: %v", err)
+			}
+
+			return
+		}
+
+		if len(index.Ifds) > DefaultMaxIfdCount {
+			t.Fatalf("Too many IFDs: (%d)", len(index.Ifds))
+		}
+
+		// The walkers have to be able to describe whatever was collected.
+
+		rootIfd := index.RootIfd
+
+		rootIfd.DumpTags()
+		rootIfd.DumpTree()
+		rootIfd.FprintIfdTree(ioutil.Discard)
+
+		err = rootIfd.EnumerateTagsRecursively(func(ifd *Ifd, ite *IfdTagEntry) error {
+			return nil
+		})
+
+		if err != nil {
+			t.Fatalf("EnumerateTagsRecursively failed: %v", err)
+		}
+
+		_, err = rootIfd.Dump()
+		if err != nil {
+			t.Fatalf("Dump failed: %v", err)
+		}
+
+		// Every value that was read was counted against the budget, so the
+		// values that we read ourselves can't add up to more than it.
+
+		valueBytes := uint64(0)
+
+		for _, ifd := range index.Ifds {
+			for _, ite := range ifd.Entries() {
+				_, err := ite.Value()
+				if err != nil {
+					if isFuzzValueError(err) == false {
+						t.Fatalf("Value for tag (0x%04x) returned an unexpected error: %v", ite.TagId(), err)
+					}
+
+					continue
+				}
+
+				if ite.TagType().IsValid() == true {
+					valueBytes += valueByteLength(ite)
+				}
+			}
+		}
+
+		if valueBytes > fuzzMaxValueBytes {
+			t.Fatalf("Values exceeded the budget: (%d) > (%d)", valueBytes, fuzzMaxValueBytes)
+		}
+	})
+}
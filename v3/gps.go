@@ -3,18 +3,24 @@ package exif
 import (
 	"errors"
 	"fmt"
+	"math"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/dsoprea/go-logging"
 	"github.com/golang/geo/s2"
 
-	"github.com/dsoprea/go-exif/v3/common"
+	"github.com/mschilli/go-exif/v3/common"
 )
 
 var (
 	// ErrGpsCoordinatesNotValid means that some part of the geographic data was
 	// unparseable.
 	ErrGpsCoordinatesNotValid = errors.New("GPS coordinates not valid")
+
+	// ErrGpsTimestampNotValid means that the GPS date or time was unparseable.
+	ErrGpsTimestampNotValid = errors.New("GPS timestamp not valid")
 )
 
 // GpsDegrees is a high-level struct representing geographic data.
@@ -52,9 +58,9 @@ func NewGpsDegreesFromRationals(refValue string, rawCoordinate []exifcommon.Rati
 
 	gd = GpsDegrees{
 		Orientation: refValue[0],
-		Degrees:     float64(rawCoordinate[0].Numerator) / float64(rawCoordinate[0].Denominator),
-		Minutes:     float64(rawCoordinate[1].Numerator) / float64(rawCoordinate[1].Denominator),
-		Seconds:     float64(rawCoordinate[2].Numerator) / float64(rawCoordinate[2].Denominator),
+		Degrees:     rawCoordinate[0].Float64(),
+		Minutes:     rawCoordinate[1].Float64(),
+		Seconds:     rawCoordinate[2].Float64(),
 	}
 
 	return gd, nil
@@ -88,11 +94,68 @@ func (d GpsDegrees) Raw() []exifcommon.Rational {
 	}
 }
 
+// GpsField flags one of the optional fields of GpsInfo.
+type GpsField uint
+
+const (
+	// GpsFieldDestination flags `GpsInfo.DestLatitude` and
+	// `GpsInfo.DestLongitude`.
+	GpsFieldDestination GpsField = 1 << iota
+
+	// GpsFieldDestBearing flags `GpsInfo.DestBearing` and
+	// `GpsInfo.DestBearingRef`.
+	GpsFieldDestBearing
+
+	// GpsFieldImgDirection flags `GpsInfo.ImgDirection` and
+	// `GpsInfo.ImgDirectionRef`.
+	GpsFieldImgDirection
+
+	// GpsFieldSpeed flags `GpsInfo.Speed` and `GpsInfo.SpeedRef`.
+	GpsFieldSpeed
+
+	// GpsFieldTimestamp flags `GpsInfo.Timestamp`.
+	GpsFieldTimestamp
+)
+
 // GpsInfo encapsulates all of the geographic information in one place.
 type GpsInfo struct {
 	Latitude, Longitude GpsDegrees
 	Altitude            int
-	Timestamp           time.Time
+
+	// Timestamp is the UTC time from the GPSDateStamp and GPSTimeStamp tags.
+	// It is the zero time if either is missing or not valid, in which case
+	// GpsFieldTimestamp is not set in Present (a timestamp that isn't valid
+	// is also logged).
+	Timestamp time.Time
+
+	// DestLatitude and DestLongitude are the position of the destination (the
+	// GPSDestLatitude and GPSDestLongitude tags).
+	DestLatitude, DestLongitude GpsDegrees
+
+	// DestBearing is the direction to the destination in degrees (0 to
+	// 359.99). DestBearingRef is 'T' for true north or 'M' for magnetic north.
+	DestBearing    float64
+	DestBearingRef byte
+
+	// ImgDirection is the direction that the camera was pointing in degrees
+	// (0 to 359.99). ImgDirectionRef is 'T' for true north or 'M' for magnetic
+	// north.
+	ImgDirection    float64
+	ImgDirectionRef byte
+
+	// Speed is the speed of the receiver. SpeedRef is the unit: 'K' for
+	// kilometers per hour, 'M' for miles per hour, or 'N' for knots.
+	Speed    float64
+	SpeedRef byte
+
+	// Present flags which of the optional fields were found. Fields whose
+	// tags are missing (or don't have the expected shape) are left zero.
+	Present GpsField
+}
+
+// Has returns true if the given optional field was found.
+func (gi *GpsInfo) Has(field GpsField) bool {
+	return gi.Present&field != 0
 }
 
 // String returns a descriptive string.
@@ -115,3 +178,96 @@ func (gi *GpsInfo) S2CellId() s2.CellID {
 
 	return cellId
 }
+
+// parseGpsTimestamp combines the GPSDateStamp ("YYYY:MM:DD") and GPSTimeStamp
+// (hour, minute, and second rationals) values into a UTC time. The seconds
+// may be fractional.
+func parseGpsTimestamp(datePhrase string, timestampRaw []exifcommon.Rational) (timestamp time.Time, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	// Normalize the separators.
+	datePhrase = strings.ReplaceAll(datePhrase, "-", ":")
+
+	dateParts := strings.Split(datePhrase, ":")
+	if len(dateParts) != 3 {
+		return timestamp, ErrGpsTimestampNotValid
+	}
+
+	year, err1 := strconv.ParseUint(dateParts[0], 10, 16)
+	month, err2 := strconv.ParseUint(dateParts[1], 10, 8)
+	day, err3 := strconv.ParseUint(dateParts[2], 10, 8)
+
+	if err1 != nil || err2 != nil || err3 != nil {
+		return timestamp, ErrGpsTimestampNotValid
+	}
+
+	if len(timestampRaw) != 3 {
+		return timestamp, ErrGpsTimestampNotValid
+	}
+
+	if hasZeroDenominator(timestampRaw) == true {
+		return timestamp, ErrGpsTimestampNotValid
+	}
+
+	hour := int(timestampRaw[0].Float64())
+	minute := int(timestampRaw[1].Float64())
+
+	seconds := timestampRaw[2].Float64()
+	wholeSeconds := math.Floor(seconds)
+	nanoseconds := math.Round((seconds - wholeSeconds) * 1e9)
+
+	timestamp = time.Date(int(year), time.Month(month), int(day), hour, minute, int(wholeSeconds), int(nanoseconds), time.UTC)
+
+	return timestamp, nil
+}
+
+// gpsRationalsWithRef returns the value of a GPS tag that must be `count`
+// RATIONALs along with the first character of its ASCII reference tag (e.g.
+// 'N' or 'K'). Tags that are missing or that can't be read are treated as
+// missing (and the latter are logged).
+func gpsRationalsWithRef(ifd *Ifd, tagId, refTagId uint16, count int) (rationals []exifcommon.Rational, ref byte, found bool) {
+	ite, found := ifd.EntryByTagId(tagId)
+	if found == false {
+		return nil, 0, false
+	}
+
+	refIte, found := ifd.EntryByTagId(refTagId)
+	if found == false {
+		return nil, 0, false
+	}
+
+	value, err := ite.Value()
+	if err != nil {
+		ifdEnumerateLogger.Warningf(nil, "Could not read GPS tag (0x%04x) and it will be ignored: %v", tagId, err)
+		return nil, 0, false
+	}
+
+	refValue, err := refIte.Value()
+	if err != nil {
+		ifdEnumerateLogger.Warningf(nil, "Could not read GPS tag (0x%04x) and it will be ignored: %v", refTagId, err)
+		return nil, 0, false
+	}
+
+	rationals, ok := value.([]exifcommon.Rational)
+	if ok == false || len(rationals) != count {
+		ifdEnumerateLogger.Warningf(nil, "GPS tag (0x%04x) is not (%d) RATIONALs and will be ignored.", tagId, count)
+		return nil, 0, false
+	}
+
+	if hasZeroDenominator(rationals) == true {
+		ifdEnumerateLogger.Warningf(nil, "GPS tag (0x%04x) has a zero denominator and will be ignored.", tagId)
+		return nil, 0, false
+	}
+
+	refPhrase, ok := refValue.(string)
+	if ok == false || refPhrase == "" {
+		ifdEnumerateLogger.Warningf(nil, "GPS tag (0x%04x) has no reference and will be ignored.", tagId)
+		return nil, 0, false
+	}
+
+	return rationals, refPhrase[0], true
+}
@@ -4,10 +4,11 @@ import (
 	"math"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/dsoprea/go-logging"
 
-	"github.com/dsoprea/go-exif/v3/common"
+	"github.com/mschilli/go-exif/v3/common"
 )
 
 func TestNewGpsDegreesFromRationals(t *testing.T) {
@@ -59,3 +60,55 @@ func TestGpsDegrees_Raw(t *testing.T) {
 		t.Fatalf("GpsInfo not correctly encoded down to raw: %v\n", actual)
 	}
 }
+
+func TestParseGpsTimestamp(t *testing.T) {
+	timestampRaw := []exifcommon.Rational{
+		{Numerator: 14, Denominator: 1},
+		{Numerator: 30, Denominator: 1},
+		{Numerator: 12, Denominator: 1},
+	}
+
+	timestamp, err := parseGpsTimestamp("2018:04:29", timestampRaw)
+	log.PanicIf(err)
+
+	expected := time.Date(2018, 4, 29, 14, 30, 12, 0, time.UTC)
+	if timestamp.Equal(expected) != true {
+		t.Fatalf("Timestamp not correct: [%s]", timestamp)
+	}
+}
+
+func TestParseGpsTimestamp__FractionalSeconds(t *testing.T) {
+	timestampRaw := []exifcommon.Rational{
+		{Numerator: 14, Denominator: 1},
+		{Numerator: 30, Denominator: 1},
+		{Numerator: 1225, Denominator: 100},
+	}
+
+	timestamp, err := parseGpsTimestamp("2018-04-29", timestampRaw)
+	log.PanicIf(err)
+
+	expected := time.Date(2018, 4, 29, 14, 30, 12, 250000000, time.UTC)
+	if timestamp.Equal(expected) != true {
+		t.Fatalf("Timestamp not correct: [%s]", timestamp)
+	}
+}
+
+func TestParseGpsTimestamp__NotValid(t *testing.T) {
+	timestampRaw := []exifcommon.Rational{
+		{Numerator: 14, Denominator: 1},
+		{Numerator: 30, Denominator: 0},
+		{Numerator: 12, Denominator: 1},
+	}
+
+	_, err := parseGpsTimestamp("2018:04:29", timestampRaw)
+	if err != ErrGpsTimestampNotValid {
+		t.Fatalf("Expected invalid-timestamp error for zero denominator: %v", err)
+	}
+
+	timestampRaw[1].Denominator = 1
+
+	_, err = parseGpsTimestamp("2018:04", timestampRaw)
+	if err != ErrGpsTimestampNotValid {
+		t.Fatalf("Expected invalid-timestamp error for bad date: %v", err)
+	}
+}
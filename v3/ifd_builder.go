@@ -14,12 +14,12 @@ import (
 
 	"github.com/dsoprea/go-logging"
 
-	"github.com/dsoprea/go-exif/v3/common"
-	"github.com/dsoprea/go-exif/v3/undefined"
+	"github.com/mschilli/go-exif/v3/common"
+	"github.com/mschilli/go-exif/v3/undefined"
 )
 
 var (
-	ifdBuilderLogger = log.NewLogger("exif.ifd_builder")
+	ifdBuilderLogger = newPackageLogger("exif.ifd_builder")
 )
 
 var (
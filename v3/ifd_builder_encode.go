@@ -9,7 +9,7 @@ import (
 
 	"github.com/dsoprea/go-logging"
 
-	"github.com/dsoprea/go-exif/v3/common"
+	"github.com/mschilli/go-exif/v3/common"
 )
 
 const (
@@ -100,6 +100,10 @@ func newIfdDataAllocator(ifdDataAddressableOffset uint32) *ifdDataAllocator {
 	}
 }
 
+// Allocate appends the value to the data block and returns the offset that it
+// was written at. Odd-length values are followed by a pad byte so that the
+// next value (and the next IFD) stays word-aligned, as TIFF requires. The pad
+// is not part of the value or its unit-count.
 func (ida *ifdDataAllocator) Allocate(value []byte) (offset uint32, err error) {
 	_, err = ida.b.Write(value)
 	log.PanicIf(err)
@@ -107,6 +111,13 @@ func (ida *ifdDataAllocator) Allocate(value []byte) (offset uint32, err error) {
 	offset = ida.offset
 	ida.offset += uint32(len(value))
 
+	if len(value)%2 == 1 {
+		err = ida.b.WriteByte(0)
+		log.PanicIf(err)
+
+		ida.offset++
+	}
+
 	return offset, nil
 }
 
@@ -3,12 +3,13 @@ package exif
 import (
 	"bytes"
 	"fmt"
+	"reflect"
 	"strings"
 	"testing"
 
 	"github.com/dsoprea/go-logging"
 
-	"github.com/dsoprea/go-exif/v3/common"
+	"github.com/mschilli/go-exif/v3/common"
 )
 
 func Test_ByteWriter_writeAsBytes_uint8(t *testing.T) {
@@ -112,9 +113,9 @@ func Test_IfdDataAllocator_Allocate_InitialOffset1(t *testing.T) {
 	expected := uint32(addressableOffset + 0)
 	if offset != expected {
 		t.Fatalf("offset not bumped correctly (2): (%d) != (%d)", offset, expected)
-	} else if ida.NextOffset() != offset+uint32(3) {
+	} else if ida.NextOffset() != offset+uint32(4) {
 		t.Fatalf("position counter not advanced properly")
-	} else if bytes.Compare(ida.Bytes(), []byte{0x1, 0x2, 0x3}) != 0 {
+	} else if bytes.Compare(ida.Bytes(), []byte{0x1, 0x2, 0x3, 0x0}) != 0 {
 		t.Fatalf("buffer not correct after write (1)")
 	}
 
@@ -122,12 +123,13 @@ func Test_IfdDataAllocator_Allocate_InitialOffset1(t *testing.T) {
 	offset, err = ida.Allocate(data)
 	log.PanicIf(err)
 
-	expected = uint32(addressableOffset + 3)
+	// The first value had an odd length and was padded.
+	expected = uint32(addressableOffset + 4)
 	if offset != expected {
 		t.Fatalf("offset not bumped correctly (3): (%d) != (%d)", offset, expected)
-	} else if ida.NextOffset() != offset+uint32(3) {
+	} else if ida.NextOffset() != offset+uint32(4) {
 		t.Fatalf("position counter not advanced properly")
-	} else if bytes.Compare(ida.Bytes(), []byte{0x1, 0x2, 0x3, 0x4, 0x5, 0x6}) != 0 {
+	} else if bytes.Compare(ida.Bytes(), []byte{0x1, 0x2, 0x3, 0x0, 0x4, 0x5, 0x6, 0x0}) != 0 {
 		t.Fatalf("buffer not correct after write (2)")
 	}
 }
@@ -149,9 +151,9 @@ func Test_IfdDataAllocator_Allocate_InitialOffset2(t *testing.T) {
 	expected := uint32(addressableOffset + 0)
 	if offset != expected {
 		t.Fatalf("offset not bumped correctly (2): (%d) != (%d)", offset, expected)
-	} else if ida.NextOffset() != offset+uint32(3) {
+	} else if ida.NextOffset() != offset+uint32(4) {
 		t.Fatalf("position counter not advanced properly")
-	} else if bytes.Compare(ida.Bytes(), []byte{0x1, 0x2, 0x3}) != 0 {
+	} else if bytes.Compare(ida.Bytes(), []byte{0x1, 0x2, 0x3, 0x0}) != 0 {
 		t.Fatalf("buffer not correct after write (1)")
 	}
 
@@ -159,12 +161,13 @@ func Test_IfdDataAllocator_Allocate_InitialOffset2(t *testing.T) {
 	offset, err = ida.Allocate(data)
 	log.PanicIf(err)
 
-	expected = uint32(addressableOffset + 3)
+	// The first value had an odd length and was padded.
+	expected = uint32(addressableOffset + 4)
 	if offset != expected {
 		t.Fatalf("offset not bumped correctly (3): (%d) != (%d)", offset, expected)
-	} else if ida.NextOffset() != offset+uint32(3) {
+	} else if ida.NextOffset() != offset+uint32(4) {
 		t.Fatalf("position counter not advanced properly")
-	} else if bytes.Compare(ida.Bytes(), []byte{0x1, 0x2, 0x3, 0x4, 0x5, 0x6}) != 0 {
+	} else if bytes.Compare(ida.Bytes(), []byte{0x1, 0x2, 0x3, 0x0, 0x4, 0x5, 0x6, 0x0}) != 0 {
 		t.Fatalf("buffer not correct after write (2)")
 	}
 }
@@ -278,9 +281,9 @@ func Test_IfdByteEncoder_encodeTagToBytes_bytes_allocated(t *testing.T) {
 		t.Fatalf("no child-IFDs were expected to be allocated (1)")
 	} else if bytes.Compare(b.Bytes(), []byte{0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x05, 0x00, 0x00, 0x12, 0x34}) != 0 {
 		t.Fatalf("encoded tag-entry bytes not correct (1)")
-	} else if ida.NextOffset() != addressableOffset+uint32(5) {
+	} else if ida.NextOffset() != addressableOffset+uint32(6) {
 		t.Fatalf("allocation offset not expected (1)")
-	} else if bytes.Compare(ida.Bytes(), []byte{0x12, 0x34, 0x56, 0x78, 0x9A}) != 0 {
+	} else if bytes.Compare(ida.Bytes(), []byte{0x12, 0x34, 0x56, 0x78, 0x9A, 0x00}) != 0 {
 		t.Fatalf("allocated data not correct (1)")
 	}
 
@@ -295,14 +298,14 @@ func Test_IfdByteEncoder_encodeTagToBytes_bytes_allocated(t *testing.T) {
 		t.Fatalf("no child-IFDs were expected to be allocated (2)")
 	} else if bytes.Compare(b.Bytes(), []byte{
 		0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x05, 0x00, 0x00, 0x12, 0x34, // Tag 1
-		0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x05, 0x00, 0x00, 0x12, 0x39, // Tag 2
+		0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x05, 0x00, 0x00, 0x12, 0x3a, // Tag 2
 	}) != 0 {
 		t.Fatalf("encoded tag-entry bytes not correct (2)")
-	} else if ida.NextOffset() != addressableOffset+uint32(10) {
+	} else if ida.NextOffset() != addressableOffset+uint32(12) {
 		t.Fatalf("allocation offset not expected (2)")
 	} else if bytes.Compare(ida.Bytes(), []byte{
-		0x12, 0x34, 0x56, 0x78, 0x9A,
-		0xbc, 0xde, 0xf0, 0x12, 0x34,
+		0x12, 0x34, 0x56, 0x78, 0x9A, 0x00,
+		0xbc, 0xde, 0xf0, 0x12, 0x34, 0x00,
 	}) != 0 {
 		t.Fatalf("allocated data not correct (2)")
 	}
@@ -565,8 +568,8 @@ func Test_IfdByteEncoder_encodeIfdToBytes_simple(t *testing.T) {
 		t.Fatalf("One or more child IFDs were allocated but shouldn't have been: (%d)", len(childIfdSizes))
 	}
 
-	// The ASCII value plus the rational size.
-	expectedAllocatedSize := 11 + 8
+	// The ASCII value (plus its pad byte) plus the rational size.
+	expectedAllocatedSize := 11 + 1 + 8
 
 	if int(allocatedDataSize) != expectedAllocatedSize {
 		t.Fatalf("Allocated data size not correct: (%d)", allocatedDataSize)
@@ -582,7 +585,7 @@ func Test_IfdByteEncoder_encodeIfdToBytes_simple(t *testing.T) {
 		0x00, 0x0b, 0x00, 0x02, 0x00, 0x00, 0x00, 0x0b, 0x00, 0x00, 0x12, 0x34,
 		0x00, 0xff, 0x00, 0x03, 0x00, 0x00, 0x00, 0x01, 0x11, 0x22, 0x00, 0x00,
 		0x01, 0x00, 0x00, 0x04, 0x00, 0x00, 0x00, 0x01, 0x33, 0x44, 0x55, 0x66,
-		0x01, 0x3e, 0x00, 0x05, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x12, 0x3f,
+		0x01, 0x3e, 0x00, 0x05, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x12, 0x40,
 
 		// - Next IFD offset
 		0x00, 0x00, 0x00, 0x00,
@@ -592,6 +595,9 @@ func Test_IfdByteEncoder_encodeIfdToBytes_simple(t *testing.T) {
 		// - The one ASCII value
 		0x61, 0x73, 0x63, 0x69, 0x69, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x00,
 
+		// - The pad byte that keeps the next value word-aligned
+		0x00,
+
 		// - The one rational value
 		0x11, 0x11, 0x22, 0x22, 0x33, 0x33, 0x44, 0x44,
 	}
@@ -873,6 +879,137 @@ func ExampleIfdByteEncoder_EncodeToExif() {
 	// 1: IfdTagEntry<TAG-IFD-PATH=[IFD] TAG-ID=(0x0150) TAG-TYPE=[BYTE] UNIT-COUNT=(1)> [[17]]
 	// 2: IfdTagEntry<TAG-IFD-PATH=[IFD] TAG-ID=(0x00ff) TAG-TYPE=[SHORT] UNIT-COUNT=(1)> [[8755]]
 	// 3: IfdTagEntry<TAG-IFD-PATH=[IFD] TAG-ID=(0x0100) TAG-TYPE=[LONG] UNIT-COUNT=(1)> [[1146447479]]
-	// 4: IfdTagEntry<TAG-IFD-PATH=[IFD] TAG-ID=(0x013e) TAG-TYPE=[RATIONAL] UNIT-COUNT=(1)> [[{286335522 858997828}]]
-	// 5: IfdTagEntry<TAG-IFD-PATH=[IFD] TAG-ID=(0x9201) TAG-TYPE=[SRATIONAL] UNIT-COUNT=(1)> [[{286335522 858997828}]]
+	// 4: IfdTagEntry<TAG-IFD-PATH=[IFD] TAG-ID=(0x013e) TAG-TYPE=[RATIONAL] UNIT-COUNT=(1)> [[286335522/858997828]]
+	// 5: IfdTagEntry<TAG-IFD-PATH=[IFD] TAG-ID=(0x9201) TAG-TYPE=[SRATIONAL] UNIT-COUNT=(1)> [[286335522/858997828]]
+}
+
+func Test_IfdByteEncoder_EncodeToExif__OddAsciiPadding(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+	ib := NewIfdBuilder(im, ti, exifcommon.IfdStandardIfdIdentity, exifcommon.TestDefaultByteOrder)
+
+	// Five bytes with the NUL, so it's stored out-of-line with an odd length.
+	err = ib.AddStandard(0x010f, "abcd")
+	log.PanicIf(err)
+
+	// ReferenceBlackWhite (RATIONAL), which is allocated right behind it.
+	err = ib.AddStandard(0x013e, []exifcommon.Rational{{Numerator: 0x11112222, Denominator: 0x33334444}})
+	log.PanicIf(err)
+
+	ibe := NewIfdByteEncoder()
+
+	exifData, err := ibe.EncodeToExif(ib)
+	log.PanicIf(err)
+
+	_, index, err := Collect(im, ti, exifData)
+	log.PanicIf(err)
+
+	ifd := index.RootIfd
+
+	asciiIte := ifd.Entries()[0]
+	rationalIte := ifd.Entries()[1]
+
+	if asciiIte.UnitCount() != 5 {
+		t.Fatalf("ASCII unit-count not correct: (%d)", asciiIte.UnitCount())
+	} else if rationalIte.getValueOffset() != asciiIte.getValueOffset()+6 {
+		t.Fatalf("Value after the odd-length ASCII not padded: (%d) (%d)", asciiIte.getValueOffset(), rationalIte.getValueOffset())
+	} else if rationalIte.getValueOffset()%2 != 0 {
+		t.Fatalf("Value after the odd-length ASCII not word-aligned: (%d)", rationalIte.getValueOffset())
+	}
+
+	asciiValue, err := asciiIte.Value()
+	log.PanicIf(err)
+
+	if asciiValue.(string) != "abcd" {
+		t.Fatalf("ASCII value not correct: [%s]", asciiValue)
+	}
+
+	rationalValue, err := rationalIte.Value()
+	log.PanicIf(err)
+
+	expected := []exifcommon.Rational{{Numerator: 0x11112222, Denominator: 0x33334444}}
+	if reflect.DeepEqual(rationalValue, expected) != true {
+		t.Fatalf("Value after the ASCII not correct: %v", rationalValue)
+	}
+}
+
+func Test_IfdByteEncoder_EncodeToExif__RoundTrip(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	_, originalIndex, err := Collect(im, ti, getTestExifData())
+	log.PanicIf(err)
+
+	rootIb := NewIfdBuilderFromExistingChain(originalIndex.RootIfd)
+
+	ibe := NewIfdByteEncoder()
+
+	exifData, err := ibe.EncodeToExif(rootIb)
+	log.PanicIf(err)
+
+	_, recoveredIndex, err := Collect(im, ti, exifData)
+	log.PanicIf(err)
+
+	// The same IFDs, in the same places.
+
+	if len(recoveredIndex.Ifds) != len(originalIndex.Ifds) {
+		t.Fatalf("IFD count not correct: (%d) != (%d)", len(recoveredIndex.Ifds), len(originalIndex.Ifds))
+	}
+
+	for i, originalIfd := range originalIndex.Ifds {
+		recoveredIfd := recoveredIndex.Ifds[i]
+
+		if recoveredIfd.IfdIdentity().Equals(originalIfd.IfdIdentity()) != true {
+			t.Fatalf("IFD (%d) not correct: [%s] != [%s]", i, recoveredIfd.IfdIdentity(), originalIfd.IfdIdentity())
+		} else if len(recoveredIfd.Children()) != len(originalIfd.Children()) {
+			t.Fatalf("Child count for IFD [%s] not correct: (%d) != (%d)", originalIfd.IfdIdentity(), len(recoveredIfd.Children()), len(originalIfd.Children()))
+		}
+
+		// The same tags, with the same values. The builder writes the
+		// thumbnail tags first, so the order isn't compared, and only the
+		// offsets are expected to have changed.
+
+		originalEntries := originalIfd.Entries()
+		recoveredEntries := recoveredIfd.Entries()
+
+		if len(recoveredEntries) != len(originalEntries) {
+			t.Fatalf("Tag count for IFD [%s] not correct: (%d) != (%d)", originalIfd.IfdIdentity(), len(recoveredEntries), len(originalEntries))
+		}
+
+		for _, originalIte := range originalEntries {
+			recoveredIte, found := recoveredIfd.EntryByTagId(originalIte.TagId())
+			if found == false {
+				t.Fatalf("Tag %s not recovered.", originalIte)
+			} else if recoveredIte.TagType() != originalIte.TagType() || recoveredIte.UnitCount() != originalIte.UnitCount() {
+				t.Fatalf("Tag not correct: %s != %s", recoveredIte, originalIte)
+			}
+
+			if originalIte.ChildIfdPath() != "" || originalIte.IsThumbnailOffset() == true {
+				continue
+			}
+
+			originalValue, originalErr := originalIte.Value()
+			recoveredValue, recoveredErr := recoveredIte.Value()
+
+			if recoveredErr != originalErr {
+				t.Fatalf("Error for tag %s not correct: %v != %v", originalIte, recoveredErr, originalErr)
+			} else if reflect.DeepEqual(recoveredValue, originalValue) != true {
+				t.Fatalf("Value for tag %s not correct: %v != %v", originalIte, recoveredValue, originalValue)
+			}
+		}
+	}
+
+	originalThumbnailData, err := originalIndex.RootIfd.NextIfd().Thumbnail()
+	log.PanicIf(err)
+
+	recoveredThumbnailData, err := recoveredIndex.RootIfd.NextIfd().Thumbnail()
+	log.PanicIf(err)
+
+	if bytes.Equal(recoveredThumbnailData, originalThumbnailData) != true {
+		t.Fatalf("Thumbnail not recovered.")
+	}
 }
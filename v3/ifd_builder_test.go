@@ -9,9 +9,9 @@ import (
 	"testing"
 	"time"
 
-	"github.com/dsoprea/go-exif/v3/common"
-	"github.com/dsoprea/go-exif/v3/undefined"
 	"github.com/dsoprea/go-logging"
+	"github.com/mschilli/go-exif/v3/common"
+	"github.com/mschilli/go-exif/v3/undefined"
 )
 
 func TestIfdBuilder_Add(t *testing.T) {
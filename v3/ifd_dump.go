@@ -0,0 +1,272 @@
+package exif
+
+import (
+	"fmt"
+	"strings"
+
+	"encoding/json"
+	"encoding/xml"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/mschilli/go-exif/v3/common"
+	"github.com/mschilli/go-exif/v3/undefined"
+)
+
+// IfdTagEntryDump is a serializable description of one tag.
+type IfdTagEntryDump struct {
+	// TagId is the tag-ID.
+	TagId uint16 `json:"id"`
+
+	// TagName is the tag-name.
+	TagName string `json:"name"`
+
+	// TagTypeName is the type name.
+	TagTypeName string `json:"type_name"`
+
+	// UnitCount is the recorded number of units constituting the value.
+	UnitCount uint32 `json:"unit_count"`
+
+	// Value is the decoded value. This is nil if the value could not be
+	// decoded.
+	Value interface{} `json:"value"`
+
+	// ChildIfdPath is the IFD-path of the child IFD this tag represents (if it
+	// represents any).
+	ChildIfdPath string `json:"child_ifd_path,omitempty"`
+}
+
+// IfdDump is a serializable description of one IFD, its tags, its child IFDs,
+// and the rest of its IFD chain.
+type IfdDump struct {
+	// IfdPath is the fully-qualified IFD-path.
+	IfdPath string `json:"ifd_path"`
+
+	// Index is the position of the IFD in its chain.
+	Index int `json:"index"`
+
+	// Offset is the offset of the IFD in the EXIF blob.
+	Offset uint32 `json:"offset"`
+
+	// Tags are the tags in stream order.
+	Tags []IfdTagEntryDump `json:"tags"`
+
+	// Children are the child IFDs.
+	Children []*IfdDump `json:"children,omitempty"`
+
+	// Next is the next IFD in the chain.
+	Next *IfdDump `json:"next,omitempty"`
+}
+
+func (ifd *Ifd) dump(visited map[*Ifd]struct{}) (ifdDump *IfdDump, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	visited[ifd] = struct{}{}
+
+	ifdDump = &IfdDump{
+		IfdPath: ifd.ifdIdentity.String(),
+		Index:   ifd.ifdIdentity.Index(),
+		Offset:  ifd.offset,
+		Tags:    make([]IfdTagEntryDump, len(ifd.entries)),
+	}
+
+	for i, ite := range ifd.entries {
+		value, err := ite.Value()
+		if err != nil {
+			if log.Is(err, exifcommon.ErrUnhandledUndefinedTypedTag) == true || err == exifundefined.ErrUnparseableValue {
+				value = nil
+			} else {
+				// The value is unparseable, runs past the end of the data, or
+				// would exceed the enumerator's limit. This shouldn't stop the
+				// rest of the dump.
+				ifdEnumerateLogger.Warningf(nil, "Could not read value for tag [%s] (%04x) [%s]: %v", ite.IfdPath(), ite.TagId(), ite.TagName(), err)
+				value = nil
+			}
+		}
+
+		ifdDump.Tags[i] = IfdTagEntryDump{
+			TagId:        ite.TagId(),
+			TagName:      ite.TagName(),
+			TagTypeName:  ite.TagType().String(),
+			UnitCount:    ite.UnitCount(),
+			Value:        value,
+			ChildIfdPath: ite.ChildIfdPath(),
+		}
+	}
+
+	for _, childIfd := range ifd.children {
+		if _, found := visited[childIfd]; found == true {
+			continue
+		}
+
+		childDump, err := childIfd.dump(visited)
+		log.PanicIf(err)
+
+		ifdDump.Children = append(ifdDump.Children, childDump)
+	}
+
+	if ifd.nextIfd != nil {
+		if _, found := visited[ifd.nextIfd]; found == false {
+			ifdDump.Next, err = ifd.nextIfd.dump(visited)
+			log.PanicIf(err)
+		}
+	}
+
+	return ifdDump, nil
+}
+
+// Dump returns a serializable description of this IFD and everything below and
+// after it. Any IFD that is reachable more than once is only described the
+// first time.
+func (ifd *Ifd) Dump() (ifdDump *IfdDump, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	visited := make(map[*Ifd]struct{})
+
+	ifdDump, err = ifd.dump(visited)
+	log.PanicIf(err)
+
+	return ifdDump, nil
+}
+
+// DumpJson returns the `Dump()` description encoded as JSON.
+func (ifd *Ifd) DumpJson() (data []byte, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	ifdDump, err := ifd.Dump()
+	log.PanicIf(err)
+
+	data, err = json.MarshalIndent(ifdDump, "", "  ")
+	log.PanicIf(err)
+
+	return data, nil
+}
+
+// IfdTagEntryXml is the XML description of one tag. See `IfdTagEntryDump`.
+type IfdTagEntryXml struct {
+	TagId        string `xml:"id,attr"`
+	TagName      string `xml:"name,attr"`
+	TagTypeName  string `xml:"type,attr"`
+	UnitCount    uint32 `xml:"count,attr"`
+	Value        string `xml:"value,attr,omitempty"`
+	ChildIfdPath string `xml:"child_ifd_path,attr,omitempty"`
+}
+
+// IfdXml is the XML description of one IFD. Child IFDs are nested in it and
+// the next IFD in the chain is nested in its "next" element. See `IfdDump`.
+type IfdXml struct {
+	XMLName  xml.Name         `xml:"ifd"`
+	IfdPath  string           `xml:"path,attr"`
+	Index    int              `xml:"index,attr"`
+	Offset   uint32           `xml:"offset,attr"`
+	Tags     []IfdTagEntryXml `xml:"tag"`
+	Children []*IfdXml        `xml:"ifd"`
+	Next     *IfdXml          `xml:"next>ifd"`
+}
+
+// newIfdXml converts the dump to its XML description.
+func newIfdXml(ifdDump *IfdDump) (ifdXml *IfdXml, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	ifdXml = &IfdXml{
+		IfdPath: ifdDump.IfdPath,
+		Index:   ifdDump.Index,
+		Offset:  ifdDump.Offset,
+		Tags:    make([]IfdTagEntryXml, len(ifdDump.Tags)),
+	}
+
+	for i, tagDump := range ifdDump.Tags {
+		var phrase string
+		if tagDump.Value != nil {
+			phrase, err = formatXmlValue(tagDump.Value)
+			log.PanicIf(err)
+		}
+
+		ifdXml.Tags[i] = IfdTagEntryXml{
+			TagId:        fmt.Sprintf("0x%04x", tagDump.TagId),
+			TagName:      tagDump.TagName,
+			TagTypeName:  tagDump.TagTypeName,
+			UnitCount:    tagDump.UnitCount,
+			Value:        phrase,
+			ChildIfdPath: tagDump.ChildIfdPath,
+		}
+	}
+
+	for _, childDump := range ifdDump.Children {
+		childXml, err := newIfdXml(childDump)
+		log.PanicIf(err)
+
+		ifdXml.Children = append(ifdXml.Children, childXml)
+	}
+
+	if ifdDump.Next != nil {
+		ifdXml.Next, err = newIfdXml(ifdDump.Next)
+		log.PanicIf(err)
+	}
+
+	return ifdXml, nil
+}
+
+// formatXmlValue formats a decoded value for an attribute. ASCII is kept as
+// it is (without the NUL), lists are comma-separated, and rationals are shown
+// as fractions. Undefined-type values are shown by their own presentation.
+func formatXmlValue(value interface{}) (phrase string, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	switch t := value.(type) {
+	case string:
+		return strings.TrimRight(t, "\000"), nil
+	case []byte, []int8, []uint16, []uint32, []int32, []float32, []float64, []exifcommon.Rational, []exifcommon.SignedRational:
+		phrase, err = formatGenericValue(value)
+		log.PanicIf(err)
+
+		return phrase, nil
+	case fmt.Stringer:
+		return t.String(), nil
+	}
+
+	return fmt.Sprintf("%v", value), nil
+}
+
+// DumpXml returns the `Dump()` description encoded as XML. There is an
+// element for each IFD and, in it, one for each tag with its ID, name, type,
+// unit-count, and decoded value. Any IFD that is reachable more than once is
+// only described the first time.
+func (ifd *Ifd) DumpXml() (data []byte, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	ifdDump, err := ifd.Dump()
+	log.PanicIf(err)
+
+	ifdXml, err := newIfdXml(ifdDump)
+	log.PanicIf(err)
+
+	data, err = xml.MarshalIndent(ifdXml, "", "  ")
+	log.PanicIf(err)
+
+	return data, nil
+}
@@ -0,0 +1,203 @@
+package exif
+
+import (
+	"testing"
+
+	"encoding/json"
+	"encoding/xml"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/mschilli/go-exif/v3/common"
+)
+
+func TestIfd_DumpJson(t *testing.T) {
+	testImageFilepath := getTestImageFilepath()
+
+	rawExif, err := SearchFileAndExtractExif(testImageFilepath)
+	log.PanicIf(err)
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	_, index, err := Collect(im, ti, rawExif)
+	log.PanicIf(err)
+
+	data, err := index.RootIfd.DumpJson()
+	log.PanicIf(err)
+
+	ifdDump := new(IfdDump)
+
+	err = json.Unmarshal(data, ifdDump)
+	log.PanicIf(err)
+
+	if ifdDump.IfdPath != "IFD" {
+		t.Fatalf("Root IFD-path not correct: [%s]", ifdDump.IfdPath)
+	} else if len(ifdDump.Tags) != len(index.RootIfd.Entries()) {
+		t.Fatalf("Root tag count not correct: (%d)", len(ifdDump.Tags))
+	} else if ifdDump.Tags[0].TagName != "Make" {
+		t.Fatalf("First tag not correct: [%s]", ifdDump.Tags[0].TagName)
+	} else if len(ifdDump.Children) != 2 {
+		t.Fatalf("Child count not correct: (%d)", len(ifdDump.Children))
+	} else if ifdDump.Children[0].IfdPath != "IFD/Exif" {
+		t.Fatalf("First child not correct: [%s]", ifdDump.Children[0].IfdPath)
+	} else if ifdDump.Children[0].Children[0].IfdPath != "IFD/Exif/Iop" {
+		t.Fatalf("Grandchild not correct: [%s]", ifdDump.Children[0].Children[0].IfdPath)
+	} else if ifdDump.Next == nil || ifdDump.Next.IfdPath != "IFD1" {
+		t.Fatalf("Next IFD not correct.")
+	}
+}
+
+func TestIfd_Dump__Shared(t *testing.T) {
+	exifData := getExifSimpleTestIbBytes()
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	_, index, err := Collect(im, ti, exifData)
+	log.PanicIf(err)
+
+	// Make the IFD link to itself.
+	ifd := index.RootIfd
+	ifd.nextIfd = ifd
+
+	ifdDump, err := ifd.Dump()
+	log.PanicIf(err)
+
+	if ifdDump.Next != nil {
+		t.Fatalf("Self-link was not suppressed.")
+	}
+}
+
+func TestIfd_Dump__ValueBudgetExceeded(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, eh, err := NewIfdEnumerateWithBytes(im, ti, getExifSimpleTestIbBytes())
+	log.PanicIf(err)
+
+	// The values are (11) + (2) + (4) + (8) bytes.
+	ie.SetMaxValueBytes(20)
+
+	index, err := ie.Collect(eh.FirstIfdOffset)
+	log.PanicIf(err)
+
+	ifdDump, err := index.RootIfd.Dump()
+	log.PanicIf(err)
+
+	if len(ifdDump.Tags) != 4 {
+		t.Fatalf("Tag count not correct: (%d)", len(ifdDump.Tags))
+	}
+
+	for _, itd := range ifdDump.Tags[:3] {
+		if itd.Value == nil {
+			t.Fatalf("Value for tag (0x%04x) should have been read.", itd.TagId)
+		}
+	}
+
+	if ifdDump.Tags[3].Value != nil {
+		t.Fatalf("Value over the limit should be nil: %v", ifdDump.Tags[3].Value)
+	}
+}
+
+func TestIfd_DumpXml(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, eh, err := NewIfdEnumerateWithBytes(im, ti, getTestExifData())
+	log.PanicIf(err)
+
+	index, err := ie.Collect(eh.FirstIfdOffset)
+	log.PanicIf(err)
+
+	data, err := index.RootIfd.DumpXml()
+	log.PanicIf(err)
+
+	ifdXml := new(IfdXml)
+
+	err = xml.Unmarshal(data, ifdXml)
+	log.PanicIf(err)
+
+	if ifdXml.IfdPath != "IFD" {
+		t.Fatalf("Root IFD-path not correct: [%s]", ifdXml.IfdPath)
+	} else if len(ifdXml.Tags) != len(index.RootIfd.Entries()) {
+		t.Fatalf("Root tag count not correct: (%d)", len(ifdXml.Tags))
+	} else if len(ifdXml.Children) != 2 {
+		t.Fatalf("Child count not correct: (%d)", len(ifdXml.Children))
+	} else if ifdXml.Children[0].IfdPath != "IFD/Exif" {
+		t.Fatalf("First child not correct: [%s]", ifdXml.Children[0].IfdPath)
+	} else if ifdXml.Children[0].Children[0].IfdPath != "IFD/Exif/Iop" {
+		t.Fatalf("Grandchild not correct: [%s]", ifdXml.Children[0].Children[0].IfdPath)
+	} else if ifdXml.Next == nil || ifdXml.Next.IfdPath != "IFD1" {
+		t.Fatalf("Next IFD not correct.")
+	}
+
+	expected := IfdTagEntryXml{
+		TagId:       "0x0110",
+		TagName:     "Model",
+		TagTypeName: "ASCII",
+		UnitCount:   22,
+		Value:       "Canon EOS 5D Mark III",
+	}
+
+	if ifdXml.Tags[1] != expected {
+		t.Fatalf("Model tag not correct: %v != %v", ifdXml.Tags[1], expected)
+	}
+
+	exifPointer := ifdXml.Tags[len(ifdXml.Tags)-2]
+	if exifPointer.ChildIfdPath != "IFD/Exif" {
+		t.Fatalf("Child IFD-path not correct: %v", exifPointer)
+	}
+
+	for _, tagXml := range ifdXml.Children[0].Tags {
+		if tagXml.TagName == "ExposureTime" && tagXml.Value != "1/640" {
+			t.Fatalf("ExposureTime not correct: [%s]", tagXml.Value)
+		}
+	}
+}
+
+func TestIfd_DumpXml__Shared(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, eh, err := NewIfdEnumerateWithBytes(im, ti, getExifSimpleTestIbBytes())
+	log.PanicIf(err)
+
+	index, err := ie.Collect(eh.FirstIfdOffset)
+	log.PanicIf(err)
+
+	// Make the IFD link to itself.
+	ifd := index.RootIfd
+	ifd.nextIfd = ifd
+
+	data, err := ifd.DumpXml()
+	log.PanicIf(err)
+
+	ifdXml := new(IfdXml)
+
+	err = xml.Unmarshal(data, ifdXml)
+	log.PanicIf(err)
+
+	if ifdXml.Next != nil {
+		t.Fatalf("Self-link was not suppressed.")
+	}
+}
+
+func TestFormatXmlValue__SignedBytes(t *testing.T) {
+	phrase, err := formatXmlValue([]int8{-1, 0, 127})
+	log.PanicIf(err)
+
+	if phrase != "-1, 0, 127" {
+		t.Fatalf("Phrase not correct: [%s]", phrase)
+	}
+}
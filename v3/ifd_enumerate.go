@@ -2,23 +2,27 @@ package exif
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
-	"strconv"
+	"math"
+	"os"
+	"sort"
 	"strings"
 	"time"
 
 	"encoding/binary"
+	"io/ioutil"
 
 	"github.com/dsoprea/go-logging"
 
-	"github.com/dsoprea/go-exif/v3/common"
-	"github.com/dsoprea/go-exif/v3/undefined"
+	"github.com/mschilli/go-exif/v3/common"
+	"github.com/mschilli/go-exif/v3/undefined"
 )
 
 var (
-	ifdEnumerateLogger = log.NewLogger("exif.ifd_enumerate")
+	ifdEnumerateLogger = newPackageLogger("exif.ifd_enumerate")
 )
 
 var (
@@ -28,13 +32,60 @@ var (
 	// ErrNoGpsTags means that no GPS info was found.
 	ErrNoGpsTags = errors.New("no gps tags")
 
+	// ErrNoGpsIfd means that there was no GPS IFD.
+	ErrNoGpsIfd = errors.New("no gps ifd")
+
 	// ErrTagTypeNotValid means that the tag-type is not valid.
 	ErrTagTypeNotValid = errors.New("tag type invalid")
 
 	// ErrOffsetInvalid means that the file offset is not valid.
 	ErrOffsetInvalid = errors.New("file offset invalid")
+
+	// ErrTagCountInvalid means that the IFD claims more tags than there is
+	// data for.
+	ErrTagCountInvalid = errors.New("IFD tag count exceeds available data")
+
+	// ErrTooManyIfds means that more IFDs were found than we are willing to
+	// parse.
+	ErrTooManyIfds = errors.New("too many IFDs")
+
+	// ErrTruncatedData means that the data ended in the middle of an IFD.
+	ErrTruncatedData = errors.New("EXIF data truncated")
+
+	// ErrIfdCycle means that the IFD chain links back to an IFD that was
+	// already in it. This is only returned in strict mode. See
+	// `IfdEnumerate.SetStrictMode()`.
+	ErrIfdCycle = errors.New("IFD chain has a cycle")
+
+	// ErrValueBudgetExceeded means that reading another value would exceed
+	// the limit on the total number of value bytes read. See
+	// `IfdEnumerate.SetMaxValueBytes()`.
+	ErrValueBudgetExceeded = errors.New("value byte budget exceeded")
 )
 
+// knownReadErrors are the conditions that the read paths return directly,
+// rather than as a generic wrapped error.
+var knownReadErrors = []error{
+	ErrOffsetInvalid,
+	ErrTagCountInvalid,
+	ErrTruncatedData,
+	ErrTagTypeNotValid,
+	ErrIfdCycle,
+	ErrValueBudgetExceeded,
+}
+
+// asKnownReadError returns the unwrapped known error that `err` is or wraps,
+// or nil if it is not one of them.
+func asKnownReadError(err error) error {
+	for _, knownErr := range knownReadErrors {
+		if log.Is(err, knownErr) == true {
+			return knownErr
+		}
+	}
+
+	return nil
+}
+
 var (
 	// ValidGpsVersions is the list of recognized EXIF GPS versions/signatures.
 	ValidGpsVersions = [][4]byte{
@@ -79,28 +130,190 @@ type byteParser struct {
 	rs            io.ReadSeeker
 	ifdOffset     uint32
 	currentOffset uint32
+
+	// dataLength is the total length of the stream.
+	dataLength int64
+
+	// isBigTiff is true if the IFD is in a BigTIFF, whose tag-counts,
+	// unit-counts, and offsets are eight bytes.
+	isBigTiff bool
+
+	// scratch is reused by every read so that they don't allocate.
+	scratch [8]byte
 }
 
-// newByteParser returns a new byteParser struct.
+// newByteParser returns a new byteParser struct. ErrOffsetInvalid is returned
+// if there isn't room at `initialOffset` for even the tag-count.
 //
 // initialOffset is for arithmetic-based tracking of where we should be at in
 // the stream.
 func newByteParser(rs io.ReadSeeker, byteOrder binary.ByteOrder, initialOffset uint32) (bp *byteParser, err error) {
-	// TODO(dustin): Add test
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	dataLength, err := rs.Seek(0, io.SeekEnd)
+	log.PanicIf(err)
+
+	if int64(initialOffset)+2 > dataLength {
+		ifdEnumerateLogger.Warningf(nil, "IFD offset (0x%08x) is beyond the end of the data (0x%08x).", initialOffset, dataLength)
+		return nil, ErrOffsetInvalid
+	}
+
+	_, err = rs.Seek(int64(initialOffset), io.SeekStart)
+	log.PanicIf(err)
 
 	bp = &byteParser{
 		rs:            rs,
 		byteOrder:     byteOrder,
 		currentOffset: initialOffset,
+		dataLength:    dataLength,
 	}
 
 	return bp, nil
 }
 
+// getTagCount reads the tag-count at the front of the IFD, which is two bytes
+// (eight in a BigTIFF). ErrTagCountInvalid is returned (unwrapped) if the
+// entries and the next-IFD offset that follows them don't fit in the data.
+func (bp *byteParser) getTagCount() (tagCount int, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	var rawTagCount uint64
+	if bp.isBigTiff == true {
+		rawTagCount, err = bp.getUint64()
+		log.PanicIf(err)
+	} else {
+		rawTagCount16, err := bp.getUint16()
+		log.PanicIf(err)
+
+		rawTagCount = uint64(rawTagCount16)
+	}
+
+	// The tags are followed by the next-IFD offset.
+	remaining := bp.dataLength - int64(bp.CurrentOffset()) - int64(bp.offsetLength())
+	if remaining < 0 || rawTagCount > uint64(remaining)/uint64(bp.entryLength()) {
+		return 0, ErrTagCountInvalid
+	}
+
+	return int(rawTagCount), nil
+}
+
+// tagCountLength returns the length of the tag-count at the front of an IFD:
+// two bytes (eight in a BigTIFF).
+func (bp *byteParser) tagCountLength() int {
+	if bp.isBigTiff == true {
+		return bigTiffOffsetSize
+	}
+
+	return 2
+}
+
+// entryLength returns the length of one entry: twelve bytes (twenty in a
+// BigTIFF).
+func (bp *byteParser) entryLength() int {
+	if bp.isBigTiff == true {
+		return bigTiffEntryLength
+	}
+
+	return 12
+}
+
+// offsetLength returns the length of an offset (and of the value-offset of an
+// entry): four bytes (eight in a BigTIFF).
+func (bp *byteParser) offsetLength() int {
+	if bp.isBigTiff == true {
+		return bigTiffOffsetSize
+	}
+
+	return 4
+}
+
+// getOffset reads an offset, like the next-IFD offset, which is four bytes
+// (eight in a BigTIFF). ErrBigTiff is returned (unwrapped) if it doesn't fit
+// in 32 bits.
+func (bp *byteParser) getOffset() (offset uint32, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	if bp.isBigTiff == false {
+		offset, err = bp.getUint32()
+		log.PanicIf(err)
+
+		return offset, nil
+	}
+
+	offset64, err := bp.getUint64()
+	log.PanicIf(err)
+
+	if offset64 > math.MaxUint32 {
+		return 0, ErrBigTiff
+	}
+
+	return uint32(offset64), nil
+}
+
+// getUnitCountAndValueOffset reads the unit-count and the value-offset of an
+// entry with the given type. A copy of the raw value-offset, which is four
+// bytes (eight in a BigTIFF), is also returned. The entry is always read to
+// its end. ErrBigTiff is returned (unwrapped) if the unit-count or the
+// value-offset doesn't fit in 32 bits. See bigTiffValueOffset().
+func (bp *byteParser) getUnitCountAndValueOffset(tagType exifcommon.TagTypePrimitive) (unitCount uint32, valueOffset uint32, rawValueOffset []byte, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	if bp.isBigTiff == false {
+		unitCount, err = bp.getUint32()
+		log.PanicIf(err)
+
+		valueOffset, rawValueOffset, err = bp.getUint32WithRaw()
+		log.PanicIf(err)
+
+		return unitCount, valueOffset, rawValueOffset, nil
+	}
+
+	unitCount64, err := bp.getUint64()
+	log.PanicIf(err)
+
+	scratch, err := bp.read(bigTiffOffsetSize)
+	log.PanicIf(err)
+
+	rawValueOffset = make([]byte, len(scratch))
+	copy(rawValueOffset, scratch)
+
+	if tagType.IsValid() == false {
+		// The tag will be skipped for its type.
+		return uint32(unitCount64), 0, rawValueOffset, nil
+	}
+
+	unitCount, valueOffset, err = bigTiffValueOffset(tagType, unitCount64, rawValueOffset, bp.byteOrder)
+	if err != nil {
+		if err == ErrBigTiff {
+			return 0, 0, rawValueOffset, err
+		}
+
+		log.Panic(err)
+	}
+
+	return unitCount, valueOffset, rawValueOffset, nil
+}
+
 // getUint16 reads a uint16 and advances both our current and our current
 // accumulator (which allows us to know how far to seek to the beginning of the
 // next IFD when it's time to jump).
-func (bp *byteParser) getUint16() (value uint16, raw []byte, err error) {
+func (bp *byteParser) getUint16() (value uint16, err error) {
 	defer func() {
 		if state := recover(); state != nil {
 			err = log.Wrap(state.(error))
@@ -109,24 +322,24 @@ func (bp *byteParser) getUint16() (value uint16, raw []byte, err error) {
 
 	// TODO(dustin): Add test
 
-	needBytes := 2
-
-	raw = make([]byte, needBytes)
+	raw, err := bp.read(2)
+	if err != nil {
+		if err == ErrTruncatedData {
+			return 0, err
+		}
 
-	_, err = io.ReadFull(bp.rs, raw)
-	log.PanicIf(err)
+		log.Panic(err)
+	}
 
 	value = bp.byteOrder.Uint16(raw)
 
-	bp.currentOffset += uint32(needBytes)
-
-	return value, raw, nil
+	return value, nil
 }
 
 // getUint32 reads a uint32 and advances both our current and our current
 // accumulator (which allows us to know how far to seek to the beginning of the
 // next IFD when it's time to jump).
-func (bp *byteParser) getUint32() (value uint32, raw []byte, err error) {
+func (bp *byteParser) getUint32() (value uint32, err error) {
 	defer func() {
 		if state := recover(); state != nil {
 			err = log.Wrap(state.(error))
@@ -135,18 +348,104 @@ func (bp *byteParser) getUint32() (value uint32, raw []byte, err error) {
 
 	// TODO(dustin): Add test
 
-	needBytes := 4
+	raw, err := bp.read(4)
+	if err != nil {
+		if err == ErrTruncatedData {
+			return 0, err
+		}
+
+		log.Panic(err)
+	}
 
-	raw = make([]byte, needBytes)
+	value = bp.byteOrder.Uint32(raw)
 
-	_, err = io.ReadFull(bp.rs, raw)
-	log.PanicIf(err)
+	return value, nil
+}
+
+// getUint64 reads a uint64 and advances both our current and our current
+// accumulator. These are only found in BigTIFFs.
+func (bp *byteParser) getUint64() (value uint64, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	raw, err := bp.read(8)
+	if err != nil {
+		if err == ErrTruncatedData {
+			return 0, err
+		}
+
+		log.Panic(err)
+	}
+
+	value = bp.byteOrder.Uint64(raw)
+
+	return value, nil
+}
+
+// getUint32WithRaw is the same as getUint32() but also returns a copy of the
+// raw bytes that the caller can keep.
+func (bp *byteParser) getUint32WithRaw() (value uint32, raw []byte, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	scratch, err := bp.read(4)
+	if err != nil {
+		if err == ErrTruncatedData {
+			return 0, nil, err
+		}
+
+		log.Panic(err)
+	}
+
+	raw = make([]byte, len(scratch))
+	copy(raw, scratch)
 
 	value = bp.byteOrder.Uint32(raw)
 
+	return value, raw, nil
+}
+
+// read reads the next `needBytes` bytes into the scratch buffer and advances
+// the current offset. The returned slice is only valid until the next read.
+func (bp *byteParser) read(needBytes int) (raw []byte, err error) {
+	raw = bp.scratch[:needBytes]
+
+	_, err = io.ReadFull(bp.rs, raw)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return nil, ErrTruncatedData
+	} else if err != nil {
+		return nil, err
+	}
+
 	bp.currentOffset += uint32(needBytes)
 
-	return value, raw, nil
+	return raw, nil
+}
+
+// skip advances past the next `count` bytes without reading them.
+func (bp *byteParser) skip(count int) (err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	if int64(bp.currentOffset)+int64(count) > bp.dataLength {
+		return ErrTruncatedData
+	}
+
+	_, err = bp.rs.Seek(int64(count), io.SeekCurrent)
+	log.PanicIf(err)
+
+	bp.currentOffset += uint32(count)
+
+	return nil
 }
 
 // CurrentOffset returns the starting offset but the number of bytes that we
@@ -165,6 +464,33 @@ type IfdEnumerate struct {
 	furthestOffset uint32
 
 	visitedIfdOffsets map[uint32]struct{}
+
+	// valueRs is shared by all of the tags that we parse in order to read
+	// their values. Every read seeks to an absolute offset first, so it
+	// doesn't matter where it has been left. See getValueReadSeeker().
+	valueRs io.ReadSeeker
+
+	// strictMode causes problems that are otherwise logged and skipped to be
+	// returned as errors. See SetStrictMode().
+	strictMode bool
+
+	// keepRawEntries causes the twelve bytes of each tag's entry to be kept.
+	// See SetKeepRawEntries().
+	keepRawEntries bool
+
+	// headerIndex and rootChainIndex are the trees that the accessors (HasTag(),
+	// Orientation(), ThumbnailBytes(), etc..) consult. Each is collected the
+	// first time that it is needed. See getHeaderIndex().
+	headerIndex    *IfdIndex
+	rootChainIndex *IfdIndex
+
+	// valueBudget limits the total number of value bytes that the tags we
+	// parse may read. It is nil if there is no limit. See SetMaxValueBytes().
+	valueBudget *valueBudget
+
+	// bigTiff is whether the data is a BigTIFF. It is nil until the header has
+	// been read. See isBigTiff().
+	bigTiff *bool
 }
 
 // NewIfdEnumerate returns a new instance of IfdEnumerate.
@@ -179,6 +505,128 @@ func NewIfdEnumerate(ifdMapping *exifcommon.IfdMapping, tagIndex *TagIndex, ebs
 	}
 }
 
+// NewIfdEnumerateWithReader returns a new instance of IfdEnumerate for EXIF
+// data read from a plain reader. The whole stream is buffered (see
+// NewExifReadSeekerWithReader). If you already have an `io.ReadSeeker`, use
+// NewExifReadSeeker() with NewIfdEnumerate() instead.
+func NewIfdEnumerateWithReader(ifdMapping *exifcommon.IfdMapping, tagIndex *TagIndex, r io.Reader, byteOrder binary.ByteOrder) (ie *IfdEnumerate, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	ebs, err := NewExifReadSeekerWithReader(r)
+	if err != nil {
+		if err == ErrExifTooLarge {
+			return nil, err
+		}
+
+		log.Panic(err)
+	}
+
+	ie = NewIfdEnumerate(ifdMapping, tagIndex, ebs, byteOrder)
+
+	return ie, nil
+}
+
+// SetStrictMode enables or disables strict mode. By default, the parse is
+// lenient and problems with the data are logged and skipped where possible.
+// In strict mode, the following are returned as errors (unwrapped) instead:
+//
+//   - ErrTagTypeNotValid: a tag has a type that is not valid or that the tag
+//     doesn't support.
+//   - ErrOffsetInvalid: an IFD, a value, or the thumbnail is beyond the end of
+//     the data.
+//   - ErrIfdCycle: the IFD chain links back to an IFD that was already in it.
+//   - ErrBigTiff: a tag or the next IFD in a BigTIFF is beyond the first
+//     4 GiB.
+//
+// Tags that are not in the tag-index are still skipped, since they are
+// usually private tags rather than corruption. IFDs that are skipped because
+// of CollectOptions limits are still reported in `IfdIndex.SkippedIfds`.
+func (ie *IfdEnumerate) SetStrictMode(flag bool) {
+	ie.strictMode = flag
+	ie.dropHeaderIndexes()
+}
+
+// StrictMode returns true if strict mode is enabled. See SetStrictMode().
+func (ie *IfdEnumerate) StrictMode() bool {
+	return ie.strictMode
+}
+
+// SetKeepRawEntries enables or disables keeping the twelve bytes of each
+// tag's entry in its IFD (see `IfdTagEntry.RawEntry()`) for the tags that are
+// parsed from now on. This is disabled by default, since most callers don't
+// need them and they would be allocated for every tag.
+func (ie *IfdEnumerate) SetKeepRawEntries(flag bool) {
+	ie.keepRawEntries = flag
+	ie.dropHeaderIndexes()
+}
+
+// SetMaxValueBytes limits the total number of bytes that the values of the
+// tags parsed from now on may read, across all of them, to guard against
+// files that declare many enormous values. Once the limit would be crossed,
+// reading a value fails with ErrValueBudgetExceeded. The thumbnail, which is
+// read while collecting, counts as well. A limit of zero (the default) means
+// there is no limit.
+func (ie *IfdEnumerate) SetMaxValueBytes(maxValueBytes uint64) {
+	ie.dropHeaderIndexes()
+
+	if maxValueBytes == 0 {
+		ie.valueBudget = nil
+		return
+	}
+
+	ie.valueBudget = newValueBudget(maxValueBytes)
+}
+
+// getValueReadSeeker returns the stream that tag values are read from. It is
+// only created once per enumerator rather than once per tag.
+func (ie *IfdEnumerate) getValueReadSeeker() (rs io.ReadSeeker, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	if ie.valueRs == nil {
+		ie.valueRs, err = ie.ebs.GetReadSeeker(0)
+		log.PanicIf(err)
+	}
+
+	return ie.valueRs, nil
+}
+
+// isBigTiff returns true if the data is a BigTIFF. The header at the front of
+// the data is only read the first time.
+func (ie *IfdEnumerate) isBigTiff() (isBigTiff bool, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	if ie.bigTiff != nil {
+		return *ie.bigTiff, nil
+	}
+
+	rs, err := ie.ebs.GetReadSeeker(0)
+	log.PanicIf(err)
+
+	headerData := make([]byte, ExifSignatureLength)
+
+	_, err = io.ReadFull(rs, headerData)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		log.Panic(err)
+	}
+
+	isBigTiff = err == nil && isBigTiffHeader(headerData) == true
+	ie.bigTiff = &isBigTiff
+
+	return isBigTiff, nil
+}
+
 func (ie *IfdEnumerate) getByteParser(ifdOffset uint32) (bp *byteParser, err error) {
 	defer func() {
 		if state := recover(); state != nil {
@@ -186,6 +634,9 @@ func (ie *IfdEnumerate) getByteParser(ifdOffset uint32) (bp *byteParser, err err
 		}
 	}()
 
+	isBigTiff, err := ie.isBigTiff()
+	log.PanicIf(err)
+
 	initialOffset := ExifAddressableAreaStart + ifdOffset
 
 	rs, err := ie.ebs.GetReadSeeker(int64(initialOffset))
@@ -205,6 +656,15 @@ func (ie *IfdEnumerate) getByteParser(ifdOffset uint32) (bp *byteParser, err err
 		log.Panic(err)
 	}
 
+	if isBigTiff == true {
+		if int64(initialOffset)+bigTiffOffsetSize > bp.dataLength {
+			ifdEnumerateLogger.Warningf(nil, "IFD offset (0x%08x) is beyond the end of the data (0x%08x).", initialOffset, bp.dataLength)
+			return nil, ErrOffsetInvalid
+		}
+
+		bp.isBigTiff = true
+	}
+
 	return bp, nil
 }
 
@@ -215,23 +675,36 @@ func (ie *IfdEnumerate) parseTag(ii *exifcommon.IfdIdentity, tagPosition int, bp
 		}
 	}()
 
-	tagId, _, err := bp.getUint16()
+	tagId, err := bp.getUint16()
 	log.PanicIf(err)
 
-	tagTypeRaw, _, err := bp.getUint16()
+	tagTypeRaw, err := bp.getUint16()
 	log.PanicIf(err)
 
 	tagType := exifcommon.TagTypePrimitive(tagTypeRaw)
 
-	unitCount, _, err := bp.getUint32()
-	log.PanicIf(err)
+	unitCount, valueOffset, rawValueOffset, err := bp.getUnitCountAndValueOffset(tagType)
+	if err != nil {
+		if err == ErrBigTiff {
+			ifdEnumerateLogger.Warningf(nil,
+				"Tag (0x%04x) in IFD [%s] at position (%d) has a unit-count or an offset that does not fit in 32 bits and will be skipped.",
+				tagId, ii, tagPosition)
 
-	valueOffset, rawValueOffset, err := bp.getUint32()
-	log.PanicIf(err)
+			ite = &IfdTagEntry{
+				tagId:   tagId,
+				tagType: tagType,
+			}
 
-	// Check whether the embedded type indicator is valid.
+			return ite, ErrBigTiff
+		}
 
-	if tagType.IsValid() == false {
+		log.Panic(err)
+	}
+
+	// Check whether the embedded type indicator is valid. The types that are
+	// only defined for BigTIFF aren't valid anywhere else.
+
+	if tagType.IsValid() == false || (tagType.IsBigTiffOnly() == true && bp.isBigTiff == false) {
 		// Technically, we have the type on-file in the tags-index, but
 		// if the type stored alongside the data disagrees with it,
 		// which it apparently does, all bets are off.
@@ -268,7 +741,7 @@ func (ie *IfdEnumerate) parseTag(ii *exifcommon.IfdIdentity, tagPosition int, bp
 	// If we're trying to be as forgiving as possible then use whatever type was
 	// reported in the format. Otherwise, only accept a type that's expected for
 	// this tag.
-	if ie.tagIndex.UniversalSearch() == false && it.DoesSupportType(tagType) == false {
+	if ie.tagIndex.UniversalSearch() == false && it.DoesSupportType(tagIndexType(tagType)) == false {
 		// The type in the stream disagrees with the type that this tag is
 		// expected to have. This can present issues with how we handle the
 		// special-case tags (e.g. thumbnails, GPS, etc..) when those tags
@@ -283,7 +756,7 @@ func (ie *IfdEnumerate) parseTag(ii *exifcommon.IfdIdentity, tagPosition int, bp
 
 	// Construct tag struct.
 
-	rs, err := ie.ebs.GetReadSeeker(0)
+	rs, err := ie.getValueReadSeeker()
 	log.PanicIf(err)
 
 	ite = newIfdTagEntry(
@@ -297,10 +770,38 @@ func (ie *IfdEnumerate) parseTag(ii *exifcommon.IfdIdentity, tagPosition int, bp
 		rs,
 		ie.byteOrder)
 
-	ifdPath := ii.UnindexedString()
+	ite.valueBudget = ie.valueBudget
+	ite.isBigTiff = bp.isBigTiff
 
-	// If it's an IFD but not a standard one, it'll just be seen as a LONG
-	// (the standard IFD tag type), later, unless we skip it because it's
+	if ie.keepRawEntries == true {
+		// The fields were decoded with the same byte-order, so this is the
+		// entry exactly as it was stored.
+		rawEntry := make([]byte, bp.entryLength())
+
+		ie.byteOrder.PutUint16(rawEntry[0:2], tagId)
+		ie.byteOrder.PutUint16(rawEntry[2:4], tagTypeRaw)
+
+		if bp.isBigTiff == true {
+			ie.byteOrder.PutUint64(rawEntry[4:12], uint64(unitCount))
+			copy(rawEntry[12:20], rawValueOffset)
+		} else {
+			ie.byteOrder.PutUint32(rawEntry[4:8], unitCount)
+			copy(rawEntry[8:12], rawValueOffset)
+		}
+
+		ite.rawEntry = rawEntry
+	}
+
+	// The IFDs referenced by a SubIFDs tag aren't in the mapping, so they
+	// can't have mapped children.
+	if isSubIfd(ii) == true {
+		return ite, nil
+	}
+
+	ifdPath := ii.UnindexedString()
+
+	// If it's an IFD but not a standard one, it'll just be seen as a LONG
+	// (the standard IFD tag type), later, unless we skip it because it's
 	// [likely] not even in the standard list of known tags.
 	mi, err := ie.ifdMapping.GetChild(ifdPath, tagId)
 	if err == nil {
@@ -364,7 +865,7 @@ func (ie *IfdEnumerate) tagPostParse(ite *IfdTagEntry, med *MiscellaneousExifDat
 		// This will overwrite the existing `it` and `err`. Since `FindFirst()`
 		// might generate different Errors than `Get()`, the log message above
 		// is import to try and mitigate confusion in that case.
-		it, err = ie.tagIndex.FindFirst(tagId, tagType, nil)
+		it, err = ie.tagIndex.FindFirst(tagId, tagIndexType(tagType), nil)
 		if err != nil {
 			if err != ErrTagNotFound {
 				log.Panic(err)
@@ -412,7 +913,7 @@ func (ie *IfdEnumerate) tagPostParse(ite *IfdTagEntry, med *MiscellaneousExifDat
 	// tag should ever be repeated, and b) all but one had an incorrect
 	// type and caused parsing/conversion woes. So, this is a quick fix
 	// for those scenarios.
-	if ie.tagIndex.UniversalSearch() == false && it.DoesSupportType(tagType) == false {
+	if ie.tagIndex.UniversalSearch() == false && it.DoesSupportType(tagIndexType(tagType)) == false {
 		ifdEnumerateLogger.Warningf(nil,
 			"Skipping tag [%s] (0x%04x) [%s] with an unexpected type: %v ∉ %v",
 			ii.UnindexedString(), tagId, it.Name,
@@ -424,29 +925,72 @@ func (ie *IfdEnumerate) tagPostParse(ite *IfdTagEntry, med *MiscellaneousExifDat
 	return nil
 }
 
+// visitIfd is the same as parseIfd() (descending into child IFDs) but only
+// invokes the visitor. The entries are not kept, which saves allocating them
+// when the caller doesn't need them.
+func (ie *IfdEnumerate) visitIfd(ctx context.Context, ii *exifcommon.IfdIdentity, bp *byteParser, visitor TagVisitorFn, med *MiscellaneousExifData) (nextIfdOffset uint32, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	nextIfdOffset, _, _, err = ie.parseIfdWithEntries(ctx, ii, bp, visitor, true, false, med)
+	log.PanicIf(err)
+
+	return nextIfdOffset, nil
+}
+
 // parseIfd decodes the IFD block that we're currently sitting on the first
 // byte of.
-func (ie *IfdEnumerate) parseIfd(ii *exifcommon.IfdIdentity, bp *byteParser, visitor TagVisitorFn, doDescend bool, med *MiscellaneousExifData) (nextIfdOffset uint32, entries []*IfdTagEntry, thumbnailData []byte, err error) {
+func (ie *IfdEnumerate) parseIfd(ctx context.Context, ii *exifcommon.IfdIdentity, bp *byteParser, visitor TagVisitorFn, doDescend bool, med *MiscellaneousExifData) (nextIfdOffset uint32, entries []*IfdTagEntry, thumbnailData []byte, err error) {
 	defer func() {
 		if state := recover(); state != nil {
 			err = log.Wrap(state.(error))
 		}
 	}()
 
-	tagCount, _, err := bp.getUint16()
+	nextIfdOffset, entries, thumbnailData, err = ie.parseIfdWithEntries(ctx, ii, bp, visitor, doDescend, true, med)
 	log.PanicIf(err)
 
+	return nextIfdOffset, entries, thumbnailData, nil
+}
+
+// parseIfdWithEntries is the implementation of parseIfd(). If `keepEntries`
+// is false, the returned entries will be nil.
+func (ie *IfdEnumerate) parseIfdWithEntries(ctx context.Context, ii *exifcommon.IfdIdentity, bp *byteParser, visitor TagVisitorFn, doDescend bool, keepEntries bool, med *MiscellaneousExifData) (nextIfdOffset uint32, entries []*IfdTagEntry, thumbnailData []byte, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	tagCount, err := bp.getTagCount()
+	if err != nil {
+		if err == ErrTagCountInvalid {
+			ifdEnumerateLogger.Warningf(nil, "IFD [%s] has more tags than the data has room for: (%d)", ii.String(), bp.dataLength)
+		}
+
+		log.Panic(err)
+	}
+
 	ifdEnumerateLogger.Debugf(nil, "IFD [%s] tag-count: (%d)", ii.String(), tagCount)
 
-	entries = make([]*IfdTagEntry, 0)
+	if keepEntries == true {
+		entries = make([]*IfdTagEntry, 0, tagCount)
+	}
 
 	var enumeratorThumbnailOffset *IfdTagEntry
 	var enumeratorThumbnailSize *IfdTagEntry
 
-	for i := 0; i < int(tagCount); i++ {
+	for i := 0; i < tagCount; i++ {
 		ite, err := ie.parseTag(ii, i, bp)
 		if err != nil {
-			if log.Is(err, ErrTagNotFound) == true || log.Is(err, ErrTagTypeNotValid) == true {
+			if log.Is(err, ErrTagTypeNotValid) == true && ie.strictMode == true {
+				log.Panic(ErrTagTypeNotValid)
+			} else if log.Is(err, ErrBigTiff) == true && ie.strictMode == true {
+				log.Panic(ErrBigTiff)
+			} else if log.Is(err, ErrTagNotFound) == true || log.Is(err, ErrTagTypeNotValid) == true || log.Is(err, ErrBigTiff) == true {
 				// These tags should've been fully logged in parseTag(). The
 				// ITE returned is nil so we can't print anything about them, now.
 				continue
@@ -466,6 +1010,17 @@ func (ie *IfdEnumerate) parseIfd(ii *exifcommon.IfdIdentity, bp *byteParser, vis
 
 		tagId := ite.TagId()
 
+		if ie.strictMode == true {
+			err := ite.checkValueBounds()
+			if err != nil {
+				if err == exifcommon.ErrNotEnoughData {
+					log.Panic(ErrOffsetInvalid)
+				}
+
+				log.Panic(err)
+			}
+		}
+
 		if visitor != nil {
 			err := visitor(ite)
 			log.PanicIf(err)
@@ -475,14 +1030,20 @@ func (ie *IfdEnumerate) parseIfd(ii *exifcommon.IfdIdentity, bp *byteParser, vis
 			ifdEnumerateLogger.Debugf(nil, "Skipping the thumbnail offset tag (0x%04x). Use accessors to get it or set it.", tagId)
 
 			enumeratorThumbnailOffset = ite
-			entries = append(entries, ite)
+
+			if keepEntries == true {
+				entries = append(entries, ite)
+			}
 
 			continue
 		} else if ite.IsThumbnailSize() == true {
 			ifdEnumerateLogger.Debugf(nil, "Skipping the thumbnail size tag (0x%04x). Use accessors to get it or set it.", tagId)
 
 			enumeratorThumbnailSize = ite
-			entries = append(entries, ite)
+
+			if keepEntries == true {
+				entries = append(entries, ite)
+			}
 
 			continue
 		}
@@ -511,29 +1072,29 @@ func (ie *IfdEnumerate) parseIfd(ii *exifcommon.IfdIdentity, bp *byteParser, vis
 			if doDescend == true {
 				ifdEnumerateLogger.Debugf(nil, "Descending from IFD [%s] to IFD [%s].", ii, ite.ChildIfdPath())
 
-				currentIfdTag := ii.IfdTag()
-
-				childIfdTag :=
-					exifcommon.NewIfdTag(
-						&currentIfdTag,
-						ite.TagId(),
-						ite.ChildIfdName())
-
-				iiChild := ii.NewChild(childIfdTag, 0)
-
-				err := ie.scan(iiChild, ite.getValueOffset(), visitor, med)
+				err := ie.scan(ctx, ite.getChildIfdIdentity(), ite.getValueOffset(), visitor, med)
 				log.PanicIf(err)
 
 				ifdEnumerateLogger.Debugf(nil, "Ascending from IFD [%s] to IFD [%s].", ite.ChildIfdPath(), ii)
 			}
 		}
 
-		entries = append(entries, ite)
+		if keepEntries == true {
+			entries = append(entries, ite)
+		}
 	}
 
 	if enumeratorThumbnailOffset != nil && enumeratorThumbnailSize != nil {
 		thumbnailData, err = ie.parseThumbnail(enumeratorThumbnailOffset, enumeratorThumbnailSize)
-		if err != nil {
+		if err != nil && log.Is(err, ErrValueBudgetExceeded) == true {
+			log.Panic(ErrValueBudgetExceeded)
+		} else if err != nil && ie.strictMode == true {
+			if log.Is(err, exifcommon.ErrNotEnoughData) == true {
+				log.Panic(ErrOffsetInvalid)
+			}
+
+			log.Panic(err)
+		} else if err != nil {
 			ifdEnumerateLogger.Errorf(
 				nil, err,
 				"We tried to bump our furthest-offset counter but there was an issue first seeking past the thumbnail.")
@@ -554,12 +1115,21 @@ func (ie *IfdEnumerate) parseIfd(ii *exifcommon.IfdIdentity, bp *byteParser, vis
 		}
 	}
 
-	nextIfdOffset, _, err = bp.getUint32()
-	log.PanicIf(err)
+	nextIfdOffset, err = bp.getOffset()
+	if err == ErrBigTiff && ie.strictMode == true {
+		log.Panic(err)
+	} else if err == ErrBigTiff {
+		ifdEnumerateLogger.Warningf(nil, "Next IFD after IFD [%s] is beyond the first 4 GiB and will not be parsed.", ii.String())
+		nextIfdOffset = 0
+	} else if err != nil {
+		log.Panic(err)
+	}
 
 	_, alreadyVisited := ie.visitedIfdOffsets[nextIfdOffset]
 
-	if alreadyVisited == true {
+	if alreadyVisited == true && ie.strictMode == true {
+		log.Panic(ErrIfdCycle)
+	} else if alreadyVisited == true {
 		ifdEnumerateLogger.Warningf(nil, "IFD at offset (0x%08x) has been linked-to more than once. There might be a cycle in the IFD chain. Not reparsing.", nextIfdOffset)
 		nextIfdOffset = 0
 	}
@@ -584,7 +1154,9 @@ func (ie *IfdEnumerate) parseThumbnail(offsetIte, lengthIte *IfdTagEntry) (thumb
 	vRaw, err := lengthIte.Value()
 	log.PanicIf(err)
 
-	vList := vRaw.([]uint32)
+	vList, err := uint32sFromValue(vRaw)
+	log.PanicIf(err)
+
 	if len(vList) != 1 {
 		log.Panicf("not exactly one long: (%d)", len(vList))
 	}
@@ -603,23 +1175,40 @@ func (ie *IfdEnumerate) parseThumbnail(offsetIte, lengthIte *IfdTagEntry) (thumb
 
 // scan parses and enumerates the different IFD blocks and invokes a visitor
 // callback for each tag. No information is kept or returned.
-func (ie *IfdEnumerate) scan(iiGeneral *exifcommon.IfdIdentity, ifdOffset uint32, visitor TagVisitorFn, med *MiscellaneousExifData) (err error) {
+func (ie *IfdEnumerate) scan(ctx context.Context, iiGeneral *exifcommon.IfdIdentity, ifdOffset uint32, visitor TagVisitorFn, med *MiscellaneousExifData) (err error) {
 	defer func() {
 		if state := recover(); state != nil {
 			err = log.Wrap(state.(error))
 		}
 	}()
 
-	// TODO(dustin): Add test
+	// The offsets of the IFDs in this chain, including the first.
+	seenOffsets := make(map[uint32]struct{})
 
 	for ifdIndex := 0; ; ifdIndex++ {
+		err := ctx.Err()
+		log.PanicIf(err)
+
 		iiSibling := iiGeneral.NewSibling(ifdIndex)
 
+		if _, found := seenOffsets[ifdOffset]; found == true {
+			if ie.strictMode == true {
+				return ErrIfdCycle
+			}
+
+			ifdEnumerateLogger.Warningf(nil, "IFD [%s] at offset (0x%08x) has already been scanned. There is a cycle in the IFD chain. Terminating scan.", iiSibling.String(), ifdOffset)
+			break
+		}
+
+		seenOffsets[ifdOffset] = struct{}{}
+
 		ifdEnumerateLogger.Debugf(nil, "Parsing IFD [%s] at offset (0x%04x) (scan).", iiSibling.String(), ifdOffset)
 
 		bp, err := ie.getByteParser(ifdOffset)
 		if err != nil {
-			if err == ErrOffsetInvalid {
+			if err == ErrOffsetInvalid && ie.strictMode == true {
+				return err
+			} else if err == ErrOffsetInvalid {
 				ifdEnumerateLogger.Errorf(nil, nil, "IFD [%s] at offset (0x%04x) is unreachable. Terminating scan.", iiSibling.String(), ifdOffset)
 				break
 			}
@@ -627,7 +1216,7 @@ func (ie *IfdEnumerate) scan(iiGeneral *exifcommon.IfdIdentity, ifdOffset uint32
 			log.Panic(err)
 		}
 
-		nextIfdOffset, _, _, err := ie.parseIfd(iiSibling, bp, visitor, true, med)
+		nextIfdOffset, err := ie.visitIfd(ctx, iiSibling, bp, visitor, med)
 		log.PanicIf(err)
 
 		currentOffset := bp.CurrentOffset()
@@ -672,14 +1261,43 @@ func (ie *IfdEnumerate) Scan(iiRoot *exifcommon.IfdIdentity, ifdOffset uint32, v
 		}
 	}()
 
+	med, err = ie.ScanContext(context.Background(), iiRoot, ifdOffset, visitor, so)
+	if err != nil {
+		if asKnownReadError(err) != nil {
+			return nil, err
+		}
+
+		log.Panic(err)
+	}
+
+	return med, nil
+}
+
+// ScanContext is the same as Scan() but stops before the next IFD once `ctx`
+// is done. In that case, `ctx.Err()` is returned as-is.
+func (ie *IfdEnumerate) ScanContext(ctx context.Context, iiRoot *exifcommon.IfdIdentity, ifdOffset uint32, visitor TagVisitorFn, so *ScanOptions) (med *MiscellaneousExifData, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
 	// TODO(dustin): Add test
 
 	med = &MiscellaneousExifData{
 		unknownTags: make(map[exifcommon.BasicTag]exifcommon.BasicTag),
 	}
 
-	err = ie.scan(iiRoot, ifdOffset, visitor, med)
-	log.PanicIf(err)
+	err = ie.scan(ctx, iiRoot, ifdOffset, visitor, med)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		} else if knownErr := asKnownReadError(err); knownErr != nil {
+			return nil, knownErr
+		}
+
+		log.Panic(err)
+	}
 
 	ifdEnumerateLogger.Debugf(nil, "Scan: It looks like the furthest offset that contained EXIF data in the EXIF blob was (%d) (Scan).", ie.FurthestOffset())
 
@@ -714,6 +1332,28 @@ type Ifd struct {
 
 	nextIfdOffset uint32
 	nextIfd       *Ifd
+
+	// makerNoteIfd is the IFD parsed from the MakerNote tag, if a parser was
+	// registered for the make. It is not one of `children` since the tag
+	// itself remains an UNDEFINED value rather than an IFD pointer.
+	makerNoteIfd *Ifd
+
+	// segment is the position of the EXIF blob that we were parsed from when
+	// several were merged. See CollectSegments().
+	segment int
+}
+
+// Segment returns the position of the EXIF blob that this IFD was parsed
+// from. This is always zero unless several blobs were merged with
+// CollectSegments().
+func (ifd *Ifd) Segment() int {
+	return ifd.segment
+}
+
+// MakerNoteIfd returns the IFD parsed from the MakerNote tag in this IFD, or
+// nil if there isn't one. See RegisterMakerNoteParser().
+func (ifd *Ifd) MakerNoteIfd() *Ifd {
+	return ifd.makerNoteIfd
 }
 
 // IfdIdentity returns IFD identity that this struct represents.
@@ -729,7 +1369,36 @@ func (ifd *Ifd) Entries() []*IfdTagEntry {
 	return ifd.entries
 }
 
-// EntriesByTagId returns a map of all tags for this IFD.
+// SortedEntries returns the tags for this IFD ordered by tag-ID, which is the
+// order that TIFF requires. Tags with the same ID stay in stream order. This
+// is a new list; `Entries()` is not reordered.
+func (ifd *Ifd) SortedEntries() []*IfdTagEntry {
+	entries := make([]*IfdTagEntry, len(ifd.entries))
+	copy(entries, ifd.entries)
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].TagId() < entries[j].TagId()
+	})
+
+	return entries
+}
+
+// IsSorted returns true if the tags for this IFD were stored in ascending
+// order of tag-ID, as TIFF requires. A tag that appears more than once also
+// breaks the order. Tags that were dropped while parsing (e.g. because of an
+// invalid type) are not considered.
+func (ifd *Ifd) IsSorted() bool {
+	for i := 1; i < len(ifd.entries); i++ {
+		if ifd.entries[i].TagId() <= ifd.entries[i-1].TagId() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// EntriesByTagId returns a map of all tags for this IFD. Each list is in
+// stream order.
 func (ifd *Ifd) EntriesByTagId() map[uint16][]*IfdTagEntry {
 
 	// TODO(dustin): Add test
@@ -745,7 +1414,8 @@ func (ifd *Ifd) Children() []*Ifd {
 	return ifd.children
 }
 
-// ChildWithIfdPath returns a map of all child IFDs of this IFD.
+// ChildWithIfdPath returns a map of all child IFDs of this IFD, keyed by their
+// indexed IFD paths (e.g. "IFD/SubIFD1").
 func (ifd *Ifd) ChildIfdIndex() map[string]*Ifd {
 
 	// TODO(dustin): Add test
@@ -762,6 +1432,25 @@ func (ifd *Ifd) ParentTagIndex() int {
 	return ifd.parentTagIndex
 }
 
+// Path returns where the IFD is in the tree as the slash-separated names of
+// it and its parents, starting at the root (e.g. "IFD/Exif/Iop"). The index
+// is appended to the name of an IFD that isn't the first in its chain (e.g.
+// "IFD1" for the IFD that follows the root IFD). This is built by following
+// the parents rather than taken from the identity, so it reflects the tree as
+// it was collected.
+func (ifd *Ifd) Path() string {
+	parts := make([]string, 0)
+	for current := ifd; current != nil; current = current.parentIfd {
+		parts = append(parts, current.ifdIdentity.LeafPathPart().String())
+	}
+
+	for i, j := 0, len(parts)-1; i < j; i, j = i+1, j-1 {
+		parts[i], parts[j] = parts[j], parts[i]
+	}
+
+	return strings.Join(parts, "/")
+}
+
 // Offset returns the offset of the IFD in the stream.
 func (ifd *Ifd) Offset() uint32 {
 
@@ -808,6 +1497,21 @@ func (ifd *Ifd) ChildWithIfdPath(iiChild *exifcommon.IfdIdentity) (childIfd *Ifd
 	return nil, nil
 }
 
+// ChildOffset returns the offset recorded by the pointer tag for the child IFD
+// with the given name (e.g. "Exif", "GPSInfo", or "Iop"). The pointer tags stay
+// in `Entries()` after the children are collected, so this is available even
+// if the child itself wasn't collected. `found` is false if this IFD has no
+// such pointer tag.
+func (ifd *Ifd) ChildOffset(ifdName string) (offset uint32, found bool) {
+	for _, ite := range ifd.entries {
+		if ite.ChildIfdName() == ifdName {
+			return ite.getValueOffset(), true
+		}
+	}
+
+	return 0, false
+}
+
 // FindTagWithId returns a list of tags (usually just zero or one) that match
 // the given tag ID. This is efficient.
 func (ifd *Ifd) FindTagWithId(tagId uint16) (results []*IfdTagEntry, err error) {
@@ -825,6 +1529,18 @@ func (ifd *Ifd) FindTagWithId(tagId uint16) (results []*IfdTagEntry, err error)
 	return results, nil
 }
 
+// EntryByTagId returns the first tag in this IFD with the given tag ID. Where
+// the same tag legitimately appears more than once, the first one (in stream
+// order) wins. Use `FindTagWithId()` or `EntriesByTagId()` to get all of them.
+func (ifd *Ifd) EntryByTagId(tagId uint16) (ite *IfdTagEntry, found bool) {
+	results := ifd.entriesByTagId[tagId]
+	if len(results) == 0 {
+		return nil, false
+	}
+
+	return results[0], true
+}
+
 // FindTagWithName returns a list of tags (usually just zero or one) that match
 // the given tag name. This is not efficient (though the labor is trivial).
 func (ifd *Ifd) FindTagWithName(tagName string) (results []*IfdTagEntry, err error) {
@@ -876,6 +1592,57 @@ func (ifd *Ifd) Thumbnail() (data []byte, err error) {
 	return ifd.thumbnailData, nil
 }
 
+// childIfdsByTag returns the collected child IFDs by the position (in
+// `entries`) of the tag that points to them: one for a child-IFD pointer and
+// one for each offset of the SubIFDs tag. The children that no tag points to
+// are returned separately, in the order that they were collected. These are
+// the IFDs of tags that were dropped when this IFD was parsed (see
+// `CollectOptions.KeepUnknownIfds`).
+func (ifd *Ifd) childIfdsByTag() (childIfds map[int][]*Ifd, unreferencedChildIfds []*Ifd) {
+	childIfds = make(map[int][]*Ifd)
+
+	for _, childIfd := range ifd.children {
+		position := ifd.childIfdTagPosition(childIfd)
+		if position < 0 {
+			unreferencedChildIfds = append(unreferencedChildIfds, childIfd)
+			continue
+		}
+
+		childIfds[position] = append(childIfds[position], childIfd)
+	}
+
+	return childIfds, unreferencedChildIfds
+}
+
+// childIfdTagPosition returns the position (in `entries`) of the tag that
+// points to the given child IFD or (-1) if there isn't one.
+func (ifd *Ifd) childIfdTagPosition(childIfd *Ifd) int {
+	ii := childIfd.ifdIdentity
+	tagId := ii.TagId()
+
+	// The position is recorded when the child is collected.
+	if childIfd.parentIfd == ifd {
+		position := childIfd.parentTagIndex
+		if position >= 0 && position < len(ifd.entries) && ifd.entries[position].TagId() == tagId {
+			return position
+		}
+	}
+
+	// Otherwise, the child is shared with another IFD (or was merged from
+	// another segment) and we have to find our own tag for it.
+	for i, ite := range ifd.entries {
+		if ite.TagId() != tagId {
+			continue
+		}
+
+		if ite.ChildIfdPath() == ii.UnindexedString() || isSubIfd(ii) == true {
+			return i
+		}
+	}
+
+	return -1
+}
+
 // dumpTags recursively builds a list of tags from an IFD.
 func (ifd *Ifd) dumpTags(tags []*IfdTagEntry) []*IfdTagEntry {
 	if tags == nil {
@@ -884,26 +1651,18 @@ func (ifd *Ifd) dumpTags(tags []*IfdTagEntry) []*IfdTagEntry {
 
 	// Now, print the tags while also descending to child-IFDS as we encounter them.
 
-	ifdsFoundCount := 0
+	childIfds, unreferencedChildIfds := ifd.childIfdsByTag()
 
-	for _, ite := range ifd.entries {
+	for i, ite := range ifd.entries {
 		tags = append(tags, ite)
 
-		childIfdPath := ite.ChildIfdPath()
-		if childIfdPath != "" {
-			ifdsFoundCount++
-
-			childIfd, found := ifd.childIfdIndex[childIfdPath]
-			if found != true {
-				log.Panicf("alien child IFD referenced by a tag: [%s]", childIfdPath)
-			}
-
+		for _, childIfd := range childIfds[i] {
 			tags = childIfd.dumpTags(tags)
 		}
 	}
 
-	if len(ifd.children) != ifdsFoundCount {
-		log.Panicf("have one or more dangling child IFDs: (%d) != (%d)", len(ifd.children), ifdsFoundCount)
+	for _, childIfd := range unreferencedChildIfds {
+		tags = childIfd.dumpTags(tags)
 	}
 
 	if ifd.nextIfd != nil {
@@ -918,7 +1677,7 @@ func (ifd *Ifd) DumpTags() []*IfdTagEntry {
 	return ifd.dumpTags(nil)
 }
 
-func (ifd *Ifd) printTagTree(populateValues bool, index, level int, nextLink bool) {
+func (ifd *Ifd) printTagTree(w io.Writer, populateValues bool, index, level int, nextLink bool) {
 	indent := strings.Repeat(" ", level*2)
 
 	prefix := " "
@@ -926,15 +1685,15 @@ func (ifd *Ifd) printTagTree(populateValues bool, index, level int, nextLink boo
 		prefix = ">"
 	}
 
-	fmt.Printf("%s%sIFD: %s\n", indent, prefix, ifd)
+	fmt.Fprintf(w, "%s%sIFD: %s\n", indent, prefix, ifd)
 
 	// Now, print the tags while also descending to child-IFDS as we encounter them.
 
-	ifdsFoundCount := 0
+	childIfds, unreferencedChildIfds := ifd.childIfdsByTag()
 
-	for _, ite := range ifd.entries {
+	for i, ite := range ifd.entries {
 		if ite.ChildIfdPath() != "" {
-			fmt.Printf("%s - TAG: %s\n", indent, ite)
+			fmt.Fprintf(w, "%s - TAG: %s\n", indent, ite)
 		} else {
 			// This will just add noise to the output (byte-tags are fully
 			// dumped).
@@ -969,37 +1728,34 @@ func (ifd *Ifd) printTagTree(populateValues bool, index, level int, nextLink boo
 				valuePhrase = "!UNRESOLVED"
 			}
 
-			fmt.Printf("%s - TAG: %s NAME=[%s] VALUE=[%v]\n", indent, ite, tagName, valuePhrase)
+			fmt.Fprintf(w, "%s - TAG: %s NAME=[%s] VALUE=[%v]\n", indent, ite, tagName, valuePhrase)
 		}
 
-		childIfdPath := ite.ChildIfdPath()
-		if childIfdPath != "" {
-			ifdsFoundCount++
-
-			childIfd, found := ifd.childIfdIndex[childIfdPath]
-			if found != true {
-				log.Panicf("alien child IFD referenced by a tag: [%s]", childIfdPath)
-			}
-
-			childIfd.printTagTree(populateValues, 0, level+1, false)
+		for _, childIfd := range childIfds[i] {
+			childIfd.printTagTree(w, populateValues, 0, level+1, false)
 		}
 	}
 
-	if len(ifd.children) != ifdsFoundCount {
-		log.Panicf("have one or more dangling child IFDs: (%d) != (%d)", len(ifd.children), ifdsFoundCount)
+	for _, childIfd := range unreferencedChildIfds {
+		childIfd.printTagTree(w, populateValues, 0, level+1, false)
 	}
 
 	if ifd.nextIfd != nil {
-		ifd.nextIfd.printTagTree(populateValues, index+1, level, true)
+		ifd.nextIfd.printTagTree(w, populateValues, index+1, level, true)
 	}
 }
 
 // PrintTagTree prints the IFD hierarchy.
 func (ifd *Ifd) PrintTagTree(populateValues bool) {
-	ifd.printTagTree(populateValues, 0, 0, false)
+	ifd.FprintTagTree(os.Stdout, populateValues)
 }
 
-func (ifd *Ifd) printIfdTree(level int, nextLink bool) {
+// FprintTagTree writes the IFD hierarchy, with tags, to the given writer.
+func (ifd *Ifd) FprintTagTree(w io.Writer, populateValues bool) {
+	ifd.printTagTree(w, populateValues, 0, 0, false)
+}
+
+func (ifd *Ifd) printIfdTree(w io.Writer, level int, nextLink bool) {
 	indent := strings.Repeat(" ", level*2)
 
 	prefix := " "
@@ -1007,38 +1763,35 @@ func (ifd *Ifd) printIfdTree(level int, nextLink bool) {
 		prefix = ">"
 	}
 
-	fmt.Printf("%s%s%s\n", indent, prefix, ifd)
+	fmt.Fprintf(w, "%s%s%s\n", indent, prefix, ifd)
 
 	// Now, print the tags while also descending to child-IFDS as we encounter them.
 
-	ifdsFoundCount := 0
-
-	for _, ite := range ifd.entries {
-		childIfdPath := ite.ChildIfdPath()
-		if childIfdPath != "" {
-			ifdsFoundCount++
-
-			childIfd, found := ifd.childIfdIndex[childIfdPath]
-			if found != true {
-				log.Panicf("alien child IFD referenced by a tag: [%s]", childIfdPath)
-			}
+	childIfds, unreferencedChildIfds := ifd.childIfdsByTag()
 
-			childIfd.printIfdTree(level+1, false)
+	for i := range ifd.entries {
+		for _, childIfd := range childIfds[i] {
+			childIfd.printIfdTree(w, level+1, false)
 		}
 	}
 
-	if len(ifd.children) != ifdsFoundCount {
-		log.Panicf("have one or more dangling child IFDs: (%d) != (%d)", len(ifd.children), ifdsFoundCount)
+	for _, childIfd := range unreferencedChildIfds {
+		childIfd.printIfdTree(w, level+1, false)
 	}
 
 	if ifd.nextIfd != nil {
-		ifd.nextIfd.printIfdTree(level, true)
+		ifd.nextIfd.printIfdTree(w, level, true)
 	}
 }
 
 // PrintIfdTree prints the IFD hierarchy.
 func (ifd *Ifd) PrintIfdTree() {
-	ifd.printIfdTree(0, false)
+	ifd.FprintIfdTree(os.Stdout)
+}
+
+// FprintIfdTree writes the IFD hierarchy to the given writer.
+func (ifd *Ifd) FprintIfdTree(w io.Writer) {
+	ifd.printIfdTree(w, 0, false)
 }
 
 func (ifd *Ifd) dumpTree(tagsDump []string, level int) []string {
@@ -1058,25 +1811,18 @@ func (ifd *Ifd) dumpTree(tagsDump []string, level int) []string {
 	startBlurb := fmt.Sprintf("%s> IFD %s TOP", indent, ifdPhrase)
 	tagsDump = append(tagsDump, startBlurb)
 
-	ifdsFoundCount := 0
-	for _, ite := range ifd.entries {
-		tagsDump = append(tagsDump, fmt.Sprintf("%s  - (0x%04x)", indent, ite.TagId()))
+	childIfds, unreferencedChildIfds := ifd.childIfdsByTag()
 
-		childIfdPath := ite.ChildIfdPath()
-		if childIfdPath != "" {
-			ifdsFoundCount++
-
-			childIfd, found := ifd.childIfdIndex[childIfdPath]
-			if found != true {
-				log.Panicf("alien child IFD referenced by a tag: [%s]", childIfdPath)
-			}
+	for i, ite := range ifd.entries {
+		tagsDump = append(tagsDump, fmt.Sprintf("%s  - (0x%04x)", indent, ite.TagId()))
 
+		for _, childIfd := range childIfds[i] {
 			tagsDump = childIfd.dumpTree(tagsDump, level+1)
 		}
 	}
 
-	if len(ifd.children) != ifdsFoundCount {
-		log.Panicf("have one or more dangling child IFDs: (%d) != (%d)", len(ifd.children), ifdsFoundCount)
+	for _, childIfd := range unreferencedChildIfds {
+		tagsDump = childIfd.dumpTree(tagsDump, level+1)
 	}
 
 	finishBlurb := fmt.Sprintf("%s< IFD %s BOTTOM", indent, ifdPhrase)
@@ -1230,32 +1976,58 @@ func (ifd *Ifd) GpsInfo() (gi *GpsInfo, err error) {
 		datePhrase := datestampValue.(string)
 		ifdEnumerateLogger.Debugf(nil, "Date tag value is [%s].", datePhrase)
 
-		// Normalize the separators.
-		datePhrase = strings.ReplaceAll(datePhrase, "-", ":")
+		timestampValue, err := timestampTags[0].Value()
+		log.PanicIf(err)
 
-		dateParts := strings.Split(datePhrase, ":")
+		timePhrase, err := timestampTags[0].Format()
+		log.PanicIf(err)
 
-		year, err1 := strconv.ParseUint(dateParts[0], 10, 16)
-		month, err2 := strconv.ParseUint(dateParts[1], 10, 8)
-		day, err3 := strconv.ParseUint(dateParts[2], 10, 8)
+		ifdEnumerateLogger.Debugf(nil, "Time tag value is [%s].", timePhrase)
 
-		if err1 == nil && err2 == nil && err3 == nil {
-			timestampValue, err := timestampTags[0].Value()
-			log.PanicIf(err)
+		timestampRaw := timestampValue.([]exifcommon.Rational)
 
-			timePhrase, err := timestampTags[0].Format()
-			log.PanicIf(err)
+		timestamp, err := parseGpsTimestamp(datePhrase, timestampRaw)
+		if err == nil {
+			gi.Timestamp = timestamp
+			gi.Present |= GpsFieldTimestamp
+		} else if log.Is(err, ErrGpsTimestampNotValid) == true {
+			ifdEnumerateLogger.Warningf(nil, "GPS timestamp not valid: [%s] %v", datePhrase, timestampRaw)
+		} else {
+			log.Panic(err)
+		}
+	}
 
-			ifdEnumerateLogger.Debugf(nil, "Time tag value is [%s].", timePhrase)
+	// Parse the optional destination, direction, and speed.
 
-			timestampRaw := timestampValue.([]exifcommon.Rational)
+	destLatitudeRaw, destLatitudeRef, foundDestLatitude := gpsRationalsWithRef(ifd, TagDestLatitudeId, TagDestLatitudeRefId, 3)
+	destLongitudeRaw, destLongitudeRef, foundDestLongitude := gpsRationalsWithRef(ifd, TagDestLongitudeId, TagDestLongitudeRefId, 3)
 
-			hour := int(timestampRaw[0].Numerator / timestampRaw[0].Denominator)
-			minute := int(timestampRaw[1].Numerator / timestampRaw[1].Denominator)
-			second := int(timestampRaw[2].Numerator / timestampRaw[2].Denominator)
+	if foundDestLatitude == true && foundDestLongitude == true {
+		gi.DestLatitude, err = NewGpsDegreesFromRationals(string([]byte{destLatitudeRef}), destLatitudeRaw)
+		log.PanicIf(err)
 
-			gi.Timestamp = time.Date(int(year), time.Month(month), int(day), hour, minute, second, 0, time.UTC)
-		}
+		gi.DestLongitude, err = NewGpsDegreesFromRationals(string([]byte{destLongitudeRef}), destLongitudeRaw)
+		log.PanicIf(err)
+
+		gi.Present |= GpsFieldDestination
+	}
+
+	if rationals, ref, found := gpsRationalsWithRef(ifd, TagDestBearingId, TagDestBearingRefId, 1); found == true {
+		gi.DestBearing = rationals[0].Float64()
+		gi.DestBearingRef = ref
+		gi.Present |= GpsFieldDestBearing
+	}
+
+	if rationals, ref, found := gpsRationalsWithRef(ifd, TagImgDirectionId, TagImgDirectionRefId, 1); found == true {
+		gi.ImgDirection = rationals[0].Float64()
+		gi.ImgDirectionRef = ref
+		gi.Present |= GpsFieldImgDirection
+	}
+
+	if rationals, ref, found := gpsRationalsWithRef(ifd, TagSpeedId, TagSpeedRefId, 1); found == true {
+		gi.Speed = rationals[0].Float64()
+		gi.SpeedRef = ref
+		gi.Present |= GpsFieldSpeed
 	}
 
 	return gi, nil
@@ -1266,7 +2038,10 @@ func (ifd *Ifd) GpsInfo() (gi *GpsInfo, err error) {
 type ParsedTagVisitor func(*Ifd, *IfdTagEntry) error
 
 // EnumerateTagsRecursively calls the given visitor function for every tag and
-// IFD in the current IFD, recursively.
+// IFD in the current IFD, recursively. The order is deterministic: the tags of
+// each IFD are visited in stream order, descending into a child IFD where its
+// tag appears, and then the rest of the IFD chain is visited. The first error
+// returned by the visitor stops the enumeration and is returned as-is.
 func (ifd *Ifd) EnumerateTagsRecursively(visitor ParsedTagVisitor) (err error) {
 	defer func() {
 		if state := recover(); state != nil {
@@ -1275,16 +2050,30 @@ func (ifd *Ifd) EnumerateTagsRecursively(visitor ParsedTagVisitor) (err error) {
 	}()
 
 	for ptr := ifd; ptr != nil; ptr = ptr.nextIfd {
-		for _, ite := range ifd.entries {
-			childIfdPath := ite.ChildIfdPath()
-			if childIfdPath != "" {
-				childIfd := ifd.childIfdIndex[childIfdPath]
+		childIfds, unreferencedChildIfds := ptr.childIfdsByTag()
 
+		for i, ite := range ptr.entries {
+			// The tags that point to child IFDs aren't visited themselves,
+			// but SubIFDs is a list of offsets and is.
+			if ite.ChildIfdPath() == "" {
+				err := visitor(ptr, ite)
+				if err != nil {
+					return err
+				}
+			}
+
+			for _, childIfd := range childIfds[i] {
 				err := childIfd.EnumerateTagsRecursively(visitor)
-				log.PanicIf(err)
-			} else {
-				err := visitor(ifd, ite)
-				log.PanicIf(err)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		for _, childIfd := range unreferencedChildIfds {
+			err := childIfd.EnumerateTagsRecursively(visitor)
+			if err != nil {
+				return err
 			}
 		}
 	}
@@ -1292,6 +2081,125 @@ func (ifd *Ifd) EnumerateTagsRecursively(visitor ParsedTagVisitor) (err error) {
 	return nil
 }
 
+// IfdWalkVisitor is a callback used by `Walk()`. `depth` is zero for the IFD
+// that the walk started at and its siblings and one more for each level of
+// child IFDs.
+type IfdWalkVisitor func(ifd *Ifd, depth int) error
+
+// Walk calls the given visitor for the current IFD and every IFD below it in
+// breadth-first order. The IFDs that follow in the chain (via the next-IFD
+// links) are siblings and are visited at the same depth. Each IFD is visited
+// once. The first error returned by the visitor stops the walk and is
+// returned as-is.
+func (ifd *Ifd) Walk(visitor IfdWalkVisitor) (err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	type walkItem struct {
+		ifd   *Ifd
+		depth int
+	}
+
+	visited := make(map[*Ifd]struct{})
+	queue := []walkItem{{ifd: ifd, depth: 0}}
+
+	for len(queue) > 0 {
+		item := queue[0]
+		queue = queue[1:]
+
+		if _, found := visited[item.ifd]; found == true {
+			continue
+		}
+
+		visited[item.ifd] = struct{}{}
+
+		err := visitor(item.ifd, item.depth)
+		if err != nil {
+			return err
+		}
+
+		if item.ifd.nextIfd != nil {
+			queue = append(queue, walkItem{ifd: item.ifd.nextIfd, depth: item.depth})
+		}
+
+		for _, childIfd := range item.ifd.children {
+			queue = append(queue, walkItem{ifd: childIfd, depth: item.depth + 1})
+		}
+	}
+
+	return nil
+}
+
+// Clone returns a deep copy of the IFD along with its children, the rest of
+// its chain, and its MakerNote IFD. The tag entries are copied, so the clone
+// can be modified without affecting the original. An IFD that appears more
+// than once in the tree (see CollectWithOptions()) is only copied once and
+// is shared the same way in the clone. The parent of this IFD is outside of
+// what is copied, so the clone refers to the original parent.
+func (ifd *Ifd) Clone() *Ifd {
+	clones := make(map[*Ifd]*Ifd)
+	cloned := ifd.cloneInto(clones)
+
+	// Only now is every parent guaranteed to have been cloned.
+	for original, clone := range clones {
+		if parentClone, found := clones[original.parentIfd]; found == true {
+			clone.parentIfd = parentClone
+		}
+	}
+
+	return cloned
+}
+
+// cloneInto copies the IFD and everything that it links to that isn't already
+// in `clones`.
+func (ifd *Ifd) cloneInto(clones map[*Ifd]*Ifd) *Ifd {
+	if ifd == nil {
+		return nil
+	} else if clone, found := clones[ifd]; found == true {
+		return clone
+	}
+
+	clone := new(Ifd)
+	*clone = *ifd
+
+	clones[ifd] = clone
+
+	clone.entries = make([]*IfdTagEntry, len(ifd.entries))
+	clone.entriesByTagId = make(map[uint16][]*IfdTagEntry)
+
+	for i, ite := range ifd.entries {
+		iteClone := ite.clone()
+
+		clone.entries[i] = iteClone
+		clone.entriesByTagId[ite.tagId] = append(clone.entriesByTagId[ite.tagId], iteClone)
+	}
+
+	if ifd.thumbnailData != nil {
+		clone.thumbnailData = make([]byte, len(ifd.thumbnailData))
+		copy(clone.thumbnailData, ifd.thumbnailData)
+	}
+
+	clone.children = make([]*Ifd, len(ifd.children))
+	for i, childIfd := range ifd.children {
+		clone.children[i] = childIfd.cloneInto(clones)
+	}
+
+	if ifd.childIfdIndex != nil {
+		clone.childIfdIndex = make(map[string]*Ifd, len(ifd.childIfdIndex))
+		for childIfdPath, childIfd := range ifd.childIfdIndex {
+			clone.childIfdIndex[childIfdPath] = childIfd.cloneInto(clones)
+		}
+	}
+
+	clone.nextIfd = ifd.nextIfd.cloneInto(clones)
+	clone.makerNoteIfd = ifd.makerNoteIfd.cloneInto(clones)
+
+	return clone
+}
+
 // QueuedIfd is one IFD that has been identified but yet to be processed.
 type QueuedIfd struct {
 	IfdIdentity *exifcommon.IfdIdentity
@@ -1303,6 +2211,14 @@ type QueuedIfd struct {
 	// (if `ParentIfd` is not nil and we weren't an IFD referenced as a sibling
 	// instead of as a child).
 	ParentTagIndex int
+
+	// Depth is how many child-IFD links are between the root IFD and this
+	// one. Siblings share the same depth.
+	Depth int
+
+	// isUnknown is true if this IFD was found through a tag with the TIFF
+	// IFD type and will be parsed raw. See `CollectOptions.KeepUnknownIfds`.
+	isUnknown bool
 }
 
 // IfdIndex collects a bunch of IFD and tag information stored in several
@@ -1312,10 +2228,326 @@ type IfdIndex struct {
 	Ifds    []*Ifd
 	Tree    map[int]*Ifd
 	Lookup  map[string]*Ifd
+
+	// SkippedIfds are the IFDs that were found but not parsed.
+	SkippedIfds []SkippedIfd
+
+	// TagWarnings are the tags whose values could not be captured. Only
+	// populated when `CollectOptions.CaptureValues` is true.
+	TagWarnings []TagWarning
+}
+
+const (
+	// SkipReasonAlreadyParsed means that the IFD was linked-to more than once
+	// (e.g. a cycle).
+	SkipReasonAlreadyParsed = "already parsed"
+
+	// SkipReasonTooDeep means that the IFD was nested deeper than allowed.
+	SkipReasonTooDeep = "too deep"
+
+	// SkipReasonNoMakerNoteParser means that there was a MakerNote but no
+	// parser registered for its make (or the parser failed). See
+	// RegisterMakerNoteParser().
+	SkipReasonNoMakerNoteParser = "maker-note not parsed"
+
+	// SkipReasonOffsetInvalid means that the IFD is not entirely within the
+	// data (e.g. a corrupt pointer to a child IFD).
+	SkipReasonOffsetInvalid = "offset invalid"
+)
+
+// SkippedIfd describes an IFD that Collect() saw but did not parse.
+type SkippedIfd struct {
+	// FqIfdPath is the fully-qualified path that the IFD would have had.
+	FqIfdPath string
+
+	// Index is the position of the IFD in its chain.
+	Index int
+
+	// Offset is where the IFD starts.
+	Offset uint32
+
+	// TagId is the tag that pointed to the IFD, or zero if it was linked-to
+	// from the previous IFD in the chain.
+	TagId uint16
+
+	// Reason is one of the SkipReason constants.
+	Reason string
+}
+
+// String returns a descriptive string.
+func (si SkippedIfd) String() string {
+	return fmt.Sprintf("SkippedIfd<FQ-IFD-PATH=[%s] INDEX=(%d) OFFSET=(0x%08x) TAG-ID=(0x%04x) REASON=[%s]>", si.FqIfdPath, si.Index, si.Offset, si.TagId, si.Reason)
+}
+
+const (
+	// TagWarningValueOutOfRange means that the value of the tag runs past the
+	// end of the data (e.g. a corrupt value offset).
+	TagWarningValueOutOfRange = "value out of range"
+)
+
+// TagWarning describes a tag that Collect() parsed but whose value could not
+// be decoded. The tag is still in the tree.
+type TagWarning struct {
+	// FqIfdPath is the fully-qualified path of the IFD that has the tag.
+	FqIfdPath string
+
+	// TagIndex is the position of the tag in its IFD.
+	TagIndex int
+
+	// TagId is the tag's ID.
+	TagId uint16
+
+	// TagName is the tag's name, or empty if it isn't known.
+	TagName string
+
+	// ValueOffset is where the value was supposed to be.
+	ValueOffset uint32
+
+	// Reason is one of the TagWarning constants.
+	Reason string
+}
+
+// String returns a descriptive string.
+func (tw TagWarning) String() string {
+	return fmt.Sprintf("TagWarning<FQ-IFD-PATH=[%s] TAG-INDEX=(%d) TAG-ID=(0x%04x) TAG-NAME=[%s] VALUE-OFFSET=(0x%08x) REASON=[%s]>", tw.FqIfdPath, tw.TagIndex, tw.TagId, tw.TagName, tw.ValueOffset, tw.Reason)
+}
+
+// FindTag returns the first tag with the given tag-ID in the IFD with the given
+// fully-qualified IFD-path (e.g. "IFD/Exif") along with the IFD that contains
+// it. ErrTagNotFound is returned (unwrapped) if either the IFD or the tag is
+// not present.
+func (index IfdIndex) FindTag(fqIfdPath string, tagId uint16) (ite *IfdTagEntry, ifd *Ifd, err error) {
+	ifd, found := index.Lookup[fqIfdPath]
+	if found == false {
+		return nil, nil, ErrTagNotFound
+	}
+
+	ite, found = ifd.EntryByTagId(tagId)
+	if found == false {
+		return nil, nil, ErrTagNotFound
+	}
+
+	return ite, ifd, nil
+}
+
+// CountTags returns the number of tags in every IFD that was collected (the
+// chain, the children, and the MakerNote IFD if one was parsed). An IFD that
+// was linked-to from more than one place is only counted once.
+func (index IfdIndex) CountTags() int {
+	count := 0
+	for _, ifd := range index.Ifds {
+		count += len(ifd.entries)
+	}
+
+	return count
+}
+
+// RootIfds returns the root IFD followed by each IFD that follows it in the
+// chain (via the next-IFD links). In a multi-page TIFF, these are the pages,
+// in the order that they are stored. In a JPEG, the second is usually the
+// thumbnail IFD (IFD1). The children of each are under that IFD and not in
+// the list (see `Ifd.Children()`).
+func (index IfdIndex) RootIfds() []*Ifd {
+	rootIfds := make([]*Ifd, 0)
+
+	visited := make(map[*Ifd]struct{})
+	for ifd := index.RootIfd; ifd != nil; ifd = ifd.nextIfd {
+		if _, found := visited[ifd]; found == true {
+			break
+		}
+
+		visited[ifd] = struct{}{}
+		rootIfds = append(rootIfds, ifd)
+	}
+
+	return rootIfds
+}
+
+// IfdsByName returns every collected IFD with the given name (e.g. "Exif",
+// "GPSInfo", or "IFD" for the root IFD and each IFD that follows it in the
+// chain), in the order that they were collected. This is the way to find all
+// of the instances of an IFD in a multi-page file: `Ifd.Children()` only has
+// the children of one IFD, and Lookup only has one IFD for each path, but
+// Ifds has every IFD that was collected. An empty list is returned if there
+// are none.
+func (index IfdIndex) IfdsByName(name string) []*Ifd {
+	ifds := make([]*Ifd, 0)
+	for _, ifd := range index.Ifds {
+		if ifd.ifdIdentity.Name() == name {
+			ifds = append(ifds, ifd)
+		}
+	}
+
+	return ifds
+}
+
+// DateTimeOriginal returns the DateTimeOriginal tag from the Exif IFD. If the
+// OffsetTimeOriginal tag is present, it is used as the timezone. Otherwise,
+// the time is returned as UTC. If the SubSecTimeOriginal tag is present, it
+// is added (a sub-second time that isn't valid is logged and ignored).
+// ErrTagNotFound is returned (unwrapped) if the
+// tag is missing or only has the placeholder spaces for an unknown time, and
+// ErrDateTimeNotValid if it can not be parsed.
+func (index IfdIndex) DateTimeOriginal() (t time.Time, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	exifIfdPath := exifcommon.IfdExifStandardIfdIdentity.String()
+
+	ite, exifIfd, err := index.FindTag(exifIfdPath, 0x9003)
+	if err != nil {
+		if err == ErrTagNotFound {
+			return time.Time{}, err
+		}
+
+		log.Panic(err)
+	}
+
+	dateTimePhrase, err := asciiTagValue(ite)
+	log.PanicIf(err)
+
+	if isDateTimeUnset(dateTimePhrase) == true {
+		return time.Time{}, ErrTagNotFound
+	}
+
+	offsetPhrase := ""
+	if offsetIte, found := exifIfd.EntryByTagId(0x9011); found == true {
+		offsetPhrase, err = asciiTagValue(offsetIte)
+		log.PanicIf(err)
+	}
+
+	t, err = parseExifDateTime(dateTimePhrase, offsetPhrase)
+	if err != nil {
+		if err == ErrDateTimeNotValid {
+			return time.Time{}, err
+		}
+
+		log.Panic(err)
+	}
+
+	subSec, err := index.SubSecTimeOriginal()
+	if err == nil {
+		t = t.Add(subSec)
+	} else if err != ErrTagNotFound && err != ErrDateTimeNotValid {
+		log.Panic(err)
+	}
+
+	return t, nil
+}
+
+// SubSecTimeOriginal returns the SubSecTimeOriginal tag from the Exif IFD,
+// which is the fraction of a second that goes with DateTimeOriginal. The
+// digits are after the decimal point, so "30" is 300 milliseconds.
+// ErrTagNotFound is returned (unwrapped) if the tag is missing or empty, and
+// ErrDateTimeNotValid if it has anything other than digits.
+func (index IfdIndex) SubSecTimeOriginal() (d time.Duration, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	exifIfdPath := exifcommon.IfdExifStandardIfdIdentity.String()
+
+	ite, _, err := index.FindTag(exifIfdPath, 0x9291)
+	if err != nil {
+		if err == ErrTagNotFound {
+			return 0, err
+		}
+
+		log.Panic(err)
+	}
+
+	subSecPhrase, err := asciiTagValue(ite)
+	log.PanicIf(err)
+
+	if strings.TrimSpace(strings.TrimRight(subSecPhrase, "\000")) == "" {
+		return 0, ErrTagNotFound
+	}
+
+	d, err = parseExifSubSecTime(subSecPhrase)
+	if err != nil {
+		if err == ErrDateTimeNotValid {
+			return 0, err
+		}
+
+		log.Panic(err)
+	}
+
+	return d, nil
+}
+
+// GpsInfo finds the GPS IFD and returns the GPS info from it. ErrNoGpsIfd is
+// returned (unwrapped) if there is no GPS IFD. See `Ifd.GpsInfo()`.
+func (index IfdIndex) GpsInfo() (gi *GpsInfo, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	ifd, found := index.Lookup[exifcommon.IfdGpsInfoStandardIfdIdentity.String()]
+	if found == false {
+		return nil, ErrNoGpsIfd
+	}
+
+	gi, err = ifd.GpsInfo()
+	log.PanicIf(err)
+
+	return gi, nil
+}
+
+const (
+	// DefaultMaxIfdCount is the most IFDs that Collect() will parse unless
+	// told otherwise. Real images have a handful. This only exists to put a
+	// ceiling on the work done for malicious data.
+	DefaultMaxIfdCount = 1000
+
+	// DefaultMaxIfdDepth is the deepest that Collect() will descend into
+	// child IFDs unless told otherwise. The standard IFDs are at most two
+	// levels down (IFD/Exif/Iop).
+	DefaultMaxIfdDepth = 16
+)
+
+// CollectOptions tweaks collection behavior.
+type CollectOptions struct {
+	// MaxIfdCount is the most IFDs that will be parsed before failing with
+	// ErrTooManyIfds. Defaults to DefaultMaxIfdCount.
+	MaxIfdCount int
+
+	// MaxDepth is the deepest child IFD that will be parsed, where the root
+	// IFD is at depth zero. Deeper IFDs are logged and skipped. Defaults to
+	// DefaultMaxIfdDepth.
+	MaxDepth int
+
+	// RootOnly only parses the root IFD and the rest of its chain (e.g. IFD1).
+	// Child IFDs (e.g. Exif and GPS) are not parsed, though the tags that
+	// point to them are still returned.
+	RootOnly bool
+
+	// CaptureValues reads the stored bytes of every tag's value while the
+	// tree is collected so that they are available from
+	// `IfdTagEntry.RawValue()`. This uses more memory. Values that run past
+	// the end of the data are left nil and recorded in `IfdIndex.TagWarnings`
+	// (or fail with ErrOffsetInvalid in strict mode).
+	CaptureValues bool
+
+	// KeepUnknownIfds parses the IFDs that are pointed to by tags with the
+	// TIFF IFD type (13), which are otherwise dropped, and attaches them to
+	// the tree as children named by UnknownIfdName() (e.g.
+	// "IFD/Unknown<0xc634>"). Their tags are parsed without the tag index, so
+	// they have no names, and are raw-only: their values are always captured
+	// and UNDEFINED values are never decoded. See `IfdTagEntry.IsRawOnly()`.
+	// Their next-IFD links and any children are not followed.
+	KeepUnknownIfds bool
 }
 
 // Collect enumerates the different EXIF blocks (called IFDs) and builds out an
-// index struct for referencing all of the parsed data.
+// index struct for referencing all of the parsed data. If the enumerator
+// wasn't built with NewIfdEnumerateWithBytes(), run ValidateHeader() first.
 func (ie *IfdEnumerate) Collect(rootIfdOffset uint32) (index IfdIndex, err error) {
 	defer func() {
 		if state := recover(); state != nil {
@@ -1323,8 +2555,99 @@ func (ie *IfdEnumerate) Collect(rootIfdOffset uint32) (index IfdIndex, err error
 		}
 	}()
 
+	index, err = ie.CollectWithOptions(rootIfdOffset, nil)
+	if err != nil {
+		if err == ErrTooManyIfds || asKnownReadError(err) != nil {
+			return index, err
+		}
+
+		log.Panic(err)
+	}
+
+	return index, nil
+}
+
+// CollectContext is the same as Collect() but stops before the next IFD once
+// `ctx` is done. In that case, `ctx.Err()` is returned as-is.
+func (ie *IfdEnumerate) CollectContext(ctx context.Context, rootIfdOffset uint32) (index IfdIndex, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	index, err = ie.collect(ctx, rootIfdOffset, nil)
+	if err != nil {
+		if err == ErrTooManyIfds || err == ctx.Err() || asKnownReadError(err) != nil {
+			return index, err
+		}
+
+		log.Panic(err)
+	}
+
+	return index, nil
+}
+
+// CollectWithOptions is the same as Collect() but accepts options. `co` may be
+// nil.
+//
+// An IFD will only be parsed once for any given IFD-path and offset. If
+// something links back to an IFD that we have already seen (e.g. a cycle in
+// the next-IFD chain) then it is logged and skipped. If a tag in another IFD
+// points to a child IFD that we have already seen, the existing IFD is
+// attached to it as a child rather than parsing it again. Children with
+// different names are never shared, even if they have the same offset (e.g. a
+// broken writer pointing both the Exif and GPS tags at one IFD). Unless in
+// strict mode, an IFD other than the root that isn't entirely within the data
+// is recorded in `IfdIndex.SkippedIfds` rather than failing the whole collect.
+func (ie *IfdEnumerate) CollectWithOptions(rootIfdOffset uint32, co *CollectOptions) (index IfdIndex, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	index, err = ie.collect(context.Background(), rootIfdOffset, co)
+	if err != nil {
+		if err == ErrTooManyIfds || asKnownReadError(err) != nil {
+			return index, err
+		}
+
+		log.Panic(err)
+	}
+
+	return index, nil
+}
+
+func (ie *IfdEnumerate) collect(ctx context.Context, rootIfdOffset uint32, co *CollectOptions) (index IfdIndex, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
 	// TODO(dustin): Add MiscellaneousExifData to IfdIndex
 
+	if co == nil {
+		co = new(CollectOptions)
+	}
+
+	maxIfdCount := co.MaxIfdCount
+	if maxIfdCount == 0 {
+		maxIfdCount = DefaultMaxIfdCount
+	}
+
+	maxDepth := co.MaxDepth
+	if maxDepth == 0 {
+		maxDepth = DefaultMaxIfdDepth
+	}
+
+	// Offsets that have already been parsed, by IFD-path.
+	visited := make(map[string]map[uint32]*Ifd)
+
+	// The IFD-paths of the children that have already been parsed, by offset.
+	childOffsets := make(map[uint32]string)
+
 	tree := make(map[int]*Ifd)
 	ifds := make([]*Ifd, 0)
 	lookup := make(map[string]*Ifd)
@@ -1338,24 +2661,117 @@ func (ie *IfdEnumerate) Collect(rootIfdOffset uint32) (index IfdIndex, err error
 
 	edges := make(map[uint32]*Ifd)
 
+	skippedIfds := make([]SkippedIfd, 0)
+
+	skip := func(qi QueuedIfd, reason string) {
+		tagId := uint16(0)
+		if qi.Parent != nil {
+			tagId = qi.IfdIdentity.TagId()
+		}
+
+		si := SkippedIfd{
+			FqIfdPath: qi.IfdIdentity.String(),
+			Index:     qi.IfdIdentity.Index(),
+			Offset:    qi.Offset,
+			TagId:     tagId,
+			Reason:    reason,
+		}
+
+		skippedIfds = append(skippedIfds, si)
+	}
+
+	tagWarnings := make([]TagWarning, 0)
+
 	for {
 		if len(queue) == 0 {
 			break
 		}
 
+		if err := ctx.Err(); err != nil {
+			return IfdIndex{}, err
+		}
+
 		qi := queue[0]
 		ii := qi.IfdIdentity
 
 		offset := qi.Offset
 		parentIfd := qi.Parent
+		depth := qi.Depth
 
 		queue = queue[1:]
 
+		ifdPath := ii.UnindexedString()
+
+		visitedOffsets, found := visited[ifdPath]
+		if found == false {
+			visitedOffsets = make(map[uint32]*Ifd)
+			visited[ifdPath] = visitedOffsets
+		}
+
+		if visitedIfd, found := visitedOffsets[offset]; found == true {
+			if parentIfd != nil {
+				ifdEnumerateLogger.Debugf(nil, "IFD [%s] at offset (0x%08x) has already been parsed. Sharing it with [%s].", ii.String(), offset, parentIfd.ifdIdentity.String())
+
+				isChild := false
+				for _, childIfd := range parentIfd.children {
+					if childIfd == visitedIfd {
+						isChild = true
+						break
+					}
+				}
+
+				if isChild == false {
+					parentIfd.children = append(parentIfd.children, visitedIfd)
+				}
+
+				continue
+			}
+
+			if ie.strictMode == true {
+				return IfdIndex{}, ErrIfdCycle
+			}
+
+			ifdEnumerateLogger.Warningf(nil, "IFD [%s] at offset (0x%08x) has already been parsed. There might be a cycle. Skipping.", ii.String(), offset)
+			skip(qi, SkipReasonAlreadyParsed)
+
+			continue
+		}
+
+		if parentIfd != nil {
+			if otherIfdPath, found := childOffsets[offset]; found == true && otherIfdPath != ifdPath {
+				ifdEnumerateLogger.Warningf(nil, "IFD [%s] at offset (0x%08x) has the same offset as IFD [%s]. It will be parsed separately.", ii.String(), offset, otherIfdPath)
+			} else {
+				childOffsets[offset] = ifdPath
+			}
+		}
+
+		if depth > maxDepth {
+			ifdEnumerateLogger.Warningf(nil, "IFD [%s] at offset (0x%08x) is nested deeper than (%d). Skipping.", ii.String(), offset, maxDepth)
+			skip(qi, SkipReasonTooDeep)
+
+			continue
+		}
+
+		if len(ifds) >= maxIfdCount {
+			ifdEnumerateLogger.Warningf(nil, "More than (%d) IFDs were found. Giving up.", maxIfdCount)
+			return IfdIndex{}, ErrTooManyIfds
+		}
+
 		ifdEnumerateLogger.Debugf(nil, "Parsing IFD [%s] (%d) at offset (0x%04x) (Collect).", ii.String(), ii.Index(), offset)
 
+		// Only the root IFD is required. Otherwise, in lenient mode, an IFD
+		// that isn't entirely within the data is skipped so that one corrupt
+		// pointer doesn't cost us the rest of the tree.
+		isRequired := len(ifds) == 0 || ie.strictMode == true
+
 		bp, err := ie.getByteParser(offset)
 		if err != nil {
-			if err == ErrOffsetInvalid {
+			if err == ErrOffsetInvalid && isRequired == false {
+				ifdEnumerateLogger.Warningf(nil, "IFD [%s] at offset (0x%08x) is beyond the end of the data. Skipping.", ii.String(), offset)
+				skip(qi, SkipReasonOffsetInvalid)
+
+				continue
+			} else if err == ErrOffsetInvalid {
 				return index, err
 			}
 
@@ -1364,14 +2780,81 @@ func (ie *IfdEnumerate) Collect(rootIfdOffset uint32) (index IfdIndex, err error
 
 		// TODO(dustin): We don't need to pass the index in as a separate argument. Get from the II.
 
-		nextIfdOffset, entries, thumbnailData, err := ie.parseIfd(ii, bp, nil, false, nil)
-		log.PanicIf(err)
+		var nextIfdOffset uint32
+		var entries []*IfdTagEntry
+		var thumbnailData []byte
+
+		if qi.isUnknown == true {
+			var rawIfd *Ifd
+
+			rawIfd, err = ie.ParseRawIfd(ii, offset)
+			if err == nil {
+				entries = rawIfd.entries
+
+				for _, ite := range entries {
+					ite.isRawOnly = true
+					ite.valueBudget = ie.valueBudget
+				}
+			}
+		} else {
+			nextIfdOffset, entries, thumbnailData, err = ie.parseIfd(ctx, ii, bp, nil, false, nil)
+		}
+
+		if err != nil {
+			knownErr := asKnownReadError(err)
+			if (knownErr == ErrTagCountInvalid || knownErr == ErrTruncatedData) && isRequired == false {
+				ifdEnumerateLogger.Warningf(nil, "IFD [%s] at offset (0x%08x) runs past the end of the data. Skipping.", ii.String(), offset)
+				skip(qi, SkipReasonOffsetInvalid)
+
+				continue
+			} else if knownErr != nil {
+				return IfdIndex{}, knownErr
+			}
+
+			log.Panic(err)
+		}
 
 		currentOffset := bp.CurrentOffset()
 		if currentOffset > ie.furthestOffset {
 			ie.furthestOffset = currentOffset
 		}
 
+		if co.CaptureValues == true || qi.isUnknown == true {
+			for _, ite := range entries {
+				if ite.TagType().IsValid() == false {
+					continue
+				}
+
+				rawValue, err := ite.effectiveValueBytes()
+				if err != nil {
+					if err == exifcommon.ErrNotEnoughData && ie.strictMode == true {
+						return IfdIndex{}, ErrOffsetInvalid
+					} else if err == exifcommon.ErrNotEnoughData {
+						ifdEnumerateLogger.Warningf(nil, "Value for tag (0x%04x) in IFD [%s] could not be captured.", ite.TagId(), ii.String())
+
+						tw := TagWarning{
+							FqIfdPath:   ii.String(),
+							TagIndex:    ite.tagIndex,
+							TagId:       ite.TagId(),
+							TagName:     ite.TagName(),
+							ValueOffset: ite.getValueOffset(),
+							Reason:      TagWarningValueOutOfRange,
+						}
+
+						tagWarnings = append(tagWarnings, tw)
+
+						continue
+					} else if err == ErrValueBudgetExceeded {
+						return IfdIndex{}, err
+					}
+
+					log.Panic(err)
+				}
+
+				ite.rawValue = rawValue
+			}
+		}
+
 		id := len(ifds)
 
 		entriesByTagId := make(map[uint16][]*IfdTagEntry)
@@ -1410,6 +2893,8 @@ func (ie *IfdEnumerate) Collect(rootIfdOffset uint32) (index IfdIndex, err error
 			tagIndex:   ie.tagIndex,
 		}
 
+		visitedOffsets[offset] = ifd
+
 		// Add ourselves to a big list of IFDs.
 		ifds = append(ifds, ifd)
 
@@ -1430,34 +2915,71 @@ func (ie *IfdEnumerate) Collect(rootIfdOffset uint32) (index IfdIndex, err error
 			parentIfd.children = append(parentIfd.children, ifd)
 		}
 
-		// Determine if any of our entries is a child IFD and queue it.
-		for i, ite := range entries {
-			if ite.ChildIfdPath() == "" {
-				continue
+		if ii.UnindexedString() == exifcommon.IfdExifStandardIfdIdentity.UnindexedString() {
+			makerNoteIfd, err := ie.parseMakerNote(tree[0], ifd)
+			log.PanicIf(err)
+
+			if makerNoteIfd != nil {
+				makerNoteIfd.id = len(ifds)
+
+				ifds = append(ifds, makerNoteIfd)
+				tree[makerNoteIfd.id] = makerNoteIfd
+				lookup[makerNoteIfd.ifdIdentity.String()] = makerNoteIfd
+
+				ifd.makerNoteIfd = makerNoteIfd
+			} else if makerNoteIte, found := ifd.EntryByTagId(MakerNoteTagId); found == true {
+				exifIfdTag := ii.IfdTag()
+				makerNoteIfdTag := exifcommon.NewIfdTag(&exifIfdTag, MakerNoteTagId, MakerNoteIfdName)
+
+				makerNoteQi := QueuedIfd{
+					IfdIdentity: ii.NewChild(makerNoteIfdTag, 0),
+					Offset:      makerNoteIte.getValueOffset(),
+					Parent:      ifd,
+				}
+
+				skip(makerNoteQi, SkipReasonNoMakerNoteParser)
 			}
+		}
 
-			tagId := ite.TagId()
-			childIfdName := ite.ChildIfdName()
+		// Determine if any of our entries is a child IFD and queue it.
+		if co.RootOnly == false && qi.isUnknown == false {
+			for i, ite := range entries {
+				// This was resolved when the tag was parsed.
+				iiChild := ite.getChildIfdIdentity()
+				if iiChild == nil {
+					continue
+				}
 
-			currentIfdTag := ii.IfdTag()
+				qi := QueuedIfd{
+					IfdIdentity: iiChild,
 
-			childIfdTag :=
-				exifcommon.NewIfdTag(
-					&currentIfdTag,
-					tagId,
-					childIfdName)
+					Offset:         ite.getValueOffset(),
+					Parent:         ifd,
+					ParentTagIndex: i,
+					Depth:          depth + 1,
+				}
 
-			iiChild := ii.NewChild(childIfdTag, 0)
+				queue = append(queue, qi)
+			}
 
-			qi := QueuedIfd{
-				IfdIdentity: iiChild,
+			// The SubIFDs tag refers to any number of child IFDs.
+			for i, ite := range entries {
+				if ite.TagId() != SubIfdsTagId || ite.ChildIfdPath() != "" {
+					continue
+				}
 
-				Offset:         ite.getValueOffset(),
-				Parent:         ifd,
-				ParentTagIndex: i,
+				subIfdQis, err := subIfdQueue(ifd, ite, i, depth)
+				log.PanicIf(err)
+
+				queue = append(queue, subIfdQis...)
 			}
 
-			queue = append(queue, qi)
+			if co.KeepUnknownIfds == true {
+				unknownIfdQis, err := ie.unknownIfdQueue(ifd, depth)
+				log.PanicIf(err)
+
+				queue = append(queue, unknownIfdQis...)
+			}
 		}
 
 		// If there's another IFD in the chain.
@@ -1470,6 +2992,7 @@ func (ie *IfdEnumerate) Collect(rootIfdOffset uint32) (index IfdIndex, err error
 			qi := QueuedIfd{
 				IfdIdentity: iiSibling,
 				Offset:      nextIfdOffset,
+				Depth:       depth,
 			}
 
 			queue = append(queue, qi)
@@ -1480,6 +3003,8 @@ func (ie *IfdEnumerate) Collect(rootIfdOffset uint32) (index IfdIndex, err error
 	index.Ifds = ifds
 	index.Tree = tree
 	index.Lookup = lookup
+	index.SkippedIfds = skippedIfds
+	index.TagWarnings = tagWarnings
 
 	err = ie.setChildrenIndex(index.RootIfd)
 	log.PanicIf(err)
@@ -1496,9 +3021,11 @@ func (ie *IfdEnumerate) setChildrenIndex(ifd *Ifd) (err error) {
 		}
 	}()
 
+	// Several children can have the same unindexed path (e.g. "IFD/SubIFD"
+	// and "IFD/SubIFD1"), so they are keyed by the indexed one.
 	childIfdIndex := make(map[string]*Ifd)
 	for _, childIfd := range ifd.children {
-		childIfdIndex[childIfd.ifdIdentity.UnindexedString()] = childIfd
+		childIfdIndex[childIfd.ifdIdentity.String()] = childIfd
 	}
 
 	ifd.childIfdIndex = childIfdIndex
@@ -1511,6 +3038,141 @@ func (ie *IfdEnumerate) setChildrenIndex(ifd *Ifd) (err error) {
 	return nil
 }
 
+// collectFromHeader reads the TIFF header at the front of our data for the
+// offset of the root IFD and collects the tree with a new enumerator (see
+// headerEnumerator()). The enumerator is returned so that values can be read
+// with it. `co` may be nil.
+func (ie *IfdEnumerate) collectFromHeader(co *CollectOptions) (collectIe *IfdEnumerate, index IfdIndex, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	collectIe, eh, err := ie.headerEnumerator()
+	log.PanicIf(err)
+
+	index, err = collectIe.CollectWithOptions(eh.FirstIfdOffset, co)
+	log.PanicIf(err)
+
+	return collectIe, index, nil
+}
+
+// headerEnumerator returns a new enumerator, with the same settings as this
+// one, for the data that this one reads, along with the header at the front
+// of that data. The accessors that parse the data from the top (Orientation(),
+// RootIfds(), CountTags(), etc..) use one of these rather than this
+// enumerator, since a parse records the IFDs that it visits and the furthest
+// offset that it reads on the enumerator. Parsing again with this one would
+// end the chain at any IFD that the caller's own Collect() has already
+// visited and would change what FurthestOffset() returns.
+func (ie *IfdEnumerate) headerEnumerator() (headerIe *IfdEnumerate, eh ExifHeader, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	// If we were given bytes, share them rather than copying them.
+	var exifData []byte
+	if ers, ok := ie.ebs.(*ExifReadSeeker); ok == true && ers.exifData != nil {
+		exifData = ers.exifData
+	} else {
+		rs, err := ie.ebs.GetReadSeeker(0)
+		log.PanicIf(err)
+
+		exifData, err = ioutil.ReadAll(rs)
+		log.PanicIf(err)
+	}
+
+	eh, err = ParseExifHeader(exifData)
+	log.PanicIf(err)
+
+	ebs := NewExifReadSeekerWithBytes(exifData)
+	headerIe = NewIfdEnumerate(ie.ifdMapping, ie.tagIndex, ebs, eh.ByteOrder)
+	headerIe.strictMode = ie.strictMode
+	headerIe.keepRawEntries = ie.keepRawEntries
+	headerIe.valueBudget = ie.valueBudget
+
+	return headerIe, eh, nil
+}
+
+// getHeaderIndex returns the tree that the accessors consult, collecting it
+// from the header the first time. If `rootOnly` is true, only the root chain
+// is collected (see ParseRootChain()). The same tree is returned until one of
+// the settings changes.
+func (ie *IfdEnumerate) getHeaderIndex(rootOnly bool) (index *IfdIndex, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	if rootOnly == true && ie.rootChainIndex != nil {
+		return ie.rootChainIndex, nil
+	} else if rootOnly == false && ie.headerIndex != nil {
+		return ie.headerIndex, nil
+	}
+
+	co := &CollectOptions{
+		RootOnly: rootOnly,
+	}
+
+	_, collectedIndex, err := ie.collectFromHeader(co)
+	log.PanicIf(err)
+
+	if rootOnly == true {
+		ie.rootChainIndex = &collectedIndex
+	} else {
+		ie.headerIndex = &collectedIndex
+	}
+
+	return &collectedIndex, nil
+}
+
+// dropHeaderIndexes discards the trees that getHeaderIndex() has collected so
+// that they are collected again with the current settings.
+func (ie *IfdEnumerate) dropHeaderIndexes() {
+	ie.headerIndex = nil
+	ie.rootChainIndex = nil
+}
+
+// RootIfds returns the root IFD followed by each IFD that follows it in the
+// chain (e.g. the pages of a multi-page TIFF). See `IfdIndex.RootIfds()`.
+func (ie *IfdEnumerate) RootIfds() (rootIfds []*Ifd, err error) {
+	index, err := ie.getHeaderIndex(false)
+	if err != nil {
+		return nil, err
+	}
+
+	return index.RootIfds(), nil
+}
+
+// IfdsByName returns every IFD with the given name (e.g. all of the Exif IFDs
+// in a multi-page TIFF). See `IfdIndex.IfdsByName()`.
+func (ie *IfdEnumerate) IfdsByName(name string) (ifds []*Ifd, err error) {
+	index, err := ie.getHeaderIndex(false)
+	if err != nil {
+		return nil, err
+	}
+
+	return index.IfdsByName(name), nil
+}
+
+// ParseRootChain returns the root IFD followed by each IFD that follows it in
+// the chain (e.g. IFD1, which has the thumbnail), like RootIfds(), but
+// without parsing any child IFDs (Exif, GPS, MakerNote, etc..). The IFDs are
+// built the same way that Collect() builds them (with `RootOnly`), so the
+// tags that point to the child IFDs are still there.
+func (ie *IfdEnumerate) ParseRootChain() (rootIfds []*Ifd, err error) {
+	index, err := ie.getHeaderIndex(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return index.RootIfds(), nil
+}
+
 // FurthestOffset returns the furthest offset visited in the EXIF blob. This
 // *does not* account for the locations of any undefined tags since we always
 // evaluate the furthest offset, whether or not the user wants to know it.
@@ -1526,6 +3188,53 @@ func (ie *IfdEnumerate) FurthestOffset() uint32 {
 	return ie.furthestOffset
 }
 
+// TagValue reads the value of the given tag from the stream that we are
+// enumerating. The concrete type of the value depends on the tag-type:
+//
+//   - BYTE: []byte
+//   - SBYTE: []int8
+//   - ASCII, ASCII_NO_NUL: string
+//   - SHORT: []uint16
+//   - LONG: []uint32
+//   - RATIONAL: []exifcommon.Rational
+//   - SLONG: []int32
+//   - SRATIONAL: []exifcommon.SignedRational
+//   - FLOAT: []float32
+//   - DOUBLE: []float64
+//   - LONG8, IFD8 (BigTIFF): []uint64
+//   - SLONG8 (BigTIFF): []int64
+//   - UNDEFINED: whatever the decoder registered for the tag returns (see the
+//     exifundefined package)
+//
+// This is the same as `IfdTagEntry.Value()`, so a value that was changed with
+// `IfdTagEntry.SetValue()` is returned and the value is counted against the
+// limit set by `SetMaxValueBytes()`.
+//
+// For UNDEFINED tags, exifcommon.ErrUnhandledUndefinedTypedTag is returned
+// (unwrapped) if there is no decoder for the tag and
+// exifundefined.ErrUnparseableValue if the decoder could not parse it. For
+// all types, exifcommon.ErrNotEnoughData is returned (unwrapped) if the value
+// runs past the end of the data and ErrValueBudgetExceeded if reading it
+// would exceed the limit.
+func (ie *IfdEnumerate) TagValue(ite *IfdTagEntry) (value interface{}, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	value, err = ite.Value()
+	if err != nil {
+		if err == exifcommon.ErrUnhandledUndefinedTypedTag || err == exifundefined.ErrUnparseableValue || err == exifcommon.ErrNotEnoughData || err == ErrValueBudgetExceeded {
+			return nil, err
+		}
+
+		log.Panic(err)
+	}
+
+	return value, nil
+}
+
 // parseOneIfd is a hack to use an IE to parse a raw IFD block. Can be used for
 // testing. The fqIfdPath ("fully-qualified IFD path") will be less qualified
 // in that the numeric index will always be zero (the zeroth child) rather than
@@ -1557,7 +3266,7 @@ func parseOneIfd(ifdMapping *exifcommon.IfdMapping, tagIndex *TagIndex, ii *exif
 	dummyEbs := NewExifReadSeekerWithBytes([]byte{})
 	ie := NewIfdEnumerate(ifdMapping, tagIndex, dummyEbs, byteOrder)
 
-	nextIfdOffset, entries, _, err = ie.parseIfd(ii, bp, visitor, true, nil)
+	nextIfdOffset, entries, _, err = ie.parseIfd(context.Background(), ii, bp, visitor, true, nil)
 	log.PanicIf(err)
 
 	return nextIfdOffset, entries, nil
@@ -2,16 +2,20 @@ package exif
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"path"
 	"reflect"
+	"strings"
 	"testing"
 
 	"io/ioutil"
 
 	"github.com/dsoprea/go-logging"
 
-	"github.com/dsoprea/go-exif/v3/common"
+	"github.com/mschilli/go-exif/v3/common"
 )
 
 func TestIfdTagEntry_RawBytes_RealData(t *testing.T) {
@@ -60,6 +64,1508 @@ func TestIfdTagEntry_RawBytes_RealData(t *testing.T) {
 	}
 }
 
+func TestNewIfdEnumerateWithReader(t *testing.T) {
+	exifData := getExifSimpleTestIbBytes()
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	// Hide everything but Read().
+	r := struct{ io.Reader }{bytes.NewReader(exifData)}
+
+	ie, err := NewIfdEnumerateWithReader(im, ti, r, exifcommon.TestDefaultByteOrder)
+	log.PanicIf(err)
+
+	index, err := ie.Collect(ExifDefaultFirstIfdOffset)
+	log.PanicIf(err)
+
+	if len(index.RootIfd.Entries()) != 4 {
+		t.Fatalf("Entries not correct: (%d)", len(index.RootIfd.Entries()))
+	}
+}
+
+func TestNewIfdEnumerateWithReader__TooLarge(t *testing.T) {
+	originalMaxBufferedExifLength := MaxBufferedExifLength
+
+	defer func() {
+		MaxBufferedExifLength = originalMaxBufferedExifLength
+	}()
+
+	exifData := getExifSimpleTestIbBytes()
+	MaxBufferedExifLength = len(exifData) - 1
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	_, err = NewIfdEnumerateWithReader(im, ti, bytes.NewReader(exifData), exifcommon.TestDefaultByteOrder)
+	if err != ErrExifTooLarge {
+		t.Fatalf("Expected too-large error: %v", err)
+	}
+}
+
+func TestIfdEnumerate_Collect__OffsetInvalid(t *testing.T) {
+	exifData := getExifSimpleTestIbBytes()
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ebs := NewExifReadSeekerWithBytes(exifData)
+	ie := NewIfdEnumerate(im, ti, ebs, exifcommon.TestDefaultByteOrder)
+
+	_, err = ie.Collect(uint32(len(exifData)) + 100)
+	if err != ErrOffsetInvalid {
+		t.Fatalf("Expected invalid-offset error: %v", err)
+	}
+}
+
+func TestIfdEnumerate_Collect__TagCountInvalid(t *testing.T) {
+	exifData := getExifSimpleTestIbBytes()
+
+	// Corrupt the tag-count of the root IFD.
+	exifcommon.TestDefaultByteOrder.PutUint16(exifData[ExifDefaultFirstIfdOffset:], 0xffff)
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ebs := NewExifReadSeekerWithBytes(exifData)
+	ie := NewIfdEnumerate(im, ti, ebs, exifcommon.TestDefaultByteOrder)
+
+	_, err = ie.Collect(ExifDefaultFirstIfdOffset)
+	if err == nil {
+		t.Fatalf("Expected error for corrupt tag-count.")
+	} else if log.Is(err, ErrTagCountInvalid) == false {
+		t.Fatalf("Error not expected: [%s]", err.Error())
+	}
+}
+
+func TestIfdEnumerate_Collect__TagCountInvalid_ErrorsIs(t *testing.T) {
+	exifData := getExifSimpleTestIbBytes()
+
+	exifcommon.TestDefaultByteOrder.PutUint16(exifData[ExifDefaultFirstIfdOffset:], 0xffff)
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	// The package-level Collect() wraps the error, but it should still be
+	// identifiable.
+	_, _, err = Collect(im, ti, exifData)
+	if errors.Is(err, ErrTagCountInvalid) == false {
+		t.Fatalf("Error not expected: %v", err)
+	}
+}
+
+func TestByteParser_getUint32__Truncated(t *testing.T) {
+	rs := bytes.NewReader([]byte{0x00, 0x01, 0x02})
+
+	bp, err := newByteParser(rs, exifcommon.TestDefaultByteOrder, 0)
+	log.PanicIf(err)
+
+	_, err = bp.getUint32()
+	if err != ErrTruncatedData {
+		t.Fatalf("Expected truncated-data error: %v", err)
+	}
+}
+
+func TestIfdEnumerate_Collect__Cycle(t *testing.T) {
+	// The second IFD links back to the first.
+	exifData := getTestIfdChainExifData(8+18, 8)
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	_, index, err := Collect(im, ti, exifData)
+	log.PanicIf(err)
+
+	if len(index.Ifds) != 2 {
+		t.Fatalf("Expected exactly two IFDs: (%d)", len(index.Ifds))
+	}
+
+	ifd := index.RootIfd
+	if ifd.NextIfd() != index.Ifds[1] {
+		t.Fatalf("Second IFD not linked.")
+	} else if ifd.NextIfd().NextIfd() != nil {
+		t.Fatalf("Cycle not broken.")
+	}
+
+	if len(index.SkippedIfds) != 1 {
+		t.Fatalf("Expected exactly one skipped IFD: %v", index.SkippedIfds)
+	}
+
+	si := index.SkippedIfds[0]
+	if si.Offset != 8 || si.TagId != 0 || si.Reason != SkipReasonAlreadyParsed {
+		t.Fatalf("Skipped IFD not correct: %s", si)
+	}
+}
+
+func TestIfdEnumerate_Collect__SelfCycle(t *testing.T) {
+	exifData := getTestIfdChainExifData(8)
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	_, index, err := Collect(im, ti, exifData)
+	log.PanicIf(err)
+
+	if len(index.Ifds) != 1 {
+		t.Fatalf("Expected exactly one IFD: (%d)", len(index.Ifds))
+	}
+}
+
+// putTestIfd writes a minimal big-endian IFD with one LONG tag for each of
+// the given tag-IDs and values and returns the length written.
+func putTestIfd(ifdData []byte, tagIds []uint16, values []uint32, nextIfdOffset uint32) int {
+	byteOrder := exifcommon.TestDefaultByteOrder
+
+	byteOrder.PutUint16(ifdData[0:], uint16(len(tagIds)))
+
+	for i, tagId := range tagIds {
+		entryData := ifdData[2+12*i:]
+
+		byteOrder.PutUint16(entryData[0:], tagId)
+		byteOrder.PutUint16(entryData[2:], uint16(exifcommon.TypeLong))
+		byteOrder.PutUint32(entryData[4:], 1)
+		byteOrder.PutUint32(entryData[8:], values[i])
+	}
+
+	length := 2 + 12*len(tagIds)
+	byteOrder.PutUint32(ifdData[length:], nextIfdOffset)
+
+	return length + 4
+}
+
+// getTestSharedChildIfdExifData returns a big-endian EXIF blob where IFD0 and
+// IFD1 both point to the same Exif IFD (which has one PixelXDimension tag).
+func getTestSharedChildIfdExifData() (exifData []byte, exifIfdOffset uint32) {
+	exifData = make([]byte, 8+18*3)
+	copy(exifData, ExifBigEndianSignature[:])
+	exifcommon.TestDefaultByteOrder.PutUint32(exifData[4:], ExifDefaultFirstIfdOffset)
+
+	exifIfdOffset = uint32(8 + 18*2)
+
+	putTestIfd(exifData[8:], []uint16{0x8769}, []uint32{exifIfdOffset}, 8+18)
+	putTestIfd(exifData[8+18:], []uint16{0x8769}, []uint32{exifIfdOffset}, 0)
+
+	// PixelXDimension
+	putTestIfd(exifData[exifIfdOffset:], []uint16{0xa002}, []uint32{1}, 0)
+
+	return exifData, exifIfdOffset
+}
+
+func TestIfdEnumerate_Collect__SharedChildIfd(t *testing.T) {
+	exifData, exifIfdOffset := getTestSharedChildIfdExifData()
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	_, index, err := Collect(im, ti, exifData)
+	log.PanicIf(err)
+
+	if len(index.Ifds) != 3 {
+		t.Fatalf("Expected exactly three IFDs: (%d)", len(index.Ifds))
+	} else if len(index.SkippedIfds) != 0 {
+		t.Fatalf("Expected no skipped IFDs: %v", index.SkippedIfds)
+	}
+
+	ifd0 := index.RootIfd
+	ifd1 := ifd0.NextIfd()
+
+	if len(ifd0.Children()) != 1 || len(ifd1.Children()) != 1 {
+		t.Fatalf("Expected both IFDs to have one child: (%d) (%d)", len(ifd0.Children()), len(ifd1.Children()))
+	} else if ifd0.Children()[0] != ifd1.Children()[0] {
+		t.Fatalf("Child IFD not shared.")
+	} else if ifd0.Children()[0].Offset() != exifIfdOffset {
+		t.Fatalf("Child IFD offset not correct: (0x%08x)", ifd0.Children()[0].Offset())
+	}
+}
+
+func TestIfdEnumerate_Collect__SameOffsetDifferentNames(t *testing.T) {
+	// The Exif and GPS tags point to the same offset.
+
+	exifData := make([]byte, 8+30+18)
+	copy(exifData, ExifBigEndianSignature[:])
+	exifcommon.TestDefaultByteOrder.PutUint32(exifData[4:], ExifDefaultFirstIfdOffset)
+
+	childIfdOffset := uint32(8 + 30)
+
+	putTestIfd(exifData[8:], []uint16{0x8769, 0x8825}, []uint32{childIfdOffset, childIfdOffset}, 0)
+	putTestIfd(exifData[childIfdOffset:], []uint16{0x0001}, []uint32{1}, 0)
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	_, index, err := Collect(im, ti, exifData)
+	log.PanicIf(err)
+
+	exifIfd, found := index.Lookup[exifcommon.IfdExifStandardIfdIdentity.String()]
+	if found == false {
+		t.Fatalf("Exif IFD not found.")
+	}
+
+	gpsIfd, found := index.Lookup[exifcommon.IfdGpsInfoStandardIfdIdentity.String()]
+	if found == false {
+		t.Fatalf("GPS IFD not found.")
+	}
+
+	if exifIfd == gpsIfd {
+		t.Fatalf("IFDs with different names should not be shared.")
+	} else if len(index.Ifds) != 3 {
+		t.Fatalf("Expected exactly three IFDs: (%d)", len(index.Ifds))
+	}
+}
+
+func TestIfdEnumerate_CollectWithOptions__MaxIfdCount(t *testing.T) {
+	exifData := getTestIfdChainExifData(8+18, 8+18*2, 0)
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ebs := NewExifReadSeekerWithBytes(exifData)
+	ie := NewIfdEnumerate(im, ti, ebs, exifcommon.TestDefaultByteOrder)
+
+	co := &CollectOptions{
+		MaxIfdCount: 2,
+	}
+
+	_, err = ie.CollectWithOptions(ExifDefaultFirstIfdOffset, co)
+	if err != ErrTooManyIfds {
+		t.Fatalf("Expected too-many-IFDs error: %v", err)
+	}
+}
+
+func TestIfdEnumerate_CollectContext__Cancelled(t *testing.T) {
+	exifData := getTestIfdChainExifData(8+18, 0)
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ebs := NewExifReadSeekerWithBytes(exifData)
+	ie := NewIfdEnumerate(im, ti, ebs, exifcommon.TestDefaultByteOrder)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = ie.CollectContext(ctx, ExifDefaultFirstIfdOffset)
+	if err != context.Canceled {
+		t.Fatalf("Expected cancellation error: %v", err)
+	}
+}
+
+func TestIfdEnumerate_ScanContext__Cancelled(t *testing.T) {
+	exifData := getTestIfdChainExifData(8+18, 8+18*2, 0)
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ebs := NewExifReadSeekerWithBytes(exifData)
+	ie := NewIfdEnumerate(im, ti, ebs, exifcommon.TestDefaultByteOrder)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	visited := 0
+	visitor := func(ite *IfdTagEntry) error {
+		visited++
+		cancel()
+
+		return nil
+	}
+
+	_, err = ie.ScanContext(ctx, exifcommon.IfdStandardIfdIdentity, ExifDefaultFirstIfdOffset, visitor, nil)
+	if err != context.Canceled {
+		t.Fatalf("Expected cancellation error: %v", err)
+	}
+
+	// Only the first IFD should have been parsed.
+	if visited != 1 {
+		t.Fatalf("Visit count not correct: (%d)", visited)
+	}
+}
+
+func TestIfdEnumerate_visitIfd(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	parse := func(keepEntries bool) (nextIfdOffset uint32, entries []*IfdTagEntry, visited int) {
+		ie, eh, err := NewIfdEnumerateWithBytes(im, ti, getTestExifData())
+		log.PanicIf(err)
+
+		bp, err := ie.getByteParser(eh.FirstIfdOffset)
+		log.PanicIf(err)
+
+		visitor := func(ite *IfdTagEntry) error {
+			visited++
+			return nil
+		}
+
+		if keepEntries == true {
+			nextIfdOffset, entries, _, err = ie.parseIfd(context.Background(), exifcommon.IfdStandardIfdIdentity, bp, visitor, true, nil)
+			log.PanicIf(err)
+		} else {
+			nextIfdOffset, err = ie.visitIfd(context.Background(), exifcommon.IfdStandardIfdIdentity, bp, visitor, nil)
+			log.PanicIf(err)
+		}
+
+		return nextIfdOffset, entries, visited
+	}
+
+	expectedNextIfdOffset, expectedEntries, expectedVisited := parse(true)
+	nextIfdOffset, entries, visited := parse(false)
+
+	if len(expectedEntries) == 0 {
+		t.Fatalf("Expected entries from parseIfd.")
+	} else if entries != nil {
+		t.Fatalf("Expected no entries from visitIfd: (%d)", len(entries))
+	} else if visited != expectedVisited {
+		t.Fatalf("Visit count not correct: (%d) != (%d)", visited, expectedVisited)
+	} else if nextIfdOffset != expectedNextIfdOffset {
+		t.Fatalf("Next-IFD offset not correct: (0x%08x) != (0x%08x)", nextIfdOffset, expectedNextIfdOffset)
+	}
+}
+
+func TestIfdEnumerate_getValueReadSeeker__Shared(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	_, index, err := Collect(im, ti, getTestExifData())
+	log.PanicIf(err)
+
+	entries := index.RootIfd.Entries()
+
+	forward := make([][]byte, len(entries))
+	for i, ite := range entries {
+		forward[i], err = ite.GetRawBytes()
+		log.PanicIf(err)
+	}
+
+	// Every tag reads from the same stream, so reading them in a different
+	// order must not change what they read.
+	for i := len(entries) - 1; i >= 0; i-- {
+		ite := entries[i]
+
+		raw, err := ite.GetRawBytes()
+		log.PanicIf(err)
+
+		if bytes.Equal(raw, forward[i]) == false {
+			t.Fatalf("Value for tag (0x%04x) not stable: %v != %v", ite.TagId(), raw, forward[i])
+		}
+	}
+}
+
+func TestIfdEnumerate_Scan__SelfCycle(t *testing.T) {
+	// The only IFD links back to itself.
+	exifData := getTestIfdChainExifData(8)
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ebs := NewExifReadSeekerWithBytes(exifData)
+	ie := NewIfdEnumerate(im, ti, ebs, exifcommon.TestDefaultByteOrder)
+
+	visited := 0
+	visitor := func(ite *IfdTagEntry) error {
+		visited++
+		return nil
+	}
+
+	_, err = ie.Scan(exifcommon.IfdStandardIfdIdentity, ExifDefaultFirstIfdOffset, visitor, nil)
+	log.PanicIf(err)
+
+	if visited != 1 {
+		t.Fatalf("Visit count not correct: (%d)", visited)
+	}
+}
+
+func TestIfdEnumerate_Scan__Cycle(t *testing.T) {
+	// The third IFD links back to the second.
+	exifData := getTestIfdChainExifData(8+18, 8+18*2, 8+18)
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ebs := NewExifReadSeekerWithBytes(exifData)
+	ie := NewIfdEnumerate(im, ti, ebs, exifcommon.TestDefaultByteOrder)
+
+	visited := 0
+	visitor := func(ite *IfdTagEntry) error {
+		visited++
+		return nil
+	}
+
+	_, err = ie.Scan(exifcommon.IfdStandardIfdIdentity, ExifDefaultFirstIfdOffset, visitor, nil)
+	log.PanicIf(err)
+
+	if visited != 3 {
+		t.Fatalf("Visit count not correct: (%d)", visited)
+	}
+}
+
+// getTestBadChildExifData returns the test EXIF data with the pointer to the
+// Exif IFD replaced with the given offset.
+func getTestBadChildExifData(exifIfdOffset uint32) []byte {
+	// The test data is shared, so modify a copy.
+	original := getTestExifData()
+
+	exifData := make([]byte, len(original))
+	copy(exifData, original)
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	_, index, err := Collect(im, ti, exifData)
+	log.PanicIf(err)
+
+	rootIfd := index.RootIfd
+
+	ite, found := rootIfd.EntryByTagId(exifcommon.IfdExifStandardIfdIdentity.TagId())
+	if found == false {
+		log.Panicf("Exif IFD pointer not found")
+	}
+
+	valueOffset := ExifAddressableAreaStart + rootIfd.Offset() + 2 + uint32(ite.tagIndex)*12 + 8
+	rootIfd.ByteOrder().PutUint32(exifData[valueOffset:], exifIfdOffset)
+
+	return exifData
+}
+
+func TestIfdEnumerate_Collect__ChildOffsetInvalid(t *testing.T) {
+	exifData := getTestExifData()
+
+	cases := []uint32{
+		// Beyond the end of the data.
+		0xfffffff0,
+
+		// There's room for the tag-count but not for the tags.
+		uint32(len(exifData)) - 4,
+	}
+
+	for _, exifIfdOffset := range cases {
+		exifData := getTestBadChildExifData(exifIfdOffset)
+
+		im, err := exifcommon.NewIfdMappingWithStandard()
+		log.PanicIf(err)
+
+		ti := NewTagIndex()
+
+		ie, eh, err := NewIfdEnumerateWithBytes(im, ti, exifData)
+		log.PanicIf(err)
+
+		index, err := ie.Collect(eh.FirstIfdOffset)
+		log.PanicIf(err)
+
+		if _, found := index.Lookup["IFD/Exif"]; found == true {
+			t.Fatalf("Exif IFD should not have been parsed: (0x%08x)", exifIfdOffset)
+		} else if _, found := index.Lookup["IFD/GPSInfo"]; found == false {
+			t.Fatalf("GPS IFD should still have been parsed: (0x%08x)", exifIfdOffset)
+		} else if _, found := index.Lookup["IFD1"]; found == false {
+			t.Fatalf("IFD1 should still have been parsed: (0x%08x)", exifIfdOffset)
+		}
+
+		if len(index.SkippedIfds) != 1 {
+			t.Fatalf("Expected one skipped IFD: %v", index.SkippedIfds)
+		}
+
+		si := index.SkippedIfds[0]
+		if si.FqIfdPath != "IFD/Exif" || si.Offset != exifIfdOffset || si.TagId != 0x8769 || si.Reason != SkipReasonOffsetInvalid {
+			t.Fatalf("Skipped IFD not correct: %s", si)
+		}
+
+		// Strict mode still fails.
+
+		ie, eh, err = NewIfdEnumerateWithBytes(im, ti, exifData)
+		log.PanicIf(err)
+
+		ie.SetStrictMode(true)
+
+		_, err = ie.Collect(eh.FirstIfdOffset)
+		if err != ErrOffsetInvalid && err != ErrTagCountInvalid {
+			t.Fatalf("Expected an offset error in strict mode: %v", err)
+		}
+	}
+}
+
+func TestIfdEnumerate_SetStrictMode__Clean(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, eh, err := NewIfdEnumerateWithBytes(im, ti, getTestExifData())
+	log.PanicIf(err)
+
+	ie.SetStrictMode(true)
+
+	if ie.StrictMode() != true {
+		t.Fatalf("Strict mode not set.")
+	}
+
+	index, err := ie.Collect(eh.FirstIfdOffset)
+	log.PanicIf(err)
+
+	if len(index.Ifds) != 5 {
+		t.Fatalf("IFD count not correct: (%d)", len(index.Ifds))
+	}
+}
+
+// testStrictCollect collects the data both leniently and strictly and returns
+// the error from the latter, after checking that the former succeeded.
+func testStrictCollect(t *testing.T, exifData []byte) error {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, eh, err := NewIfdEnumerateWithBytes(im, ti, exifData)
+	log.PanicIf(err)
+
+	_, err = ie.Collect(eh.FirstIfdOffset)
+	if err != nil {
+		t.Fatalf("Lenient collect failed: %v", err)
+	}
+
+	ie, eh, err = NewIfdEnumerateWithBytes(im, ti, exifData)
+	log.PanicIf(err)
+
+	ie.SetStrictMode(true)
+
+	_, err = ie.Collect(eh.FirstIfdOffset)
+	return err
+}
+
+func TestIfdEnumerate_SetStrictMode__TagTypeNotValid(t *testing.T) {
+	exifData := getTestOrientationExifData(6)
+
+	eh, err := ParseExifHeader(exifData)
+	log.PanicIf(err)
+
+	// The type of the first tag in IFD0.
+	eh.ByteOrder.PutUint16(exifData[eh.FirstIfdOffset+2+2:], 0x00ff)
+
+	err = testStrictCollect(t, exifData)
+	if err != ErrTagTypeNotValid {
+		t.Fatalf("Expected ErrTagTypeNotValid: %v", err)
+	}
+}
+
+func TestIfdEnumerate_SetStrictMode__ValueOffsetInvalid(t *testing.T) {
+	exifData := getTestOrientationExifData(6)
+
+	eh, err := ParseExifHeader(exifData)
+	log.PanicIf(err)
+
+	// The value-offset of the first tag in IFD0 (the Model, which is too
+	// large to be embedded).
+	eh.ByteOrder.PutUint32(exifData[eh.FirstIfdOffset+2+8:], 0xffff0000)
+
+	err = testStrictCollect(t, exifData)
+	if err != ErrOffsetInvalid {
+		t.Fatalf("Expected ErrOffsetInvalid: %v", err)
+	}
+}
+
+func TestIfdEnumerate_SetStrictMode__Cycle(t *testing.T) {
+	// The third IFD links back to the second.
+	exifData := getTestIfdChainExifData(8+18, 8+18*2, 8+18)
+
+	err := testStrictCollect(t, exifData)
+	if err != ErrIfdCycle {
+		t.Fatalf("Expected ErrIfdCycle from Collect(): %v", err)
+	}
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ebs := NewExifReadSeekerWithBytes(exifData)
+	ie := NewIfdEnumerate(im, ti, ebs, exifcommon.TestDefaultByteOrder)
+
+	ie.SetStrictMode(true)
+
+	_, err = ie.Scan(exifcommon.IfdStandardIfdIdentity, ExifDefaultFirstIfdOffset, nil, nil)
+	if err != ErrIfdCycle {
+		t.Fatalf("Expected ErrIfdCycle from Scan(): %v", err)
+	}
+}
+
+func TestIfdEnumerate_SetStrictMode__SelfCycle(t *testing.T) {
+	// The root IFD links back to itself.
+	exifData := getTestIfdChainExifData(8)
+
+	err := testStrictCollect(t, exifData)
+	if err != ErrIfdCycle {
+		t.Fatalf("Expected ErrIfdCycle: %v", err)
+	}
+}
+
+func TestIfdEnumerate_SetKeepRawEntries(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	exifData := getTestExifData()
+
+	ie, eh, err := NewIfdEnumerateWithBytes(im, ti, exifData)
+	log.PanicIf(err)
+
+	ie.SetKeepRawEntries(true)
+
+	index, err := ie.Collect(eh.FirstIfdOffset)
+	log.PanicIf(err)
+
+	checked := 0
+	for _, ifd := range index.Ifds {
+		for _, ite := range ifd.Entries() {
+			entryOffset := ExifAddressableAreaStart + ifd.Offset() + 2 + uint32(ite.tagIndex)*12
+			expected := exifData[entryOffset : entryOffset+12]
+
+			if bytes.Equal(ite.RawEntry(), expected) != true {
+				t.Fatalf("Raw entry for tag (0x%04x) in IFD [%s] not correct: %v != %v", ite.TagId(), ifd.Path(), ite.RawEntry(), expected)
+			}
+
+			checked++
+		}
+	}
+
+	if checked == 0 {
+		t.Fatalf("No entries were checked.")
+	}
+}
+
+func TestIfdEnumerate_SetKeepRawEntries__Default(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, eh, err := NewIfdEnumerateWithBytes(im, ti, getTestExifData())
+	log.PanicIf(err)
+
+	index, err := ie.Collect(eh.FirstIfdOffset)
+	log.PanicIf(err)
+
+	for _, ite := range index.RootIfd.Entries() {
+		if ite.RawEntry() != nil {
+			t.Fatalf("Raw entries should not be kept by default.")
+		}
+	}
+}
+
+func TestIfdEnumerate_CollectWithOptions__CaptureValues(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	exifData := getTestExifData()
+
+	ie, eh, err := NewIfdEnumerateWithBytes(im, ti, exifData)
+	log.PanicIf(err)
+
+	co := &CollectOptions{
+		CaptureValues: true,
+	}
+
+	index, err := ie.CollectWithOptions(eh.FirstIfdOffset, co)
+	log.PanicIf(err)
+
+	checked := 0
+	for _, ifd := range index.Ifds {
+		for _, ite := range ifd.Entries() {
+			byteLength := uint32(valueByteLength(ite))
+
+			var expected []byte
+			if byteLength <= 4 {
+				valueOffset := ExifAddressableAreaStart + ifd.Offset() + 2 + uint32(ite.tagIndex)*12 + 8
+				expected = exifData[valueOffset : valueOffset+byteLength]
+			} else {
+				valueOffset := ExifAddressableAreaStart + ite.getValueOffset()
+				expected = exifData[valueOffset : valueOffset+byteLength]
+			}
+
+			if bytes.Equal(ite.RawValue(), expected) != true {
+				t.Fatalf("Raw value for tag (0x%04x) in IFD [%s] not correct: %v != %v", ite.TagId(), ifd.Path(), ite.RawValue(), expected)
+			}
+
+			checked++
+		}
+	}
+
+	if checked == 0 {
+		t.Fatalf("No entries were checked.")
+	}
+
+	// By default, nothing is captured.
+
+	index, err = ie.Collect(eh.FirstIfdOffset)
+	log.PanicIf(err)
+
+	for _, ite := range index.RootIfd.Entries() {
+		if ite.RawValue() != nil {
+			t.Fatalf("Raw values should not be captured by default.")
+		}
+	}
+}
+
+func TestIfdEnumerate_CollectWithOptions__CaptureValues_TagWarnings(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	exifData := make([]byte, len(getTestExifData()))
+	copy(exifData, getTestExifData())
+
+	eh, err := ParseExifHeader(exifData)
+	log.PanicIf(err)
+
+	// Point the value of the Model tag (the second entry in IFD0) way past the
+	// end of the data.
+	valueOffsetPosition := eh.FirstIfdOffset + 2 + IfdTagEntrySize + 8
+	eh.ByteOrder.PutUint32(exifData[valueOffsetPosition:], 0xfffffff0)
+
+	ie, _, err := NewIfdEnumerateWithBytes(im, ti, exifData)
+	log.PanicIf(err)
+
+	co := &CollectOptions{
+		CaptureValues: true,
+	}
+
+	index, err := ie.CollectWithOptions(eh.FirstIfdOffset, co)
+	log.PanicIf(err)
+
+	if len(index.TagWarnings) != 1 {
+		t.Fatalf("Exactly one tag warning expected: %v", index.TagWarnings)
+	}
+
+	expected := TagWarning{
+		FqIfdPath:   "IFD",
+		TagIndex:    1,
+		TagId:       0x0110,
+		TagName:     "Model",
+		ValueOffset: 0xfffffff0,
+		Reason:      TagWarningValueOutOfRange,
+	}
+
+	if index.TagWarnings[0] != expected {
+		t.Fatalf("Tag warning not correct: %s", index.TagWarnings[0])
+	}
+
+	// The rest of the tree is still there.
+
+	if len(index.Ifds) != len(index.Lookup) || len(index.Lookup) != 5 {
+		t.Fatalf("IFDs not all collected: (%d)", len(index.Ifds))
+	}
+
+	results, err := index.RootIfd.FindTagWithName("Model")
+	log.PanicIf(err)
+
+	if results[0].RawValue() != nil {
+		t.Fatalf("Raw value should not have been captured.")
+	}
+
+	results, err = index.RootIfd.FindTagWithName("Make")
+	log.PanicIf(err)
+
+	if string(results[0].RawValue()) != "Canon\000" {
+		t.Fatalf("Raw value not captured: %v", results[0].RawValue())
+	}
+}
+
+func TestIfdEnumerate_CollectWithOptions__CaptureValues_TagWarnings_Strict(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	exifData := make([]byte, len(getTestExifData()))
+	copy(exifData, getTestExifData())
+
+	eh, err := ParseExifHeader(exifData)
+	log.PanicIf(err)
+
+	valueOffsetPosition := eh.FirstIfdOffset + 2 + IfdTagEntrySize + 8
+	eh.ByteOrder.PutUint32(exifData[valueOffsetPosition:], 0xfffffff0)
+
+	ie, _, err := NewIfdEnumerateWithBytes(im, ti, exifData)
+	log.PanicIf(err)
+
+	ie.SetStrictMode(true)
+
+	co := &CollectOptions{
+		CaptureValues: true,
+	}
+
+	_, err = ie.CollectWithOptions(eh.FirstIfdOffset, co)
+	if err != ErrOffsetInvalid {
+		t.Fatalf("Expected ErrOffsetInvalid: %v", err)
+	}
+}
+
+func TestIfdEnumerate_CollectWithOptions__NoTagWarnings(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, eh, err := NewIfdEnumerateWithBytes(im, ti, getTestExifData())
+	log.PanicIf(err)
+
+	co := &CollectOptions{
+		CaptureValues: true,
+	}
+
+	index, err := ie.CollectWithOptions(eh.FirstIfdOffset, co)
+	log.PanicIf(err)
+
+	if len(index.TagWarnings) != 0 {
+		t.Fatalf("No tag warnings expected: %v", index.TagWarnings)
+	}
+}
+
+func TestIfdEnumerate_ParseRootChain(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, _, err := NewIfdEnumerateWithBytes(im, ti, getTestExifData())
+	log.PanicIf(err)
+
+	rootIfds, err := ie.ParseRootChain()
+	log.PanicIf(err)
+
+	if len(rootIfds) != 2 {
+		t.Fatalf("Root IFD count not correct: (%d)", len(rootIfds))
+	} else if rootIfds[0].Path() != "IFD" || rootIfds[1].Path() != "IFD1" {
+		t.Fatalf("Root IFDs not correct: [%s] [%s]", rootIfds[0].Path(), rootIfds[1].Path())
+	} else if len(rootIfds[0].Children()) != 0 {
+		t.Fatalf("Child IFDs should not have been parsed: (%d)", len(rootIfds[0].Children()))
+	}
+
+	if _, found := rootIfds[0].EntryByTagId(exifcommon.IfdExifStandardIfdIdentity.TagId()); found == false {
+		t.Fatalf("Exif pointer tag should still be present.")
+	}
+
+	thumbnailData, err := rootIfds[1].Thumbnail()
+	log.PanicIf(err)
+
+	if len(thumbnailData) != 21491 {
+		t.Fatalf("Thumbnail size not correct: (%d)", len(thumbnailData))
+	}
+}
+
+func TestIfdEnumerate_RootIfds(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, _, err := NewIfdEnumerateWithBytes(im, ti, getTestExifData())
+	log.PanicIf(err)
+
+	rootIfds, err := ie.RootIfds()
+	log.PanicIf(err)
+
+	if len(rootIfds) != 2 {
+		t.Fatalf("Root IFD count not correct: (%d)", len(rootIfds))
+	} else if rootIfds[0].Path() != "IFD" || rootIfds[1].Path() != "IFD1" {
+		t.Fatalf("Root IFDs not correct: [%s] [%s]", rootIfds[0].Path(), rootIfds[1].Path())
+	} else if len(rootIfds[0].Children()) != 2 {
+		t.Fatalf("Children of the first page not correct: (%d)", len(rootIfds[0].Children()))
+	}
+}
+
+func TestIfdEnumerate_getHeaderIndex(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, _, err := NewIfdEnumerateWithBytes(im, ti, getTestExifData())
+	log.PanicIf(err)
+
+	// The thumbnail accessors and Orientation() share the root chain.
+
+	_, err = ie.ThumbnailBytes()
+	log.PanicIf(err)
+
+	rootChainIndex := ie.rootChainIndex
+	if rootChainIndex == nil {
+		t.Fatalf("Expected the root chain to be kept.")
+	} else if ie.headerIndex != nil {
+		t.Fatalf("Expected only the root chain to be collected.")
+	}
+
+	_, err = ie.Thumbnail()
+	log.PanicIf(err)
+
+	_, err = ie.Orientation()
+	log.PanicIf(err)
+
+	if ie.rootChainIndex != rootChainIndex {
+		t.Fatalf("Expected the root chain to be reused.")
+	}
+
+	// The other accessors share the whole tree.
+
+	_, err = ie.RootIfds()
+	log.PanicIf(err)
+
+	headerIndex := ie.headerIndex
+	if headerIndex == nil {
+		t.Fatalf("Expected the tree to be kept.")
+	}
+
+	_, err = ie.CameraSettings()
+	log.PanicIf(err)
+
+	_, err = ie.ToMap()
+	log.PanicIf(err)
+
+	if ie.headerIndex != headerIndex {
+		t.Fatalf("Expected the tree to be reused.")
+	}
+
+	// Changing a setting drops both.
+
+	ie.SetStrictMode(true)
+
+	if ie.headerIndex != nil || ie.rootChainIndex != nil {
+		t.Fatalf("Expected the trees to be dropped.")
+	}
+}
+
+func TestIfdIndex_RootIfds__Pages(t *testing.T) {
+	// Four IFDs in one chain.
+	exifData := getTestIfdChainExifData(8+18, 8+18*2, 8+18*3, 0)
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, eh, err := NewIfdEnumerateWithBytes(im, ti, exifData)
+	log.PanicIf(err)
+
+	index, err := ie.Collect(eh.FirstIfdOffset)
+	log.PanicIf(err)
+
+	rootIfds := index.RootIfds()
+	if len(rootIfds) != 4 {
+		t.Fatalf("Page count not correct: (%d)", len(rootIfds))
+	}
+
+	for i, ifd := range rootIfds {
+		if ifd.IfdIdentity().Index() != i {
+			t.Fatalf("Page (%d) has the wrong index: (%d)", i, ifd.IfdIdentity().Index())
+		} else if ifd.Offset() != uint32(8+18*i) {
+			t.Fatalf("Page (%d) has the wrong offset: (0x%08x)", i, ifd.Offset())
+		}
+	}
+}
+
+func TestIfdIndex_IfdsByName(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, eh, err := NewIfdEnumerateWithBytes(im, ti, getTestExifData())
+	log.PanicIf(err)
+
+	index, err := ie.Collect(eh.FirstIfdOffset)
+	log.PanicIf(err)
+
+	rootIfds := index.IfdsByName("IFD")
+	if len(rootIfds) != 2 {
+		t.Fatalf("Root IFD count not correct: (%d)", len(rootIfds))
+	} else if rootIfds[0].String() != index.RootIfd.String() || rootIfds[1].IfdIdentity().Index() != 1 {
+		t.Fatalf("Root IFDs not correct: %v", rootIfds)
+	}
+
+	exifIfds := index.IfdsByName("Exif")
+	if len(exifIfds) != 1 {
+		t.Fatalf("Exif IFD count not correct: (%d)", len(exifIfds))
+	} else if exifIfds[0].IfdIdentity().UnindexedString() != exifcommon.IfdExifStandardIfdIdentity.UnindexedString() {
+		t.Fatalf("Exif IFD not correct: [%s]", exifIfds[0].IfdIdentity())
+	}
+
+	if len(index.IfdsByName("Iop")) != 1 {
+		t.Fatalf("Iop IFD not found.")
+	}
+
+	missingIfds := index.IfdsByName("SubIFD")
+	if missingIfds == nil || len(missingIfds) != 0 {
+		t.Fatalf("Expected an empty list: %v", missingIfds)
+	}
+}
+
+func TestIfdEnumerate_IfdsByName__Pages(t *testing.T) {
+	// Four IFDs in one chain.
+	exifData := getTestIfdChainExifData(8+18, 8+18*2, 8+18*3, 0)
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, _, err := NewIfdEnumerateWithBytes(im, ti, exifData)
+	log.PanicIf(err)
+
+	ifds, err := ie.IfdsByName("IFD")
+	log.PanicIf(err)
+
+	if len(ifds) != 4 {
+		t.Fatalf("Page count not correct: (%d)", len(ifds))
+	}
+
+	for i, ifd := range ifds {
+		if ifd.IfdIdentity().Index() != i {
+			t.Fatalf("Page (%d) has the wrong index: (%d)", i, ifd.IfdIdentity().Index())
+		}
+	}
+}
+
+func TestIfdEnumerate_TagValue(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, eh, err := NewIfdEnumerateWithBytes(im, ti, getTestExifData())
+	log.PanicIf(err)
+
+	index, err := ie.Collect(eh.FirstIfdOffset)
+	log.PanicIf(err)
+
+	for _, ifd := range index.Ifds {
+		for _, ite := range ifd.Entries() {
+			expected, expectedErr := ite.Value()
+
+			value, err := ie.TagValue(ite)
+			if err != expectedErr {
+				t.Fatalf("Error for tag (0x%04x) not correct: %v != %v", ite.TagId(), err, expectedErr)
+			} else if reflect.DeepEqual(value, expected) != true {
+				t.Fatalf("Value for tag (0x%04x) not correct: %v != %v", ite.TagId(), value, expected)
+			}
+		}
+	}
+
+	// DateTime
+	ite, _, err := index.FindTag(exifcommon.IfdStandardIfdIdentity.String(), 0x0132)
+	log.PanicIf(err)
+
+	value, err := ie.TagValue(ite)
+	log.PanicIf(err)
+
+	if value.(string) != "2017:12:02 08:18:50" {
+		t.Fatalf("DateTime not correct: [%s]", value)
+	}
+
+	// XResolution
+	ite, _, err = index.FindTag(exifcommon.IfdStandardIfdIdentity.String(), 0x011a)
+	log.PanicIf(err)
+
+	value, err = ie.TagValue(ite)
+	log.PanicIf(err)
+
+	if _, ok := value.([]exifcommon.Rational); ok == false {
+		t.Fatalf("XResolution not a rational: [%v]", value)
+	}
+}
+
+func TestIfdEnumerate_TagValue__SignedByte(t *testing.T) {
+	byteOrder := exifcommon.TestDefaultByteOrder
+
+	// IFD0 (at 8) has one SBYTE tag with six values (at 26). No standard tag
+	// is an SBYTE, so it's registered here.
+	exifData := make([]byte, 32)
+	copy(exifData, ExifBigEndianSignature[:])
+	byteOrder.PutUint32(exifData[4:], ExifDefaultFirstIfdOffset)
+
+	byteOrder.PutUint16(exifData[8:], 1)
+	putTestIfdEntry(exifData, byteOrder, 10, 0xf001, exifcommon.TypeSignedByte, 6)
+	byteOrder.PutUint32(exifData[18:], 26)
+	byteOrder.PutUint32(exifData[22:], 0)
+
+	copy(exifData[26:], []byte{0xff, 0x80, 0x7f, 0x00, 0x01, 0x02})
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	it := &IndexedTag{
+		Id:             0xf001,
+		Name:           "TestSignedBytes",
+		IfdPath:        exifcommon.IfdStandardIfdIdentity.UnindexedString(),
+		SupportedTypes: []exifcommon.TagTypePrimitive{exifcommon.TypeSignedByte},
+	}
+
+	err = ti.Add(it)
+	log.PanicIf(err)
+
+	ie, eh, err := NewIfdEnumerateWithBytes(im, ti, exifData)
+	log.PanicIf(err)
+
+	index, err := ie.Collect(eh.FirstIfdOffset)
+	log.PanicIf(err)
+
+	ite, found := index.RootIfd.EntryByTagId(0xf001)
+	if found == false {
+		t.Fatalf("SBYTE tag not collected.")
+	}
+
+	value, err := ie.TagValue(ite)
+	log.PanicIf(err)
+
+	expected := []int8{-1, -128, 127, 0, 1, 2}
+	if reflect.DeepEqual(value, expected) != true {
+		t.Fatalf("SBYTE value not correct: %v", value)
+	}
+
+	err = ite.SetValue([]int8{-3})
+	log.PanicIf(err)
+
+	value, err = ie.TagValue(ite)
+	log.PanicIf(err)
+
+	if reflect.DeepEqual(value, []int8{-3}) != true {
+		t.Fatalf("Changed SBYTE value not correct: %v", value)
+	}
+}
+
+func TestIfdEnumerate_TagValue__SetValue(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, eh, err := NewIfdEnumerateWithBytes(im, ti, getTestExifData())
+	log.PanicIf(err)
+
+	index, err := ie.Collect(eh.FirstIfdOffset)
+	log.PanicIf(err)
+
+	// DateTime
+	ite, _, err := index.FindTag(exifcommon.IfdStandardIfdIdentity.String(), 0x0132)
+	log.PanicIf(err)
+
+	err = ite.SetValue("2020:01:02 03:04:05")
+	log.PanicIf(err)
+
+	value, err := ie.TagValue(ite)
+	log.PanicIf(err)
+
+	if value.(string) != "2020:01:02 03:04:05" {
+		t.Fatalf("Changed value not returned: [%s]", value)
+	}
+}
+
+func TestIfdEnumerate_TagValue__SetMaxValueBytes(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, eh, err := NewIfdEnumerateWithBytes(im, ti, getExifSimpleTestIbBytes())
+	log.PanicIf(err)
+
+	// The first value is (11) bytes.
+	ie.SetMaxValueBytes(20)
+
+	index, err := ie.Collect(eh.FirstIfdOffset)
+	log.PanicIf(err)
+
+	ite := index.RootIfd.Entries()[0]
+
+	_, err = ie.TagValue(ite)
+	log.PanicIf(err)
+
+	_, err = ie.TagValue(ite)
+	if err != ErrValueBudgetExceeded {
+		t.Fatalf("Expected ErrValueBudgetExceeded: %v", err)
+	}
+}
+
+func TestIfdEnumerate_Collect__RegisteredChildIfd(t *testing.T) {
+	rawExif, err := SearchFileAndExtractExif(getTestImageFilepath())
+	log.PanicIf(err)
+
+	// Start with a mapping that only knows about the root and Exif IFDs.
+
+	im := exifcommon.NewIfdMapping()
+
+	err = im.Add(
+		[]uint16{},
+		exifcommon.IfdStandardIfdIdentity.TagId(), exifcommon.IfdStandardIfdIdentity.Name())
+
+	log.PanicIf(err)
+
+	err = im.Add(
+		[]uint16{exifcommon.IfdStandardIfdIdentity.TagId()},
+		exifcommon.IfdExifStandardIfdIdentity.TagId(), exifcommon.IfdExifStandardIfdIdentity.Name())
+
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	_, index, err := Collect(im, ti, rawExif)
+	log.PanicIf(err)
+
+	if _, found := index.Lookup[exifcommon.IfdExifIopStandardIfdIdentity.String()]; found == true {
+		t.Fatalf("Iop IFD should not have been descended into before being registered.")
+	}
+
+	// Register the Interoperability IFD (0xa005) under the Exif IFD.
+
+	err = im.Add(
+		[]uint16{exifcommon.IfdStandardIfdIdentity.TagId(), exifcommon.IfdExifStandardIfdIdentity.TagId()},
+		0xa005, "Iop")
+
+	log.PanicIf(err)
+
+	_, index, err = Collect(im, ti, rawExif)
+	log.PanicIf(err)
+
+	iopIfd, found := index.Lookup[exifcommon.IfdExifIopStandardIfdIdentity.String()]
+	if found == false {
+		t.Fatalf("Iop IFD not descended into after being registered.")
+	} else if iopIfd.parentIfd.ifdIdentity.UnindexedString() != exifcommon.IfdExifStandardIfdIdentity.UnindexedString() {
+		t.Fatalf("Iop IFD parent not correct: [%s]", iopIfd.parentIfd.ifdIdentity)
+	} else if len(iopIfd.Entries()) != 2 {
+		t.Fatalf("Iop IFD tag count not correct: (%d)", len(iopIfd.Entries()))
+	}
+}
+
+func TestIfdEnumerate_Collect__IopByDefault(t *testing.T) {
+	rawExif, err := SearchFileAndExtractExif(getTestImageFilepath())
+	log.PanicIf(err)
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	_, index, err := Collect(im, ti, rawExif)
+	log.PanicIf(err)
+
+	exifIfd := index.Lookup[exifcommon.IfdExifStandardIfdIdentity.String()]
+
+	ite, found := exifIfd.EntryByTagId(0xa005)
+	if found == false {
+		t.Fatalf("Iop pointer tag not found.")
+	} else if ite.ChildIfdPath() != exifcommon.IfdExifIopStandardIfdIdentity.UnindexedString() {
+		t.Fatalf("Iop pointer tag child-path not correct: [%s]", ite.ChildIfdPath())
+	}
+
+	iopIfd, err := exifIfd.ChildWithIfdPath(exifcommon.IfdExifIopStandardIfdIdentity)
+	log.PanicIf(err)
+
+	if iopIfd.parentIfd != exifIfd {
+		t.Fatalf("Iop IFD not attached to the Exif IFD.")
+	} else if index.Lookup[exifcommon.IfdExifIopStandardIfdIdentity.String()] != iopIfd {
+		t.Fatalf("Iop IFD not in lookup.")
+	}
+
+	iopIte, found := iopIfd.EntryByTagId(0x0001)
+	if found == false {
+		t.Fatalf("InteroperabilityIndex tag not found.")
+	}
+
+	value, err := iopIte.Value()
+	log.PanicIf(err)
+
+	if value.(string) != "R98" {
+		t.Fatalf("InteroperabilityIndex not correct: [%s]", value)
+	}
+}
+
+func TestIfdEnumerate_CollectWithOptions__MaxDepth(t *testing.T) {
+	rawExif, err := SearchFileAndExtractExif(getTestImageFilepath())
+	log.PanicIf(err)
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, eh, err := NewIfdEnumerateWithBytes(im, ti, rawExif)
+	log.PanicIf(err)
+
+	// The Iop IFD is two levels down.
+	co := &CollectOptions{
+		MaxDepth: 1,
+	}
+
+	index, err := ie.CollectWithOptions(eh.FirstIfdOffset, co)
+	log.PanicIf(err)
+
+	if _, found := index.Lookup[exifcommon.IfdExifIopStandardIfdIdentity.String()]; found == true {
+		t.Fatalf("Iop IFD should have been skipped.")
+	} else if _, found := index.Lookup[exifcommon.IfdExifStandardIfdIdentity.String()]; found == false {
+		t.Fatalf("Exif IFD should have been parsed.")
+	} else if _, found := index.Lookup["IFD1"]; found == false {
+		t.Fatalf("Sibling IFD should have been parsed.")
+	} else if len(index.Ifds) != 4 {
+		t.Fatalf("IFD count not correct: (%d)", len(index.Ifds))
+	}
+
+	var si SkippedIfd
+	for _, si = range index.SkippedIfds {
+		if si.Reason == SkipReasonTooDeep {
+			break
+		}
+	}
+
+	if si.Reason != SkipReasonTooDeep {
+		t.Fatalf("Iop IFD not reported as skipped: %v", index.SkippedIfds)
+	} else if si.FqIfdPath != exifcommon.IfdExifIopStandardIfdIdentity.String() {
+		t.Fatalf("Skipped IFD path not correct: [%s]", si.FqIfdPath)
+	} else if si.TagId != exifcommon.IfdExifIopStandardIfdIdentity.TagId() {
+		t.Fatalf("Skipped IFD tag not correct: (0x%04x)", si.TagId)
+	}
+}
+
+func TestIfdEnumerate_CollectWithOptions__RootOnly(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, eh, err := NewIfdEnumerateWithBytes(im, ti, getTestExifData())
+	log.PanicIf(err)
+
+	co := &CollectOptions{
+		RootOnly: true,
+	}
+
+	index, err := ie.CollectWithOptions(eh.FirstIfdOffset, co)
+	log.PanicIf(err)
+
+	if len(index.Ifds) != 2 {
+		t.Fatalf("Expected only the root chain: (%d)", len(index.Ifds))
+	} else if index.RootIfd.NextIfd() == nil {
+		t.Fatalf("Second IFD in the chain not parsed.")
+	} else if len(index.RootIfd.Children()) != 0 {
+		t.Fatalf("No children should have been parsed: (%d)", len(index.RootIfd.Children()))
+	}
+
+	// The tag that points to the Exif IFD is still there.
+	_, _, err = index.FindTag(exifcommon.IfdStandardIfdIdentity.String(), exifcommon.IfdExifStandardIfdIdentity.TagId())
+	log.PanicIf(err)
+
+	// Orientation
+	_, _, err = index.FindTag(exifcommon.IfdStandardIfdIdentity.String(), 0x0112)
+	log.PanicIf(err)
+
+	// The tree is still well-formed.
+
+	tags := index.RootIfd.DumpTags()
+	if len(tags) != index.CountTags() {
+		t.Fatalf("Dumped tags not correct: (%d) != (%d)", len(tags), index.CountTags())
+	}
+
+	visited := 0
+	err = index.RootIfd.EnumerateTagsRecursively(func(ifd *Ifd, ite *IfdTagEntry) error {
+		visited++
+		return nil
+	})
+
+	log.PanicIf(err)
+
+	if visited == 0 {
+		t.Fatalf("No tags were enumerated.")
+	}
+
+	b := new(bytes.Buffer)
+	index.RootIfd.FprintIfdTree(b)
+
+	if strings.Contains(b.String(), "IFD/Exif") == true {
+		t.Fatalf("Exif IFD should not be in the tree:\n%s", b.String())
+	}
+}
+
+func TestIfdTagEntry_TagName_RealData(t *testing.T) {
+	testImageFilepath := getTestImageFilepath()
+
+	rawExif, err := SearchFileAndExtractExif(testImageFilepath)
+	log.PanicIf(err)
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	_, index, err := Collect(im, ti, rawExif)
+	log.PanicIf(err)
+
+	for _, ifd := range index.Ifds {
+		for _, ite := range ifd.Entries() {
+			if ite.TagName() == "" {
+				t.Fatalf("Tag has no name: %s", ite)
+			}
+		}
+	}
+
+	ite, _, err := index.FindTag("IFD", 0x0110)
+	log.PanicIf(err)
+
+	if ite.TagName() != "Model" {
+		t.Fatalf("Tag name not correct: [%s]", ite.TagName())
+	}
+
+	// The same tag-ID means different things in different IFDs.
+
+	ite, _, err = index.FindTag("IFD/Exif/Iop", 0x0001)
+	log.PanicIf(err)
+
+	if ite.TagName() != "InteroperabilityIndex" {
+		t.Fatalf("Tag name not correct: [%s]", ite.TagName())
+	}
+
+	it, err := ti.Get(exifcommon.IfdGpsInfoStandardIfdIdentity, 0x0001)
+	log.PanicIf(err)
+
+	if it.Name != "GPSLatitudeRef" {
+		t.Fatalf("GPS tag name not correct: [%s]", it.Name)
+	}
+}
+
 func TestIfd_FindTagWithId_Hit(t *testing.T) {
 	testImageFilepath := getTestImageFilepath()
 	rawExif, err := SearchFileAndExtractExif(testImageFilepath)
@@ -73,18 +1579,254 @@ func TestIfd_FindTagWithId_Hit(t *testing.T) {
 	_, index, err := Collect(im, ti, rawExif)
 	log.PanicIf(err)
 
-	ifd := index.RootIfd
-	results, err := ifd.FindTagWithId(0x011b)
+	ifd := index.RootIfd
+	results, err := ifd.FindTagWithId(0x011b)
+	log.PanicIf(err)
+
+	if len(results) != 1 {
+		t.Fatalf("Exactly one result was not found: (%d)", len(results))
+	} else if results[0].TagId() != 0x011b {
+		t.Fatalf("The result was not expected: %v", results[0])
+	}
+}
+
+func TestIfd_FindTagWithId_Miss(t *testing.T) {
+	testImageFilepath := getTestImageFilepath()
+
+	rawExif, err := SearchFileAndExtractExif(testImageFilepath)
+	log.PanicIf(err)
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	_, index, err := Collect(im, ti, rawExif)
+	log.PanicIf(err)
+
+	ifd := index.RootIfd
+
+	_, err = ifd.FindTagWithId(0xffff)
+	if err == nil {
+		t.Fatalf("Expected error for not-found tag.")
+	} else if log.Is(err, ErrTagNotFound) == false {
+		log.Panic(err)
+	}
+}
+
+func TestIfd_EntryByTagId(t *testing.T) {
+	testImageFilepath := getTestImageFilepath()
+
+	rawExif, err := SearchFileAndExtractExif(testImageFilepath)
+	log.PanicIf(err)
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	_, index, err := Collect(im, ti, rawExif)
+	log.PanicIf(err)
+
+	ifd := index.RootIfd
+
+	ite, found := ifd.EntryByTagId(0x011b)
+	if found != true {
+		t.Fatalf("Tag not found.")
+	} else if ite.TagId() != 0x011b {
+		t.Fatalf("The result was not expected: %v", ite)
+	}
+
+	_, found = ifd.EntryByTagId(0xffff)
+	if found != false {
+		t.Fatalf("Expected miss for unknown tag.")
+	}
+}
+
+func TestIfd_SortedEntries(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	exifData := make([]byte, len(getTestExifData()))
+	copy(exifData, getTestExifData())
+
+	eh, err := ParseExifHeader(exifData)
+	log.PanicIf(err)
+
+	// Swap the first two entries of IFD0 (Make and Model).
+	firstEntryPosition := eh.FirstIfdOffset + 2
+
+	firstEntry := make([]byte, IfdTagEntrySize)
+	copy(firstEntry, exifData[firstEntryPosition:])
+	copy(exifData[firstEntryPosition:], exifData[firstEntryPosition+IfdTagEntrySize:firstEntryPosition+2*IfdTagEntrySize])
+	copy(exifData[firstEntryPosition+IfdTagEntrySize:], firstEntry)
+
+	_, index, err := Collect(im, ti, exifData)
+	log.PanicIf(err)
+
+	rootIfd := index.RootIfd
+
+	if rootIfd.IsSorted() != false {
+		t.Fatalf("IFD should not be sorted.")
+	}
+
+	entries := rootIfd.Entries()
+	sortedEntries := rootIfd.SortedEntries()
+
+	if len(sortedEntries) != len(entries) {
+		t.Fatalf("Sorted entry count not correct: (%d) != (%d)", len(sortedEntries), len(entries))
+	} else if sortedEntries[0].TagName() != "Make" || sortedEntries[1].TagName() != "Model" {
+		t.Fatalf("Entries not sorted: [%s] [%s]", sortedEntries[0].TagName(), sortedEntries[1].TagName())
+	} else if entries[0].TagName() != "Model" || entries[1].TagName() != "Make" {
+		t.Fatalf("Original order was changed: [%s] [%s]", entries[0].TagName(), entries[1].TagName())
+	}
+
+	for i := 1; i < len(sortedEntries); i++ {
+		if sortedEntries[i].TagId() < sortedEntries[i-1].TagId() {
+			t.Fatalf("Entries not sorted at (%d).", i)
+		}
+	}
+}
+
+func TestIfd_IsSorted(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	_, index, err := Collect(im, ti, getTestExifData())
+	log.PanicIf(err)
+
+	for _, ifd := range index.Ifds {
+		if ifd.IsSorted() != true {
+			t.Fatalf("IFD [%s] should be sorted.", ifd.Path())
+		}
+	}
+
+	// A repeated tag breaks the order.
+
+	_, index, err = Collect(im, ti, getTestManyTagsExifData(2))
+	log.PanicIf(err)
+
+	if index.RootIfd.IsSorted() != false {
+		t.Fatalf("IFD with a repeated tag should not be sorted.")
+	}
+}
+
+func TestIfd_ChildOffset(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	_, index, err := Collect(im, ti, getTestExifData())
+	log.PanicIf(err)
+
+	rootIfd := index.RootIfd
+
+	exifIfd, err := rootIfd.ChildWithIfdPath(exifcommon.IfdExifStandardIfdIdentity)
+	log.PanicIf(err)
+
+	offset, found := rootIfd.ChildOffset("Exif")
+	if found != true {
+		t.Fatalf("Exif pointer not found.")
+	} else if offset != exifIfd.Offset() {
+		t.Fatalf("Exif offset not correct: (0x%08x) != (0x%08x)", offset, exifIfd.Offset())
+	}
+
+	// The pointer tag is still visible as a regular entry.
+
+	ite, found := rootIfd.EntryByTagId(exifcommon.IfdExifStandardIfdIdentity.TagId())
+	if found != true {
+		t.Fatalf("Exif pointer tag not among the entries.")
+	} else if ite.ChildIfdName() != "Exif" {
+		t.Fatalf("Exif pointer tag IFD-name not correct: [%s]", ite.ChildIfdName())
+	}
+
+	iopIfd, err := exifIfd.ChildWithIfdPath(exifcommon.IfdExifIopStandardIfdIdentity)
+	log.PanicIf(err)
+
+	offset, found = exifIfd.ChildOffset("Iop")
+	if found != true {
+		t.Fatalf("Iop pointer not found.")
+	} else if offset != iopIfd.Offset() {
+		t.Fatalf("Iop offset not correct: (0x%08x) != (0x%08x)", offset, iopIfd.Offset())
+	}
+
+	if _, found := rootIfd.ChildOffset("Iop"); found != false {
+		t.Fatalf("Expected no Iop pointer in the root IFD.")
+	}
+}
+
+func TestIfdIndex_CountTags(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	_, index, err := Collect(im, ti, getTestExifData())
+	log.PanicIf(err)
+
+	expected := 0
+
+	err = index.RootIfd.Walk(func(ifd *Ifd, depth int) error {
+		expected += len(ifd.Entries())
+		return nil
+	})
+
+	log.PanicIf(err)
+
+	if count := index.CountTags(); count != expected {
+		t.Fatalf("Tag count not correct: (%d) != (%d)", count, expected)
+	} else if count != len(index.RootIfd.DumpTags()) {
+		t.Fatalf("Tag count does not match the dump: (%d) != (%d)", count, len(index.RootIfd.DumpTags()))
+	}
+}
+
+func TestIfdIndex_CountTags__SharedChildIfd(t *testing.T) {
+	exifData, _ := getTestSharedChildIfdExifData()
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	_, index, err := Collect(im, ti, exifData)
+	log.PanicIf(err)
+
+	// The shared Exif IFD is only counted once.
+	if count := index.CountTags(); count != 3 {
+		t.Fatalf("Tag count not correct: (%d)", count)
+	}
+}
+
+func TestIfdIndex_FindTag_Hit(t *testing.T) {
+	testImageFilepath := getTestImageFilepath()
+
+	rawExif, err := SearchFileAndExtractExif(testImageFilepath)
+	log.PanicIf(err)
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	_, index, err := Collect(im, ti, rawExif)
+	log.PanicIf(err)
+
+	ite, ifd, err := index.FindTag("IFD/Exif", 0x829a)
 	log.PanicIf(err)
 
-	if len(results) != 1 {
-		t.Fatalf("Exactly one result was not found: (%d)", len(results))
-	} else if results[0].TagId() != 0x011b {
-		t.Fatalf("The result was not expected: %v", results[0])
+	if ite.TagName() != "ExposureTime" {
+		t.Fatalf("Tag not correct: [%s]", ite.TagName())
+	} else if ifd.IfdIdentity().String() != "IFD/Exif" {
+		t.Fatalf("IFD not correct: [%s]", ifd.IfdIdentity().String())
 	}
 }
 
-func TestIfd_FindTagWithId_Miss(t *testing.T) {
+func TestIfdIndex_FindTag_Miss(t *testing.T) {
 	testImageFilepath := getTestImageFilepath()
 
 	rawExif, err := SearchFileAndExtractExif(testImageFilepath)
@@ -98,13 +1840,47 @@ func TestIfd_FindTagWithId_Miss(t *testing.T) {
 	_, index, err := Collect(im, ti, rawExif)
 	log.PanicIf(err)
 
-	ifd := index.RootIfd
+	_, _, err = index.FindTag("IFD/Exif", 0xffff)
+	if err != ErrTagNotFound {
+		t.Fatalf("Expected not-found error for missing tag: %v", err)
+	}
 
-	_, err = ifd.FindTagWithId(0xffff)
-	if err == nil {
-		t.Fatalf("Expected error for not-found tag.")
-	} else if log.Is(err, ErrTagNotFound) == false {
-		log.Panic(err)
+	_, _, err = index.FindTag("IFD/Nonexistent", 0x829a)
+	if err != ErrTagNotFound {
+		t.Fatalf("Expected not-found error for missing IFD: %v", err)
+	}
+}
+
+func TestIfd_Path(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	_, index, err := Collect(im, ti, getTestExifData())
+	log.PanicIf(err)
+
+	actual := make([]string, len(index.Ifds))
+	for i, ifd := range index.Ifds {
+		actual[i] = ifd.Path()
+	}
+
+	expected := []string{
+		"IFD",
+		"IFD/Exif",
+		"IFD/GPSInfo",
+		"IFD1",
+		"IFD/Exif/Iop",
+	}
+
+	if reflect.DeepEqual(actual, expected) != true {
+		t.Fatalf("Paths not correct: %v", actual)
+	}
+
+	for _, ifd := range index.Ifds {
+		if ifd.Path() != ifd.IfdIdentity().String() {
+			t.Fatalf("Path does not match the identity: [%s] != [%s]", ifd.Path(), ifd.IfdIdentity().String())
+		}
 	}
 }
 
@@ -250,13 +2026,248 @@ func TestIfd_GpsInfo(t *testing.T) {
 		t.Fatalf("longitude not correct")
 	} else if gi.Altitude != 0 {
 		t.Fatalf("altitude not correct")
-	} else if gi.Timestamp.Unix() != 1524964977 {
+	} else if gi.Timestamp.Unix() != 1524964977 || gi.Has(GpsFieldTimestamp) == false {
 		t.Fatalf("timestamp not correct")
 	} else if gi.Altitude != 0 {
 		t.Fatalf("altitude not correct")
 	}
 }
 
+// getTestGpsNavigationExifData returns the GPS test image with the given
+// GPS tags (by name) added.
+func getTestGpsNavigationExifData(values map[string]interface{}) []byte {
+	rawExif, err := SearchFileAndExtractExif(getTestGpsImageFilepath())
+	log.PanicIf(err)
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	_, index, err := Collect(im, ti, rawExif)
+	log.PanicIf(err)
+
+	rootIb := NewIfdBuilderFromExistingChain(index.RootIfd)
+
+	gpsIb, err := rootIb.ChildWithTagId(exifcommon.IfdGpsInfoStandardIfdIdentity.TagId())
+	log.PanicIf(err)
+
+	for name, value := range values {
+		err := gpsIb.AddStandardWithName(name, value)
+		log.PanicIf(err)
+	}
+
+	ibe := NewIfdByteEncoder()
+
+	updatedRawExif, err := ibe.EncodeToExif(rootIb)
+	log.PanicIf(err)
+
+	return updatedRawExif
+}
+
+func TestIfd_GpsInfo__Navigation(t *testing.T) {
+	rawExif := getTestGpsNavigationExifData(map[string]interface{}{
+		"GPSDestLatitudeRef":  "S",
+		"GPSDestLatitude":     GpsDegrees{Degrees: 12, Minutes: 34, Seconds: 56}.Raw(),
+		"GPSDestLongitudeRef": "E",
+		"GPSDestLongitude":    GpsDegrees{Degrees: 123, Minutes: 45, Seconds: 6}.Raw(),
+		"GPSDestBearingRef":   "M",
+		"GPSDestBearing":      []exifcommon.Rational{{Numerator: 4515, Denominator: 100}},
+		"GPSImgDirectionRef":  "T",
+		"GPSImgDirection":     []exifcommon.Rational{{Numerator: 2705, Denominator: 10}},
+		"GPSSpeedRef":         "K",
+		"GPSSpeed":            []exifcommon.Rational{{Numerator: 55, Denominator: 2}},
+	})
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	_, index, err := Collect(im, ti, rawExif)
+	log.PanicIf(err)
+
+	ifd, err := index.RootIfd.ChildWithIfdPath(exifcommon.IfdGpsInfoStandardIfdIdentity)
+	log.PanicIf(err)
+
+	gi, err := ifd.GpsInfo()
+	log.PanicIf(err)
+
+	expectedPresent := GpsFieldDestination | GpsFieldDestBearing | GpsFieldImgDirection | GpsFieldSpeed | GpsFieldTimestamp
+
+	if gi.Present != expectedPresent {
+		t.Fatalf("Present flags not correct: (%b)", gi.Present)
+	} else if gi.Latitude.Orientation != 'N' || gi.Latitude.Degrees != 26 {
+		t.Fatalf("Latitude not correct: %s", gi.Latitude)
+	} else if gi.DestLatitude.Orientation != 'S' || gi.DestLatitude.Degrees != 12 || gi.DestLatitude.Minutes != 34 || gi.DestLatitude.Seconds != 56 {
+		t.Fatalf("Destination latitude not correct: %s", gi.DestLatitude)
+	} else if gi.DestLongitude.Orientation != 'E' || gi.DestLongitude.Degrees != 123 || gi.DestLongitude.Minutes != 45 || gi.DestLongitude.Seconds != 6 {
+		t.Fatalf("Destination longitude not correct: %s", gi.DestLongitude)
+	} else if gi.DestBearing != 45.15 || gi.DestBearingRef != 'M' {
+		t.Fatalf("Destination bearing not correct: (%f) [%c]", gi.DestBearing, gi.DestBearingRef)
+	} else if gi.ImgDirection != 270.5 || gi.ImgDirectionRef != 'T' {
+		t.Fatalf("Image direction not correct: (%f) [%c]", gi.ImgDirection, gi.ImgDirectionRef)
+	} else if gi.Speed != 27.5 || gi.SpeedRef != 'K' {
+		t.Fatalf("Speed not correct: (%f) [%c]", gi.Speed, gi.SpeedRef)
+	}
+}
+
+func TestIfd_GpsInfo__NavigationMissing(t *testing.T) {
+	rawExif, err := SearchFileAndExtractExif(getTestGpsImageFilepath())
+	log.PanicIf(err)
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	_, index, err := Collect(im, ti, rawExif)
+	log.PanicIf(err)
+
+	ifd, err := index.RootIfd.ChildWithIfdPath(exifcommon.IfdGpsInfoStandardIfdIdentity)
+	log.PanicIf(err)
+
+	gi, err := ifd.GpsInfo()
+	log.PanicIf(err)
+
+	if gi.Present != GpsFieldTimestamp {
+		t.Fatalf("Only the timestamp should be present: (%b)", gi.Present)
+	} else if gi.Has(GpsFieldSpeed) == true {
+		t.Fatalf("Speed should not be present.")
+	} else if gi.DestLatitude != (GpsDegrees{}) || gi.Speed != 0 || gi.SpeedRef != 0 {
+		t.Fatalf("Missing fields should be zero: %v", gi)
+	}
+}
+
+func TestIfd_GpsInfo__NavigationPartial(t *testing.T) {
+	// A destination latitude without a longitude and a speed without a unit
+	// are both ignored.
+
+	rawExif := getTestGpsNavigationExifData(map[string]interface{}{
+		"GPSDestLatitudeRef": "S",
+		"GPSDestLatitude":    GpsDegrees{Degrees: 12, Minutes: 34, Seconds: 56}.Raw(),
+		"GPSSpeed":           []exifcommon.Rational{{Numerator: 55, Denominator: 2}},
+		"GPSImgDirectionRef": "M",
+		"GPSImgDirection":    []exifcommon.Rational{{Numerator: 90, Denominator: 1}},
+	})
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	_, index, err := Collect(im, ti, rawExif)
+	log.PanicIf(err)
+
+	ifd, err := index.RootIfd.ChildWithIfdPath(exifcommon.IfdGpsInfoStandardIfdIdentity)
+	log.PanicIf(err)
+
+	gi, err := ifd.GpsInfo()
+	log.PanicIf(err)
+
+	if gi.Present != GpsFieldImgDirection|GpsFieldTimestamp {
+		t.Fatalf("Present flags not correct: (%b)", gi.Present)
+	} else if gi.ImgDirection != 90 || gi.ImgDirectionRef != 'M' {
+		t.Fatalf("Image direction not correct: (%f) [%c]", gi.ImgDirection, gi.ImgDirectionRef)
+	} else if gi.DestLatitude != (GpsDegrees{}) {
+		t.Fatalf("Destination latitude should be zero: %s", gi.DestLatitude)
+	} else if gi.Speed != 0 {
+		t.Fatalf("Speed should be zero: (%f)", gi.Speed)
+	}
+}
+
+func TestIfd_GpsInfo__TimestampMissingOrNotValid(t *testing.T) {
+	// GPSDateStamp is removed, or replaced with one that is missing the day.
+	for _, datestamp := range []string{"", "2018:04"} {
+		rawExif, err := SearchFileAndExtractExif(getTestGpsImageFilepath())
+		log.PanicIf(err)
+
+		im, err := exifcommon.NewIfdMappingWithStandard()
+		log.PanicIf(err)
+
+		ti := NewTagIndex()
+
+		_, index, err := Collect(im, ti, rawExif)
+		log.PanicIf(err)
+
+		rootIb := NewIfdBuilderFromExistingChain(index.RootIfd)
+
+		gpsIb, err := rootIb.ChildWithTagId(exifcommon.IfdGpsInfoStandardIfdIdentity.TagId())
+		log.PanicIf(err)
+
+		_, err = gpsIb.DeleteAll(TagDatestampId)
+		log.PanicIf(err)
+
+		if datestamp != "" {
+			err := gpsIb.AddStandard(TagDatestampId, datestamp)
+			log.PanicIf(err)
+		}
+
+		ibe := NewIfdByteEncoder()
+
+		updatedRawExif, err := ibe.EncodeToExif(rootIb)
+		log.PanicIf(err)
+
+		_, index, err = Collect(im, ti, updatedRawExif)
+		log.PanicIf(err)
+
+		ifd, err := index.RootIfd.ChildWithIfdPath(exifcommon.IfdGpsInfoStandardIfdIdentity)
+		log.PanicIf(err)
+
+		gi, err := ifd.GpsInfo()
+		log.PanicIf(err)
+
+		if gi.Has(GpsFieldTimestamp) == true {
+			t.Fatalf("Timestamp should not be present: [%s]", datestamp)
+		} else if gi.Timestamp.IsZero() == false {
+			t.Fatalf("Timestamp should be zero: [%s] %v", datestamp, gi.Timestamp)
+		} else if gi.Latitude.Orientation != 'N' || gi.Latitude.Degrees != 26 {
+			t.Fatalf("Latitude not correct: %s", gi.Latitude)
+		}
+	}
+}
+
+func TestIfdIndex_GpsInfo(t *testing.T) {
+	filepath := getTestGpsImageFilepath()
+
+	rawExif, err := SearchFileAndExtractExif(filepath)
+	log.PanicIf(err)
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	_, index, err := Collect(im, ti, rawExif)
+	log.PanicIf(err)
+
+	gi, err := index.GpsInfo()
+	log.PanicIf(err)
+
+	if fmt.Sprintf("%.05f", gi.Latitude.Decimal()) != "26.58667" {
+		t.Fatalf("Decimal latitude not correct: (%.05f)", gi.Latitude.Decimal())
+	} else if fmt.Sprintf("%.05f", gi.Longitude.Decimal()) != "-80.05361" {
+		t.Fatalf("Decimal longitude not correct: (%.05f)", gi.Longitude.Decimal())
+	}
+}
+
+func TestIfdIndex_GpsInfo__NoGpsIfd(t *testing.T) {
+	exifData := getExifSimpleTestIbBytes()
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	_, index, err := Collect(im, ti, exifData)
+	log.PanicIf(err)
+
+	_, err = index.GpsInfo()
+	if err != ErrNoGpsIfd {
+		t.Fatalf("Expected no-GPS-IFD error: %v", err)
+	}
+}
+
 func TestIfd_GpsInfo__2_0_0_0(t *testing.T) {
 	defer func() {
 		if state := recover(); state != nil {
@@ -302,95 +2313,47 @@ func TestIfd_GpsInfo__2_0_0_0(t *testing.T) {
 	if GpsDegreesEquals(gi.Latitude, expectedLatitude) != true {
 		t.Fatalf("Latitude not correct: %v", gi.Latitude)
 	} else if GpsDegreesEquals(gi.Longitude, expectedLongitude) != true {
-		t.Fatalf("Longitude not correct: %v", gi.Longitude)
-	} else if gi.Altitude != 0 {
-		t.Fatalf("Altitude not correct: (%d)", gi.Altitude)
-	} else if gi.Timestamp.Unix() != -62135596800 {
-		t.Fatalf("Timestamp not correct: (%d)", gi.Timestamp.Unix())
-	}
-}
-
-func TestIfd_EnumerateTagsRecursively(t *testing.T) {
-	testImageFilepath := getTestImageFilepath()
-
-	rawExif, err := SearchFileAndExtractExif(testImageFilepath)
-	log.PanicIf(err)
-
-	im, err := exifcommon.NewIfdMappingWithStandard()
-	log.PanicIf(err)
-
-	ti := NewTagIndex()
-
-	_, index, err := Collect(im, ti, rawExif)
-	log.PanicIf(err)
-
-	collected := make([][2]interface{}, 0)
-
-	cb := func(ifd *Ifd, ite *IfdTagEntry) error {
-		item := [2]interface{}{
-			ifd.ifdIdentity.UnindexedString(),
-			int(ite.TagId()),
-		}
-
-		collected = append(collected, item)
-
-		return nil
-	}
-
-	err = index.RootIfd.EnumerateTagsRecursively(cb)
-	log.PanicIf(err)
-
-	expected := [][2]interface{}{
-		{"IFD", 0x010f},
-		{"IFD", 0x0110},
-		{"IFD", 0x0112},
-		{"IFD", 0x011a},
-		{"IFD", 0x011b},
-		{"IFD", 0x0128},
-		{"IFD", 0x0132},
-		{"IFD", 0x013b},
-		{"IFD", 0x0213},
-		{"IFD", 0x8298},
-		{"IFD/Exif", 0x829a},
-		{"IFD/Exif", 0x829d},
-		{"IFD/Exif", 0x8822},
-		{"IFD/Exif", 0x8827},
-		{"IFD/Exif", 0x8830},
-		{"IFD/Exif", 0x8832},
-		{"IFD/Exif", 0x9000},
-		{"IFD/Exif", 0x9003},
-		{"IFD/Exif", 0x9004},
-		{"IFD/Exif", 0x9101},
-		{"IFD/Exif", 0x9201},
-		{"IFD/Exif", 0x9202},
-		{"IFD/Exif", 0x9204},
-		{"IFD/Exif", 0x9207},
-		{"IFD/Exif", 0x9209},
-		{"IFD/Exif", 0x920a},
-		{"IFD/Exif", 0x927c},
-		{"IFD/Exif", 0x9286},
-		{"IFD/Exif", 0x9290},
-		{"IFD/Exif", 0x9291},
-		{"IFD/Exif", 0x9292},
-		{"IFD/Exif", 0xa000},
-		{"IFD/Exif", 0xa001},
-		{"IFD/Exif", 0xa002},
-		{"IFD/Exif", 0xa003},
-		{"IFD/Exif/Iop", 0x0001},
-		{"IFD/Exif/Iop", 0x0002},
-		{"IFD/Exif", 0xa20e},
-		{"IFD/Exif", 0xa20f},
-		{"IFD/Exif", 0xa210},
-		{"IFD/Exif", 0xa401},
-		{"IFD/Exif", 0xa402},
-		{"IFD/Exif", 0xa403},
-		{"IFD/Exif", 0xa406},
-		{"IFD/Exif", 0xa430},
-		{"IFD/Exif", 0xa431},
-		{"IFD/Exif", 0xa432},
-		{"IFD/Exif", 0xa434},
-		{"IFD/Exif", 0xa435},
-		{"IFD/GPSInfo", 0x0000},
+		t.Fatalf("Longitude not correct: %v", gi.Longitude)
+	} else if gi.Altitude != 0 {
+		t.Fatalf("Altitude not correct: (%d)", gi.Altitude)
+	} else if gi.Timestamp.Unix() != -62135596800 {
+		t.Fatalf("Timestamp not correct: (%d)", gi.Timestamp.Unix())
+	} else if gi.Has(GpsFieldTimestamp) == true {
+		t.Fatalf("Timestamp should not be present.")
+	}
+}
+
+func TestIfd_EnumerateTagsRecursively(t *testing.T) {
+	testImageFilepath := getTestImageFilepath()
+
+	rawExif, err := SearchFileAndExtractExif(testImageFilepath)
+	log.PanicIf(err)
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	_, index, err := Collect(im, ti, rawExif)
+	log.PanicIf(err)
+
+	collected := make([][2]interface{}, 0)
+
+	cb := func(ifd *Ifd, ite *IfdTagEntry) error {
+		item := [2]interface{}{
+			ifd.ifdIdentity.UnindexedString(),
+			int(ite.TagId()),
+		}
+
+		collected = append(collected, item)
+
+		return nil
+	}
+
+	err = index.RootIfd.EnumerateTagsRecursively(cb)
+	log.PanicIf(err)
+
+	expected := [][2]interface{}{
 		{"IFD", 0x010f},
 		{"IFD", 0x0110},
 		{"IFD", 0x0112},
@@ -441,6 +2404,12 @@ func TestIfd_EnumerateTagsRecursively(t *testing.T) {
 		{"IFD/Exif", 0xa434},
 		{"IFD/Exif", 0xa435},
 		{"IFD/GPSInfo", 0x0000},
+		{"IFD", 0x0103},
+		{"IFD", 0x011a},
+		{"IFD", 0x011b},
+		{"IFD", 0x0128},
+		{"IFD", 0x0201},
+		{"IFD", 0x0202},
 	}
 
 	if reflect.DeepEqual(collected, expected) != true {
@@ -466,6 +2435,256 @@ func TestIfd_EnumerateTagsRecursively(t *testing.T) {
 	}
 }
 
+func TestIfd_EnumerateTagsRecursively__StopOnError(t *testing.T) {
+	testImageFilepath := getTestImageFilepath()
+
+	rawExif, err := SearchFileAndExtractExif(testImageFilepath)
+	log.PanicIf(err)
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	_, index, err := Collect(im, ti, rawExif)
+	log.PanicIf(err)
+
+	errStop := errors.New("stop")
+
+	visited := 0
+	cb := func(ifd *Ifd, ite *IfdTagEntry) error {
+		visited++
+
+		if ifd.ifdIdentity.UnindexedString() == "IFD/Exif" {
+			return errStop
+		}
+
+		return nil
+	}
+
+	err = index.RootIfd.EnumerateTagsRecursively(cb)
+	if err != errStop {
+		t.Fatalf("Expected visitor error: %v", err)
+	}
+
+	// The ten root tags preceding the Exif pointer, plus the first Exif tag.
+	if visited != 11 {
+		t.Fatalf("Visit count not correct: (%d)", visited)
+	}
+}
+
+func TestIfd_Clone(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	_, index, err := Collect(im, ti, getTestExifData())
+	log.PanicIf(err)
+
+	original := index.RootIfd
+	originalTree := original.DumpTree()
+
+	clone := original.Clone()
+
+	if clone == original {
+		t.Fatalf("Clone is the original.")
+	} else if reflect.DeepEqual(clone.DumpTree(), originalTree) != true {
+		t.Fatalf("Cloned tree not correct.")
+	} else if clone.NextIfd() == original.NextIfd() {
+		t.Fatalf("Next IFD not cloned.")
+	}
+
+	for _, childIfd := range clone.Children() {
+		if childIfd.parentIfd != clone {
+			t.Fatalf("Child IFD [%s] not parented to the clone.", childIfd.ifdIdentity)
+		}
+	}
+
+	exifIfd, err := clone.ChildWithIfdPath(exifcommon.IfdExifStandardIfdIdentity)
+	log.PanicIf(err)
+
+	if exifIfd != clone.Children()[0] && exifIfd != clone.Children()[1] {
+		t.Fatalf("Child-IFD index not rebuilt for the clone.")
+	}
+
+	originalThumbnail, err := original.NextIfd().Thumbnail()
+	log.PanicIf(err)
+
+	cloneThumbnail, err := clone.NextIfd().Thumbnail()
+	log.PanicIf(err)
+
+	if bytes.Equal(cloneThumbnail, originalThumbnail) != true {
+		t.Fatalf("Thumbnail not cloned.")
+	}
+
+	// Modify the clone.
+
+	cloneIte := clone.Entries()[0]
+	originalIte := original.Entries()[0]
+
+	originalUnitCount := originalIte.UnitCount()
+
+	cloneIte.updateUnitCount(originalUnitCount + 1)
+	cloneIte.setTagName("Modified")
+	cloneIte.rawValueOffset[0]++
+
+	clone.entries = clone.entries[1:]
+	cloneThumbnail[0]++
+
+	if originalIte.UnitCount() != originalUnitCount {
+		t.Fatalf("Original unit-count was modified.")
+	} else if originalIte.TagName() == "Modified" {
+		t.Fatalf("Original tag-name was modified.")
+	} else if reflect.DeepEqual(original.DumpTree(), originalTree) != true {
+		t.Fatalf("Original tree was modified.")
+	}
+
+	originalThumbnailAfter, err := original.NextIfd().Thumbnail()
+	log.PanicIf(err)
+
+	if bytes.Equal(originalThumbnailAfter, originalThumbnail) != true {
+		t.Fatalf("Original thumbnail was modified.")
+	}
+}
+
+func TestIfd_Clone__SharedChildIfd(t *testing.T) {
+	exifData, _ := getTestSharedChildIfdExifData()
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	_, index, err := Collect(im, ti, exifData)
+	log.PanicIf(err)
+
+	clone := index.RootIfd.Clone()
+
+	sharedIfd := clone.Children()[0]
+
+	if sharedIfd == index.RootIfd.Children()[0] {
+		t.Fatalf("Shared IFD not cloned.")
+	} else if clone.NextIfd().Children()[0] != sharedIfd {
+		t.Fatalf("Shared IFD not shared in the clone.")
+	}
+}
+
+func TestIfd_Walk(t *testing.T) {
+	rawExif, err := SearchFileAndExtractExif(getTestImageFilepath())
+	log.PanicIf(err)
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	_, index, err := Collect(im, ti, rawExif)
+	log.PanicIf(err)
+
+	collected := make([]string, 0)
+
+	cb := func(ifd *Ifd, depth int) error {
+		collected = append(collected, fmt.Sprintf("%s/%d", ifd.ifdIdentity.String(), depth))
+		return nil
+	}
+
+	err = index.RootIfd.Walk(cb)
+	log.PanicIf(err)
+
+	expected := []string{
+		"IFD/0",
+		"IFD1/0",
+		"IFD/Exif/1",
+		"IFD/GPSInfo/1",
+		"IFD/Exif/Iop/2",
+	}
+
+	if reflect.DeepEqual(collected, expected) != true {
+		t.Fatalf("IFDs not walked correctly: %v", collected)
+	}
+}
+
+func TestIfd_Walk__StopOnError(t *testing.T) {
+	rawExif, err := SearchFileAndExtractExif(getTestImageFilepath())
+	log.PanicIf(err)
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	_, index, err := Collect(im, ti, rawExif)
+	log.PanicIf(err)
+
+	errStop := errors.New("stop")
+
+	visited := 0
+	cb := func(ifd *Ifd, depth int) error {
+		visited++
+		return errStop
+	}
+
+	err = index.RootIfd.Walk(cb)
+	if err != errStop {
+		t.Fatalf("Expected visitor error: %v", err)
+	} else if visited != 1 {
+		t.Fatalf("Visit count not correct: (%d)", visited)
+	}
+}
+
+func TestIfd_FprintIfdTree(t *testing.T) {
+	rawExif, err := SearchFileAndExtractExif(getTestImageFilepath())
+	log.PanicIf(err)
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	_, index, err := Collect(im, ti, rawExif)
+	log.PanicIf(err)
+
+	b := new(bytes.Buffer)
+	index.RootIfd.FprintIfdTree(b)
+
+	expected := ` Ifd<ID=(0) IFD-PATH=[IFD] INDEX=(0) COUNT=(12) OFF=(0x0008) CHILDREN=(2) PARENT=(0x0000) NEXT-IFD=(0x2c54)>
+   Ifd<ID=(1) IFD-PATH=[IFD/Exif] INDEX=(0) COUNT=(38) OFF=(0x0168) CHILDREN=(1) PARENT=(0x0008) NEXT-IFD=(0x0000)>
+     Ifd<ID=(4) IFD-PATH=[IFD/Exif/Iop] INDEX=(0) COUNT=(2) OFF=(0x246e) CHILDREN=(0) PARENT=(0x0168) NEXT-IFD=(0x0000)>
+   Ifd<ID=(2) IFD-PATH=[IFD/GPSInfo] INDEX=(0) COUNT=(1) OFF=(0x2552) CHILDREN=(0) PARENT=(0x0008) NEXT-IFD=(0x0000)>
+>Ifd<ID=(3) IFD-PATH=[IFD] INDEX=(1) COUNT=(6) OFF=(0x2c54) CHILDREN=(0) PARENT=(0x0000) NEXT-IFD=(0x0000)>
+`
+
+	if b.String() != expected {
+		t.Fatalf("IFD tree not correct:\n%s", b.String())
+	}
+}
+
+func TestIfd_FprintTagTree(t *testing.T) {
+	rawExif, err := SearchFileAndExtractExif(getTestImageFilepath())
+	log.PanicIf(err)
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	_, index, err := Collect(im, ti, rawExif)
+	log.PanicIf(err)
+
+	b := new(bytes.Buffer)
+	index.RootIfd.FprintTagTree(b, true)
+
+	lines := strings.Split(strings.TrimRight(b.String(), "\n"), "\n")
+
+	expectedFirst := ` IFD: Ifd<ID=(0) IFD-PATH=[IFD] INDEX=(0) COUNT=(12) OFF=(0x0008) CHILDREN=(2) PARENT=(0x0000) NEXT-IFD=(0x2c54)>`
+	if lines[0] != expectedFirst {
+		t.Fatalf("First line not correct: [%s]", lines[0])
+	} else if strings.Contains(b.String(), "NAME=[Model] VALUE=[Canon EOS 5D Mark III]") == false {
+		t.Fatalf("Model tag not printed:\n%s", b.String())
+	}
+}
+
 func ExampleIfd_EnumerateTagsRecursively() {
 	testImageFilepath := getTestImageFilepath()
 
@@ -557,3 +2776,148 @@ func ExampleIfd_FindTagWithName() {
 	// Output:
 	// Canon EOS 5D Mark III
 }
+
+func BenchmarkIfdEnumerate_Collect(b *testing.B) {
+	exifData := getTestExifData()
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_, _, err := Collect(im, ti, exifData)
+		log.PanicIf(err)
+	}
+}
+
+func BenchmarkIfdEnumerate_parseIfd(b *testing.B) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, eh, err := NewIfdEnumerateWithBytes(im, ti, getTestExifData())
+	log.PanicIf(err)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		bp, err := ie.getByteParser(eh.FirstIfdOffset)
+		log.PanicIf(err)
+
+		_, _, _, err = ie.parseIfd(context.Background(), exifcommon.IfdStandardIfdIdentity, bp, nil, false, nil)
+		log.PanicIf(err)
+	}
+}
+
+func BenchmarkIfdEnumerate_CollectWithOptions__RootOnly(b *testing.B) {
+	exifData := getTestExifData()
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	co := &CollectOptions{
+		RootOnly: true,
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		ie, eh, err := NewIfdEnumerateWithBytes(im, ti, exifData)
+		log.PanicIf(err)
+
+		_, err = ie.CollectWithOptions(eh.FirstIfdOffset, co)
+		log.PanicIf(err)
+	}
+}
+
+// getTestManyTagsExifData returns EXIF data with one IFD that has the given
+// number of (inline) SHORT tags.
+func getTestManyTagsExifData(tagCount int) []byte {
+	byteOrder := exifcommon.TestDefaultByteOrder
+
+	exifData := make([]byte, 8+2+12*tagCount+4)
+	copy(exifData, ExifBigEndianSignature[:])
+	byteOrder.PutUint32(exifData[4:], ExifDefaultFirstIfdOffset)
+
+	ifdData := exifData[8:]
+	byteOrder.PutUint16(ifdData[0:], uint16(tagCount))
+
+	for i := 0; i < tagCount; i++ {
+		entryData := ifdData[2+12*i:]
+
+		// ImageWidth
+		byteOrder.PutUint16(entryData[0:], 0x0100)
+		byteOrder.PutUint16(entryData[2:], uint16(exifcommon.TypeShort))
+		byteOrder.PutUint32(entryData[4:], 1)
+		byteOrder.PutUint16(entryData[8:], uint16(i))
+	}
+
+	return exifData
+}
+
+func TestByteParser__NoAllocations(t *testing.T) {
+	exifData := getTestManyTagsExifData(50)
+
+	bp, err := newByteParser(bytes.NewReader(exifData), exifcommon.TestDefaultByteOrder, ExifDefaultFirstIfdOffset)
+	log.PanicIf(err)
+
+	allocations := testing.AllocsPerRun(10, func() {
+		_, err := bp.rs.Seek(int64(ExifDefaultFirstIfdOffset), io.SeekStart)
+		log.PanicIf(err)
+
+		_, err = bp.getUint16()
+		log.PanicIf(err)
+
+		for i := 0; i < 50; i++ {
+			_, err := bp.getUint16()
+			log.PanicIf(err)
+
+			_, err = bp.getUint16()
+			log.PanicIf(err)
+
+			_, err = bp.getUint32()
+			log.PanicIf(err)
+
+			_, err = bp.getUint32()
+			log.PanicIf(err)
+		}
+	})
+
+	if allocations != 0 {
+		t.Fatalf("Reading the IFD fields allocated: (%f)", allocations)
+	}
+}
+
+func BenchmarkIfdEnumerate_parseIfd__50Tags(b *testing.B) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, eh, err := NewIfdEnumerateWithBytes(im, ti, getTestManyTagsExifData(50))
+	log.PanicIf(err)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		bp, err := ie.getByteParser(eh.FirstIfdOffset)
+		log.PanicIf(err)
+
+		_, entries, _, err := ie.parseIfd(context.Background(), exifcommon.IfdStandardIfdIdentity, bp, nil, false, nil)
+		log.PanicIf(err)
+
+		if len(entries) != 50 {
+			b.Fatalf("Entry count not correct: (%d)", len(entries))
+		}
+	}
+}
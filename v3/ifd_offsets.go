@@ -0,0 +1,257 @@
+package exif
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/dsoprea/go-logging"
+)
+
+// OffsetRegion is a range of bytes in the EXIF blob that is used by either an
+// IFD (its tag-count, its entries, and its next-IFD offset) or by a tag value
+// that is stored outside of its entry.
+type OffsetRegion struct {
+	// FqIfdPath is the fully-qualified path of the IFD that has the region.
+	FqIfdPath string
+
+	// TagId is the tag that the value belongs to. It is meaningless if
+	// `IsIfd` is true.
+	TagId uint16
+
+	// IsIfd is true if the region is the IFD structure itself rather than a
+	// tag value.
+	IsIfd bool
+
+	// Offset is where the region starts.
+	Offset uint32
+
+	// Length is how many bytes the region has.
+	Length uint32
+}
+
+// String returns a descriptive string.
+func (or OffsetRegion) String() string {
+	if or.IsIfd == true {
+		return fmt.Sprintf("OffsetRegion<IFD=[%s] OFFSET=(0x%08x) LENGTH=(%d)>", or.FqIfdPath, or.Offset, or.Length)
+	}
+
+	return fmt.Sprintf("OffsetRegion<IFD=[%s] TAG-ID=(0x%04x) OFFSET=(0x%08x) LENGTH=(%d)>", or.FqIfdPath, or.TagId, or.Offset, or.Length)
+}
+
+// end returns the first offset after the region.
+func (or OffsetRegion) end() uint64 {
+	return uint64(or.Offset) + uint64(or.Length)
+}
+
+// OffsetConflict describes two regions that share bytes.
+type OffsetConflict struct {
+	First  OffsetRegion
+	Second OffsetRegion
+
+	// Offset is where the overlap starts.
+	Offset uint32
+
+	// Length is how many bytes overlap.
+	Length uint32
+}
+
+// String returns a descriptive string.
+func (oc OffsetConflict) String() string {
+	return fmt.Sprintf("OffsetConflict<FIRST=%s SECOND=%s OFFSET=(0x%08x) LENGTH=(%d)>", oc.First, oc.Second, oc.Offset, oc.Length)
+}
+
+// ValidateOffsets returns every pair of regions in the given tree that
+// overlap, where the regions are the IFD structures and the values that are
+// stored outside of their entries (including the thumbnail). Offsets are
+// never shared in well-formed EXIF, so any conflict is a sign of corruption
+// (or of something deliberately crafted). The conflicts are ordered by where
+// they start.
+func (ie *IfdEnumerate) ValidateOffsets(rootIfd *Ifd) (conflicts []OffsetConflict, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	regions, err := ie.treeOffsetRegions(rootIfd)
+	log.PanicIf(err)
+
+	conflicts = make([]OffsetConflict, 0)
+
+	for i, first := range regions {
+		for _, second := range regions[i+1:] {
+			if uint64(second.Offset) >= first.end() {
+				break
+			}
+
+			end := first.end()
+			if second.end() < end {
+				end = second.end()
+			}
+
+			oc := OffsetConflict{
+				First:  first,
+				Second: second,
+				Offset: second.Offset,
+				Length: uint32(end - uint64(second.Offset)),
+			}
+
+			conflicts = append(conflicts, oc)
+		}
+	}
+
+	return conflicts, nil
+}
+
+// UsedByteRange returns the span of bytes that the given tree actually uses:
+// the IFD structures and the values that are stored outside of their entries
+// (including the thumbnail). `min` is the first byte that is used and `max` is
+// the offset just past the last one, so the used data is `exifData[min:max]`.
+// Like all offsets in this package, these are relative to the start of the
+// TIFF header (the byte-order marker), not to the start of the file or of the
+// APP1 segment. The eight-byte header itself isn't counted, and bytes between
+// the regions might not be used by anything. Anything in the EXIF data at or
+// after `max` is not referenced by the tree.
+func (ie *IfdEnumerate) UsedByteRange(rootIfd *Ifd) (min, max uint32, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	regions, err := ie.treeOffsetRegions(rootIfd)
+	log.PanicIf(err)
+
+	// The regions are sorted by where they start, and there is always at
+	// least the region of the root IFD.
+	min = regions[0].Offset
+
+	end := uint64(0)
+	for _, region := range regions {
+		if region.end() > end {
+			end = region.end()
+		}
+	}
+
+	if end > math.MaxUint32 {
+		log.Panicf("used bytes end beyond a 32-bit offset: (%d)", end)
+	}
+
+	return min, uint32(end), nil
+}
+
+// treeOffsetRegions returns the regions of every IFD in the given tree,
+// ordered by where they start.
+func (ie *IfdEnumerate) treeOffsetRegions(rootIfd *Ifd) (regions []OffsetRegion, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	regions = make([]OffsetRegion, 0)
+
+	visitor := func(ifd *Ifd, depth int) error {
+		ifdRegions, err := ie.ifdOffsetRegions(ifd)
+		log.PanicIf(err)
+
+		regions = append(regions, ifdRegions...)
+
+		return nil
+	}
+
+	err = rootIfd.Walk(visitor)
+	log.PanicIf(err)
+
+	sort.SliceStable(regions, func(i, j int) bool {
+		return regions[i].Offset < regions[j].Offset
+	})
+
+	return regions, nil
+}
+
+// ifdOffsetRegions returns the region of the IFD structure and of each of its
+// values that doesn't fit in its entry.
+func (ie *IfdEnumerate) ifdOffsetRegions(ifd *Ifd) (regions []OffsetRegion, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	fqIfdPath := ifd.ifdIdentity.String()
+
+	// The entries that we have might not include every entry that is on disk
+	// (invalid ones are dropped), so read the count from the stream.
+	bp, err := ie.getByteParser(ifd.Offset())
+	log.PanicIf(err)
+
+	tagCount, err := bp.getTagCount()
+	log.PanicIf(err)
+
+	// The tag-count, the entries, and the next-IFD offset.
+	ifdRegion := OffsetRegion{
+		FqIfdPath: fqIfdPath,
+		IsIfd:     true,
+		Offset:    ifd.Offset(),
+		Length:    uint32(bp.tagCountLength() + tagCount*bp.entryLength() + bp.offsetLength()),
+	}
+
+	regions = append(regions, ifdRegion)
+
+	var thumbnailOffsetIte *IfdTagEntry
+	var thumbnailSizeIte *IfdTagEntry
+
+	for _, ite := range ifd.Entries() {
+		if ite.ChildIfdPath() != "" {
+			// The value is the offset of the child IFD, which is its own
+			// region.
+			continue
+		} else if ite.IsThumbnailOffset() == true {
+			// This is handled below, once we have the size.
+			thumbnailOffsetIte = ite
+			continue
+		} else if ite.IsThumbnailSize() == true {
+			thumbnailSizeIte = ite
+		}
+
+		isInline, err := ite.isInline()
+		log.PanicIf(err)
+
+		if isInline == true {
+			continue
+		}
+
+		length := valueByteLength(ite)
+
+		valueRegion := OffsetRegion{
+			FqIfdPath: fqIfdPath,
+			TagId:     ite.TagId(),
+			Offset:    ite.getValueOffset(),
+			Length:    uint32(length),
+		}
+
+		regions = append(regions, valueRegion)
+	}
+
+	// The thumbnail-offset tag is a LONG but it points to the thumbnail image.
+	if thumbnailOffsetIte != nil && thumbnailSizeIte != nil && thumbnailSizeIte.UnitCount() == 1 {
+		thumbnailRegion := OffsetRegion{
+			FqIfdPath: fqIfdPath,
+			TagId:     thumbnailOffsetIte.TagId(),
+			Offset:    thumbnailOffsetIte.getValueOffset(),
+			Length:    thumbnailSizeIte.getValueOffset(),
+		}
+
+		regions = append(regions, thumbnailRegion)
+	}
+
+	return regions, nil
+}
+
+// valueByteLength returns how many bytes the value of the tag has.
+func valueByteLength(ite *IfdTagEntry) uint64 {
+	unitSize, _ := ite.TagType().UnitSize()
+	return uint64(unitSize) * uint64(ite.UnitCount())
+}
@@ -0,0 +1,236 @@
+package exif
+
+import (
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/mschilli/go-exif/v3/common"
+)
+
+// getTestOverlappingExifData returns a big-endian EXIF blob with one IFD that
+// has ImageDescription and Make values (eight bytes each) at the given
+// offsets. The IFD is at (8) and is thirty bytes, and there are twelve bytes
+// of data after it.
+func getTestOverlappingExifData(imageDescriptionOffset, makeOffset uint32) []byte {
+	byteOrder := exifcommon.TestDefaultByteOrder
+
+	exifData := make([]byte, 8+30+12)
+	copy(exifData, ExifBigEndianSignature[:])
+	byteOrder.PutUint32(exifData[4:], ExifDefaultFirstIfdOffset)
+
+	ifdData := exifData[8:]
+	byteOrder.PutUint16(ifdData[0:], 2)
+
+	offsets := []uint32{imageDescriptionOffset, makeOffset}
+	for i, tagId := range []uint16{0x010e, 0x010f} {
+		entryData := ifdData[2+12*i:]
+
+		byteOrder.PutUint16(entryData[0:], tagId)
+		byteOrder.PutUint16(entryData[2:], uint16(exifcommon.TypeAscii))
+		byteOrder.PutUint32(entryData[4:], 8)
+		byteOrder.PutUint32(entryData[8:], offsets[i])
+	}
+
+	copy(exifData[8+30:], "abcdefghijk\000")
+
+	return exifData
+}
+
+func TestIfdEnumerate_ValidateOffsets__RealData(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, eh, err := NewIfdEnumerateWithBytes(im, ti, getTestExifData())
+	log.PanicIf(err)
+
+	index, err := ie.Collect(eh.FirstIfdOffset)
+	log.PanicIf(err)
+
+	conflicts, err := ie.ValidateOffsets(index.RootIfd)
+	log.PanicIf(err)
+
+	if len(conflicts) != 0 {
+		t.Fatalf("Expected no conflicts: %v", conflicts)
+	}
+}
+
+func TestIfdEnumerate_ValidateOffsets__OverlappingValues(t *testing.T) {
+	exifData := getTestOverlappingExifData(8+30, 8+30+4)
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, eh, err := NewIfdEnumerateWithBytes(im, ti, exifData)
+	log.PanicIf(err)
+
+	index, err := ie.Collect(eh.FirstIfdOffset)
+	log.PanicIf(err)
+
+	conflicts, err := ie.ValidateOffsets(index.RootIfd)
+	log.PanicIf(err)
+
+	if len(conflicts) != 1 {
+		t.Fatalf("Expected exactly one conflict: %v", conflicts)
+	}
+
+	oc := conflicts[0]
+
+	if oc.First.TagId != 0x010e || oc.Second.TagId != 0x010f {
+		t.Fatalf("Conflicting tags not correct: %s", oc)
+	} else if oc.First.IsIfd == true || oc.Second.IsIfd == true {
+		t.Fatalf("Conflict should be between values: %s", oc)
+	} else if oc.Offset != 8+30+4 || oc.Length != 4 {
+		t.Fatalf("Conflict range not correct: %s", oc)
+	}
+}
+
+func TestIfdEnumerate_ValidateOffsets__ValueOverlapsIfd(t *testing.T) {
+	// The Make value points into the middle of the IFD.
+	exifData := getTestOverlappingExifData(8+30, 10)
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, eh, err := NewIfdEnumerateWithBytes(im, ti, exifData)
+	log.PanicIf(err)
+
+	index, err := ie.Collect(eh.FirstIfdOffset)
+	log.PanicIf(err)
+
+	conflicts, err := ie.ValidateOffsets(index.RootIfd)
+	log.PanicIf(err)
+
+	if len(conflicts) != 1 {
+		t.Fatalf("Expected exactly one conflict: %v", conflicts)
+	}
+
+	oc := conflicts[0]
+
+	if oc.First.IsIfd != true || oc.First.FqIfdPath != "IFD" {
+		t.Fatalf("First region should be the IFD: %s", oc.First)
+	} else if oc.Second.TagId != 0x010f {
+		t.Fatalf("Second region should be the Make value: %s", oc.Second)
+	} else if oc.Offset != 10 || oc.Length != 8 {
+		t.Fatalf("Conflict range not correct: %s", oc)
+	}
+}
+
+func TestIfdEnumerate_UsedByteRange(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	exifData := getTestOverlappingExifData(38, 42)
+
+	// Junk that nothing refers to.
+	exifData = append(exifData, make([]byte, 20)...)
+
+	ie, eh, err := NewIfdEnumerateWithBytes(im, ti, exifData)
+	log.PanicIf(err)
+
+	index, err := ie.Collect(eh.FirstIfdOffset)
+	log.PanicIf(err)
+
+	min, max, err := ie.UsedByteRange(index.RootIfd)
+	log.PanicIf(err)
+
+	if min != 8 {
+		t.Fatalf("Minimum offset not correct: (%d)", min)
+	} else if max != 50 {
+		t.Fatalf("Maximum offset not correct: (%d)", max)
+	}
+}
+
+func TestIfdEnumerate_UsedByteRange__RealData(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	exifData := getTestExifData()
+
+	ie, eh, err := NewIfdEnumerateWithBytes(im, ti, exifData)
+	log.PanicIf(err)
+
+	index, err := ie.Collect(eh.FirstIfdOffset)
+	log.PanicIf(err)
+
+	min, max, err := ie.UsedByteRange(index.RootIfd)
+	log.PanicIf(err)
+
+	thumbnailIfd := index.Lookup[ThumbnailFqIfdPath]
+
+	offsetIte, found := thumbnailIfd.EntryByTagId(ThumbnailOffsetTagId)
+	if found == false {
+		t.Fatalf("Thumbnail offset tag not found.")
+	}
+
+	thumbnailEnd := offsetIte.getValueOffset() + 21491
+
+	if min != eh.FirstIfdOffset {
+		t.Fatalf("Minimum offset not correct: (%d)", min)
+	} else if max < thumbnailEnd {
+		t.Fatalf("Maximum offset is before the end of the thumbnail: (%d) < (%d)", max, thumbnailEnd)
+	} else if int(max) > len(exifData) {
+		t.Fatalf("Maximum offset is past the end of the data: (%d) > (%d)", max, len(exifData))
+	}
+}
+
+func TestIfdEnumerate_UsedByteRange__UnitSizes(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	// The standard tags are only loaded on first use if nothing else was
+	// added.
+	err = LoadStandardTags(ti)
+	log.PanicIf(err)
+
+	it := &IndexedTag{
+		Id:             0xf001,
+		Name:           "TestUndefined",
+		IfdPath:        exifcommon.IfdStandardIfdIdentity.UnindexedString(),
+		SupportedTypes: []exifcommon.TagTypePrimitive{exifcommon.TypeUndefined},
+	}
+
+	err = ti.Add(it)
+	log.PanicIf(err)
+
+	// The values start after the IFD (at 38). The RATIONAL is eight bytes
+	// and each UNDEFINED unit is one.
+	exifData := getTestIfdExifData(
+		exifcommon.TestDefaultByteOrder,
+		testIfdEntry{tagId: 0x011a, tagType: exifcommon.TypeRational, unitCount: 1, value: make([]byte, 8)},
+		testIfdEntry{tagId: 0xf001, tagType: exifcommon.TypeUndefined, unitCount: 5, value: make([]byte, 5)})
+
+	ie, eh, err := NewIfdEnumerateWithBytes(im, ti, exifData)
+	log.PanicIf(err)
+
+	index, err := ie.Collect(eh.FirstIfdOffset)
+	log.PanicIf(err)
+
+	entries := index.RootIfd.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("Entry count not correct: (%d)", len(entries))
+	} else if entries[0].TagType().Name() != "RATIONAL" || entries[1].TagType().Name() != "UNDEFINED" {
+		t.Fatalf("Type names not correct: [%s] [%s]", entries[0].TagType().Name(), entries[1].TagType().Name())
+	}
+
+	min, max, err := ie.UsedByteRange(index.RootIfd)
+	log.PanicIf(err)
+
+	if min != 8 {
+		t.Fatalf("Minimum offset not correct: (%d)", min)
+	} else if max != 51 {
+		t.Fatalf("Maximum offset not correct: (%d)", max)
+	}
+}
@@ -1,6 +1,7 @@
 package exif
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 
@@ -8,12 +9,12 @@ import (
 
 	"github.com/dsoprea/go-logging"
 
-	"github.com/dsoprea/go-exif/v3/common"
-	"github.com/dsoprea/go-exif/v3/undefined"
+	"github.com/mschilli/go-exif/v3/common"
+	"github.com/mschilli/go-exif/v3/undefined"
 )
 
 var (
-	iteLogger = log.NewLogger("exif.ifd_tag_entry")
+	iteLogger = newPackageLogger("exif.ifd_tag_entry")
 )
 
 // IfdTagEntry refers to a tag in the loaded EXIF block.
@@ -25,6 +26,15 @@ type IfdTagEntry struct {
 	valueOffset    uint32
 	rawValueOffset []byte
 
+	// baseOffset is where, in the EXIF data, `valueOffset` is relative to. It
+	// is zero except for the tags of IFDs whose offsets are relative to
+	// something else, like some MakerNotes. See ParseRawIfdWithBaseOffset().
+	baseOffset uint32
+
+	// isBigTiff is true if the tag is from a BigTIFF, whose entries hold
+	// values of up to eight bytes (rather than four).
+	isBigTiff bool
+
 	// childIfdName is the right most atom in the IFD-path. We need this to
 	// construct the fully-qualified IFD-path.
 	childIfdName string
@@ -37,6 +47,11 @@ type IfdTagEntry struct {
 	// child IFD. Includes indices.
 	childFqIfdPath string
 
+	// childIfdIdentity is the identity of the child IFD if this tag
+	// represents a child IFD. It is resolved once, when the tag is parsed,
+	// so that the enumerator doesn't have to construct it again.
+	childIfdIdentity *exifcommon.IfdIdentity
+
 	// TODO(dustin): !! IB's host the child-IBs directly in the tag, but that's not the case here. Refactor to accommodate it for a consistent experience.
 
 	ifdIdentity *exifcommon.IfdIdentity
@@ -47,6 +62,31 @@ type IfdTagEntry struct {
 	byteOrder binary.ByteOrder
 
 	tagName string
+
+	// segment is the position of the EXIF blob that we were parsed from when
+	// several were merged. See CollectSegments().
+	segment int
+
+	// rawEntry is the entry as it was stored in the IFD, if the enumerator
+	// was told to keep it.
+	rawEntry []byte
+
+	// rawValue is the value as it was stored, if it was captured during
+	// collection. See `CollectOptions.CaptureValues`.
+	rawValue []byte
+
+	// isRawOnly is true if the tag is in an IFD that we don't know. See
+	// `CollectOptions.KeepUnknownIfds`.
+	isRawOnly bool
+
+	// valueBudget is shared by all of the tags parsed by the same enumerator
+	// and is charged every time that a value is read. It is nil if there is
+	// no limit. See `IfdEnumerate.SetMaxValueBytes()`.
+	valueBudget *valueBudget
+
+	// modifiedValue is the encoded value that was set with SetValue(), or nil
+	// if the value is still the one that was parsed.
+	modifiedValue []byte
 }
 
 func newIfdTagEntry(ii *exifcommon.IfdIdentity, tagId uint16, tagIndex int, tagType exifcommon.TagTypePrimitive, unitCount uint32, valueOffset uint32, rawValueOffset []byte, rs io.ReadSeeker, byteOrder binary.ByteOrder) *IfdTagEntry {
@@ -121,6 +161,74 @@ func (ite *IfdTagEntry) UnitCount() uint32 {
 	return ite.unitCount
 }
 
+// ByteOrder returns the byte-order that the value of this tag is encoded with.
+// This is the byte-order of the EXIF block that the tag was read from.
+func (ite *IfdTagEntry) ByteOrder() binary.ByteOrder {
+	return ite.byteOrder
+}
+
+// Segment returns the position of the EXIF blob that this tag was parsed
+// from. This is always zero unless several blobs were merged with
+// CollectSegments(), in which case it can differ from that of the IFD that
+// the tag is in.
+func (ite *IfdTagEntry) Segment() int {
+	return ite.segment
+}
+
+// RawEntry returns the twelve bytes of the tag's entry in its IFD, exactly as
+// they were stored (tag-ID, type, unit-count, and value-offset, in the
+// byte-order of the EXIF data). This is nil unless the enumerator was told to
+// keep them. See `IfdEnumerate.SetKeepRawEntries()`.
+func (ite *IfdTagEntry) RawEntry() []byte {
+	return ite.rawEntry
+}
+
+// RawValue returns the bytes of the value exactly as they were stored
+// (whether in the entry or elsewhere), in the byte-order of the EXIF data.
+// This is nil unless the values were captured when the tree was collected.
+// See `CollectOptions.CaptureValues`.
+func (ite *IfdTagEntry) RawValue() []byte {
+	return ite.rawValue
+}
+
+// IsRawOnly returns true if the tag is in an IFD that we don't know, so its
+// value might not mean what its type says. Its UNDEFINED value is never
+// decoded (Value() returns exifcommon.ErrUnhandledUndefinedTypedTag) and
+// GetRawBytes() returns the bytes as they were stored. See
+// `CollectOptions.KeepUnknownIfds`.
+func (ite *IfdTagEntry) IsRawOnly() bool {
+	return ite.isRawOnly
+}
+
+// clone returns a copy of the entry that shares nothing mutable with it.
+func (ite *IfdTagEntry) clone() *IfdTagEntry {
+	clone := new(IfdTagEntry)
+	*clone = *ite
+
+	if ite.rawValueOffset != nil {
+		clone.rawValueOffset = make([]byte, len(ite.rawValueOffset))
+		copy(clone.rawValueOffset, ite.rawValueOffset)
+	}
+
+	if ite.rawEntry != nil {
+		clone.rawEntry = make([]byte, len(ite.rawEntry))
+		copy(clone.rawEntry, ite.rawEntry)
+	}
+
+	if ite.rawValue != nil {
+		clone.rawValue = make([]byte, len(ite.rawValue))
+		copy(clone.rawValue, ite.rawValue)
+	}
+
+	if ite.modifiedValue != nil {
+		clone.modifiedValue = make([]byte, len(ite.modifiedValue))
+		copy(clone.modifiedValue, ite.modifiedValue)
+		clone.rs = bytes.NewReader(clone.modifiedValue)
+	}
+
+	return clone
+}
+
 // updateUnitCount sets an alternatively interpreted unit-count.
 func (ite *IfdTagEntry) updateUnitCount(unitCount uint32) {
 	ite.unitCount = unitCount
@@ -133,6 +241,29 @@ func (ite *IfdTagEntry) getValueOffset() uint32 {
 	return ite.valueOffset
 }
 
+// BaseOffset returns the position in the EXIF data that the value-offset is
+// relative to. This is zero unless the tag came from an IFD that was parsed
+// with ParseRawIfdWithBaseOffset().
+func (ite *IfdTagEntry) BaseOffset() uint32 {
+	return ite.baseOffset
+}
+
+// isInline returns true if the value is stored in the value-offset bytes of
+// the entry itself rather than at the value-offset. See
+// `exifcommon.ValueContext.IsInline()`.
+func (ite *IfdTagEntry) isInline() (isInline bool, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	isInline, err = ite.getValueContext().IsInline(ite.tagType)
+	log.PanicIf(err)
+
+	return isInline, nil
+}
+
 // GetRawBytes renders a specific list of bytes from the value in this tag.
 func (ite *IfdTagEntry) GetRawBytes() (rawBytes []byte, err error) {
 	defer func() {
@@ -141,6 +272,37 @@ func (ite *IfdTagEntry) GetRawBytes() (rawBytes []byte, err error) {
 		}
 	}()
 
+	if ite.isRawOnly == true {
+		rawBytes, err = ite.effectiveValueBytes()
+		if err != nil {
+			if err == exifcommon.ErrNotEnoughData || err == ErrValueBudgetExceeded {
+				return nil, err
+			}
+
+			log.Panic(err)
+		}
+
+		return rawBytes, nil
+	}
+
+	err = ite.checkValueBounds()
+	if err != nil {
+		if err == exifcommon.ErrNotEnoughData {
+			return nil, err
+		}
+
+		log.Panic(err)
+	}
+
+	err = ite.valueBudget.spend(ite)
+	if err != nil {
+		if err == ErrValueBudgetExceeded {
+			return nil, err
+		}
+
+		log.Panic(err)
+	}
+
 	valueContext := ite.getValueContext()
 
 	if ite.tagType == exifcommon.TypeUndefined {
@@ -172,6 +334,9 @@ func (ite *IfdTagEntry) GetRawBytes() (rawBytes []byte, err error) {
 }
 
 // Value returns the specific, parsed, typed value from the tag.
+// exifcommon.ErrNotEnoughData is returned (unwrapped) if the value runs past
+// the end of the data and ErrValueBudgetExceeded if reading it would exceed
+// the enumerator's limit (see `IfdEnumerate.SetMaxValueBytes()`).
 func (ite *IfdTagEntry) Value() (value interface{}, err error) {
 	defer func() {
 		if state := recover(); state != nil {
@@ -179,6 +344,28 @@ func (ite *IfdTagEntry) Value() (value interface{}, err error) {
 		}
 	}()
 
+	if ite.isRawOnly == true && ite.tagType == exifcommon.TypeUndefined {
+		return nil, exifcommon.ErrUnhandledUndefinedTypedTag
+	}
+
+	err = ite.checkValueBounds()
+	if err != nil {
+		if err == exifcommon.ErrNotEnoughData {
+			return nil, err
+		}
+
+		log.Panic(err)
+	}
+
+	err = ite.valueBudget.spend(ite)
+	if err != nil {
+		if err == ErrValueBudgetExceeded {
+			return nil, err
+		}
+
+		log.Panic(err)
+	}
+
 	valueContext := ite.getValueContext()
 
 	if ite.tagType == exifcommon.TypeUndefined {
@@ -202,6 +389,76 @@ func (ite *IfdTagEntry) Value() (value interface{}, err error) {
 	return value, nil
 }
 
+// SetValue replaces the value of the tag in the collected tree. The value
+// has to encode to the tag's current type (e.g. []uint16 for a SHORT tag or a
+// string for an ASCII tag). UNDEFINED tags take either one of the
+// exifundefined types or the raw bytes. The unit-count is updated to match
+// and Value() and GetRawBytes() return the new value from then on, but the
+// EXIF data itself isn't touched. See `IfdEnumerate.Rewrite()`.
+func (ite *IfdTagEntry) SetValue(value interface{}) (err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	var encoded []byte
+	var unitCount uint32
+
+	if ite.tagType == exifcommon.TypeUndefined {
+		if raw, ok := value.([]byte); ok == true {
+			encoded = raw
+			unitCount = uint32(len(raw))
+		} else {
+			encodeable, ok := value.(exifundefined.EncodeableValue)
+			if ok == false {
+				log.Panicf("value for UNDEFINED tag (0x%04x) is not encodeable: [%T]", ite.tagId, value)
+			}
+
+			encoded, unitCount, err = exifundefined.Encode(encodeable, ite.byteOrder)
+			log.PanicIf(err)
+		}
+	} else {
+		ve := exifcommon.NewValueEncoder(ite.byteOrder)
+
+		ed, err := ve.Encode(value)
+		log.PanicIf(err)
+
+		if ed.Type != ite.tagType {
+			log.Panicf("value for tag (0x%04x) is [%s] but the tag is [%s]", ite.tagId, ed.Type, ite.tagType)
+		}
+
+		encoded = ed.Encoded
+		unitCount = ed.UnitCount
+	}
+
+	modifiedValue := make([]byte, len(encoded))
+	copy(modifiedValue, encoded)
+
+	// Point the entry at the new value, as if it had been parsed from it.
+
+	rawValueOffset := make([]byte, ite.inlineLength())
+	copy(rawValueOffset, modifiedValue)
+
+	ite.modifiedValue = modifiedValue
+	ite.unitCount = unitCount
+	ite.valueOffset = 0
+	ite.baseOffset = 0
+	ite.rawValueOffset = rawValueOffset
+	ite.rs = bytes.NewReader(modifiedValue)
+
+	// These describe the value as it was stored.
+	ite.rawEntry = nil
+	ite.rawValue = nil
+
+	return nil
+}
+
+// IsModified returns true if the value was replaced with SetValue().
+func (ite *IfdTagEntry) IsModified() bool {
+	return ite.modifiedValue != nil
+}
+
 // Format returns the tag's value as a string.
 func (ite *IfdTagEntry) Format() (phrase string, err error) {
 	defer func() {
@@ -261,6 +518,13 @@ func (ite *IfdTagEntry) SetChildIfd(ii *exifcommon.IfdIdentity) {
 	ite.childFqIfdPath = ii.String()
 	ite.childIfdPath = ii.UnindexedString()
 	ite.childIfdName = ii.Name()
+	ite.childIfdIdentity = ii
+}
+
+// getChildIfdIdentity returns the identity of the child IFD or nil if we
+// don't represent a child IFD.
+func (ite *IfdTagEntry) getChildIfdIdentity() *exifcommon.IfdIdentity {
+	return ite.childIfdIdentity
 }
 
 // ChildIfdName returns the name of the child IFD
@@ -285,8 +549,86 @@ func (ite *IfdTagEntry) IfdIdentity() *exifcommon.IfdIdentity {
 	return ite.ifdIdentity
 }
 
+// checkValueBounds returns ErrNotEnoughData (unwrapped) if the value is
+// stored outside of the entry and runs past the end of the data. This has to
+// be checked before the value is read since the unit-count comes from the
+// data and might be enormous. See `exifcommon.ValueContext.CheckValueBounds()`.
+func (ite *IfdTagEntry) checkValueBounds() (err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	if ite.tagType.IsValid() == false {
+		// This is caught when the value is read.
+		return nil
+	}
+
+	err = ite.getValueContext().CheckValueBounds(ite.tagType)
+	if err != nil {
+		if err == exifcommon.ErrNotEnoughData {
+			iteLogger.Warningf(nil, "Value for tag (0x%04x) in IFD [%s] runs past the end of the data: OFFSET=(0x%08x) LENGTH=(%d)", ite.tagId, ite.ifdIdentity.String(), ite.valueOffset, valueByteLength(ite))
+			return err
+		}
+
+		log.Panic(err)
+	}
+
+	return nil
+}
+
+// effectiveValueBytes returns the bytes of the value as they were stored,
+// without decoding them (see `exifcommon.ValueContext.EffectiveValueBytes()`).
+// Unlike GetRawBytes(), undefined-type values are not decoded and re-encoded;
+// they are read with `exifcommon.ValueContext.ReadUndefined()`.
+// exifcommon.ErrNotEnoughData is returned (unwrapped) if the value runs past
+// the end of the data and ErrValueBudgetExceeded if reading it would exceed the
+// enumerator's limit.
+func (ite *IfdTagEntry) effectiveValueBytes() (value []byte, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	if ite.tagType.IsValid() == false {
+		log.Panicf("tag (0x%04x) has an invalid type: (%d)", ite.tagId, ite.tagType)
+	}
+
+	err = ite.checkValueBounds()
+	if err != nil {
+		if err == exifcommon.ErrNotEnoughData {
+			return nil, err
+		}
+
+		log.Panic(err)
+	}
+
+	err = ite.valueBudget.spend(ite)
+	if err != nil {
+		if err == ErrValueBudgetExceeded {
+			return nil, err
+		}
+
+		log.Panic(err)
+	}
+
+	valueContext := ite.getValueContext()
+
+	if ite.tagType == exifcommon.TypeUndefined {
+		value, err = valueContext.ReadUndefined()
+		log.PanicIf(err)
+	} else {
+		value, err = valueContext.EffectiveValueBytes(ite.tagType)
+		log.PanicIf(err)
+	}
+
+	return value, nil
+}
+
 func (ite *IfdTagEntry) getValueContext() *exifcommon.ValueContext {
-	return exifcommon.NewValueContext(
+	vc := exifcommon.NewValueContext(
 		ite.ifdIdentity.String(),
 		ite.tagId,
 		ite.unitCount,
@@ -295,4 +637,19 @@ func (ite *IfdTagEntry) getValueContext() *exifcommon.ValueContext {
 		ite.rs,
 		ite.tagType,
 		ite.byteOrder)
+
+	vc.SetBaseOffset(ite.baseOffset)
+	vc.SetInlineLength(ite.inlineLength())
+
+	return vc
+}
+
+// inlineLength returns how many bytes a value can have and still be stored in
+// the entry itself.
+func (ite *IfdTagEntry) inlineLength() int {
+	if ite.isBigTiff == true {
+		return bigTiffOffsetSize
+	}
+
+	return 4
 }
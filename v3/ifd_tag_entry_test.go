@@ -2,12 +2,16 @@ package exif
 
 import (
 	"bytes"
+	"fmt"
+	"reflect"
 	"testing"
 
+	"encoding/binary"
+
 	"github.com/dsoprea/go-logging"
 	"github.com/dsoprea/go-utility/v2/filesystem"
 
-	"github.com/dsoprea/go-exif/v3/common"
+	"github.com/mschilli/go-exif/v3/common"
 )
 
 func TestIfdTagEntry_RawBytes_Allocated(t *testing.T) {
@@ -66,6 +70,58 @@ func TestIfdTagEntry_RawBytes_Embedded(t *testing.T) {
 	}
 }
 
+func TestIfdTagEntry_Value__PastEnd(t *testing.T) {
+	data := []byte{0x11, 0x22, 0x33, 0x44, 0x55, 0x66, 0x77, 0x88}
+	sb := rifs.NewSeekableBufferWithBytes(data)
+
+	// The unit-count is far larger than the data. This must fail before
+	// anything is allocated for it.
+	ite := newIfdTagEntry(
+		exifcommon.IfdStandardIfdIdentity,
+		0x1,
+		0,
+		exifcommon.TypeLong,
+		0x40000000,
+		0,
+		nil,
+		sb,
+		exifcommon.TestDefaultByteOrder)
+
+	_, err := ite.Value()
+	if err != exifcommon.ErrNotEnoughData {
+		t.Fatalf("Expected ErrNotEnoughData for Value(): %v", err)
+	}
+
+	_, err = ite.GetRawBytes()
+	if err != exifcommon.ErrNotEnoughData {
+		t.Fatalf("Expected ErrNotEnoughData for GetRawBytes(): %v", err)
+	}
+}
+
+func TestIfdTagEntry_Value__AtEnd(t *testing.T) {
+	data := []byte{0x11, 0x22, 0x33, 0x44, 0x55, 0x66, 0x77, 0x88}
+	sb := rifs.NewSeekableBufferWithBytes(data)
+
+	ite := newIfdTagEntry(
+		exifcommon.IfdStandardIfdIdentity,
+		0x1,
+		0,
+		exifcommon.TypeShort,
+		3,
+		2,
+		nil,
+		sb,
+		binary.BigEndian)
+
+	value, err := ite.Value()
+	log.PanicIf(err)
+
+	expected := []uint16{0x3344, 0x5566, 0x7788}
+	if reflect.DeepEqual(value, expected) != true {
+		t.Fatalf("Value not correct: %v", value)
+	}
+}
+
 func TestIfdTagEntry_String(t *testing.T) {
 	ite := newIfdTagEntry(
 		exifcommon.IfdStandardIfdIdentity,
@@ -83,3 +139,441 @@ func TestIfdTagEntry_String(t *testing.T) {
 		t.Fatalf("string representation not expected: [%s] != [%s]", ite.String(), expected)
 	}
 }
+
+func TestIfdTagEntry_ByteOrder(t *testing.T) {
+	ite := newIfdTagEntry(
+		exifcommon.IfdStandardIfdIdentity,
+		0x1,
+		0,
+		exifcommon.TypeByte,
+		6,
+		0,
+		nil,
+		nil,
+		binary.LittleEndian)
+
+	if ite.ByteOrder() != binary.LittleEndian {
+		t.Fatalf("ByteOrder() not correct: %v", ite.ByteOrder())
+	}
+}
+
+func TestIfdTagEntry_SetValue(t *testing.T) {
+	data := []byte{0x11, 0x22, 0x33, 0x44, 0x55, 0x66}
+	sb := rifs.NewSeekableBufferWithBytes(data)
+
+	ite := newIfdTagEntry(
+		exifcommon.IfdStandardIfdIdentity,
+		0x1,
+		0,
+		exifcommon.TypeShort,
+		3,
+		0,
+		nil,
+		sb,
+		exifcommon.TestDefaultByteOrder)
+
+	if ite.IsModified() == true {
+		t.Fatalf("Entry should not be modified yet.")
+	}
+
+	err := ite.SetValue([]uint16{1, 2, 3, 4})
+	log.PanicIf(err)
+
+	value, err := ite.Value()
+	log.PanicIf(err)
+
+	if ite.IsModified() == false {
+		t.Fatalf("Entry should be modified.")
+	} else if ite.UnitCount() != 4 {
+		t.Fatalf("Unit-count not updated: (%d)", ite.UnitCount())
+	} else if reflect.DeepEqual(value, []uint16{1, 2, 3, 4}) == false {
+		t.Fatalf("Value not correct: %v", value)
+	} else if bytes.Equal(data, []byte{0x11, 0x22, 0x33, 0x44, 0x55, 0x66}) == false {
+		t.Fatalf("Original data was modified.")
+	}
+
+	// Small enough to be stored in the entry.
+
+	err = ite.SetValue([]uint16{5})
+	log.PanicIf(err)
+
+	rawBytes, err := ite.GetRawBytes()
+	log.PanicIf(err)
+
+	if bytes.Equal(rawBytes, []byte{0x00, 0x05}) == false {
+		t.Fatalf("Raw bytes not correct: %v", rawBytes)
+	}
+}
+
+func TestIfdTagEntry_SetValue__WrongType(t *testing.T) {
+	ite := newIfdTagEntry(
+		exifcommon.IfdStandardIfdIdentity,
+		0x1,
+		0,
+		exifcommon.TypeShort,
+		1,
+		0,
+		[]byte{0x00, 0x01, 0x00, 0x00},
+		nil,
+		exifcommon.TestDefaultByteOrder)
+
+	err := ite.SetValue([]uint32{1})
+	if err == nil {
+		t.Fatalf("Expected failure for a LONG value for a SHORT tag.")
+	} else if ite.IsModified() == true {
+		t.Fatalf("Entry should not have been modified.")
+	}
+}
+
+func TestIfdTagEntry_effectiveValueBytes__InlineByteOrder(t *testing.T) {
+	cases := []struct {
+		byteOrder     binary.ByteOrder
+		expectedShort []byte
+		expectedLong  []byte
+	}{
+		{binary.LittleEndian, []byte{0x02, 0x01, 0x04, 0x03}, []byte{0x04, 0x03, 0x02, 0x01}},
+		{binary.BigEndian, []byte{0x01, 0x02, 0x03, 0x04}, []byte{0x01, 0x02, 0x03, 0x04}},
+	}
+
+	for _, c := range cases {
+		im, err := exifcommon.NewIfdMappingWithStandard()
+		log.PanicIf(err)
+
+		ti := NewTagIndex()
+		ib := NewIfdBuilder(im, ti, exifcommon.IfdStandardIfdIdentity, c.byteOrder)
+
+		// YCbCrSubSampling (two SHORTs) and ImageWidth (one LONG).
+
+		err = ib.AddStandard(0x0212, []uint16{0x0102, 0x0304})
+		log.PanicIf(err)
+
+		err = ib.AddStandard(0x0100, []uint32{0x01020304})
+		log.PanicIf(err)
+
+		ibe := NewIfdByteEncoder()
+
+		exifData, err := ibe.EncodeToExif(ib)
+		log.PanicIf(err)
+
+		_, index, err := Collect(im, ti, exifData)
+		log.PanicIf(err)
+
+		shortIte, _, err := index.FindTag(exifcommon.IfdStandardIfdIdentity.String(), 0x0212)
+		log.PanicIf(err)
+
+		longIte, _, err := index.FindTag(exifcommon.IfdStandardIfdIdentity.String(), 0x0100)
+		log.PanicIf(err)
+
+		shortBytes, err := shortIte.effectiveValueBytes()
+		log.PanicIf(err)
+
+		longBytes, err := longIte.effectiveValueBytes()
+		log.PanicIf(err)
+
+		if bytes.Equal(shortBytes, c.expectedShort) == false {
+			t.Fatalf("SHORT bytes not correct for %s: %v", c.byteOrder, shortBytes)
+		} else if bytes.Equal(longBytes, c.expectedLong) == false {
+			t.Fatalf("LONG bytes not correct for %s: %v", c.byteOrder, longBytes)
+		}
+
+		shortValue, err := shortIte.Value()
+		log.PanicIf(err)
+
+		longValue, err := longIte.Value()
+		log.PanicIf(err)
+
+		if reflect.DeepEqual(shortValue, []uint16{0x0102, 0x0304}) == false {
+			t.Fatalf("SHORT value not correct for %s: %v", c.byteOrder, shortValue)
+		} else if reflect.DeepEqual(longValue, []uint32{0x01020304}) == false {
+			t.Fatalf("LONG value not correct for %s: %v", c.byteOrder, longValue)
+		}
+	}
+}
+
+func TestIfdTagEntry_Value__Ascii(t *testing.T) {
+	// Make is stored after the IFD, Model is inline, and Software claims
+	// more bytes than there are.
+	exifData := getTestIfdExifData(
+		exifcommon.TestDefaultByteOrder,
+		testIfdEntry{tagId: 0x010f, tagType: exifcommon.TypeAscii, unitCount: 10, value: []byte("Canon EOS\000")},
+		testIfdEntry{tagId: 0x0110, tagType: exifcommon.TypeAscii, unitCount: 3, value: []byte("ab\000")},
+		testIfdEntry{tagId: 0x0131, tagType: exifcommon.TypeAscii, unitCount: 20, value: []byte("short\000")})
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	_, index, err := Collect(im, ti, exifData)
+	log.PanicIf(err)
+
+	cases := map[uint16]string{
+		0x010f: "Canon EOS",
+		0x0110: "ab",
+	}
+
+	for tagId, expected := range cases {
+		ite, _, err := index.FindTag(exifcommon.IfdStandardIfdIdentity.String(), tagId)
+		log.PanicIf(err)
+
+		value, err := ite.Value()
+		log.PanicIf(err)
+
+		if value.(string) != expected {
+			t.Fatalf("Value for (0x%04x) not correct: [%s] != [%s]", tagId, value, expected)
+		}
+	}
+
+	ite, _, err := index.FindTag(exifcommon.IfdStandardIfdIdentity.String(), 0x0131)
+	log.PanicIf(err)
+
+	_, err = ite.Value()
+	if err != exifcommon.ErrNotEnoughData {
+		t.Fatalf("Expected ErrNotEnoughData: %v", err)
+	}
+}
+
+func TestIfdTagEntry_effectiveValueBytes__Undefined(t *testing.T) {
+	// The first value is inline, the second is stored after the IFD, and the
+	// third claims more bytes than there are.
+	exifData := getTestIfdExifData(
+		exifcommon.TestDefaultByteOrder,
+		testIfdEntry{tagId: 0xf001, tagType: exifcommon.TypeUndefined, unitCount: 3, value: []byte{1, 2, 3}},
+		testIfdEntry{tagId: 0xf002, tagType: exifcommon.TypeUndefined, unitCount: 6, value: []byte{4, 5, 6, 7, 8, 9}},
+		testIfdEntry{tagId: 0xf003, tagType: exifcommon.TypeUndefined, unitCount: 100, value: []byte{10, 11}})
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	for i, tagId := range []uint16{0xf001, 0xf002, 0xf003} {
+		it := &IndexedTag{
+			Id:             tagId,
+			Name:           fmt.Sprintf("TestUndefined%d", i),
+			IfdPath:        exifcommon.IfdStandardIfdIdentity.UnindexedString(),
+			SupportedTypes: []exifcommon.TagTypePrimitive{exifcommon.TypeUndefined},
+		}
+
+		err = ti.Add(it)
+		log.PanicIf(err)
+	}
+
+	_, index, err := Collect(im, ti, exifData)
+	log.PanicIf(err)
+
+	cases := map[uint16][]byte{
+		0xf001: {1, 2, 3},
+		0xf002: {4, 5, 6, 7, 8, 9},
+	}
+
+	for tagId, expected := range cases {
+		ite, _, err := index.FindTag(exifcommon.IfdStandardIfdIdentity.String(), tagId)
+		log.PanicIf(err)
+
+		value, err := ite.effectiveValueBytes()
+		log.PanicIf(err)
+
+		if bytes.Equal(value, expected) == false {
+			t.Fatalf("Bytes for (0x%04x) not correct: %v", tagId, value)
+		}
+	}
+
+	ite, _, err := index.FindTag(exifcommon.IfdStandardIfdIdentity.String(), 0xf003)
+	log.PanicIf(err)
+
+	_, err = ite.effectiveValueBytes()
+	if err != exifcommon.ErrNotEnoughData {
+		t.Fatalf("Expected ErrNotEnoughData: %v", err)
+	}
+}
+
+func TestIfdTagEntry_Value__Bounds(t *testing.T) {
+	value := []byte{0x11, 0x22, 0x33, 0x44, 0x55, 0x66}
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	// The three BitsPerSample SHORTs end exactly at the end of the data.
+
+	exifData := getTestIfdExifData(
+		exifcommon.TestDefaultByteOrder,
+		testIfdEntry{tagId: 0x0102, tagType: exifcommon.TypeShort, unitCount: 3, value: value})
+
+	_, index, err := Collect(im, ti, exifData)
+	log.PanicIf(err)
+
+	ite, _, err := index.FindTag(exifcommon.IfdStandardIfdIdentity.String(), 0x0102)
+	log.PanicIf(err)
+
+	shorts, err := ite.Value()
+	log.PanicIf(err)
+
+	if reflect.DeepEqual(shorts, []uint16{0x1122, 0x3344, 0x5566}) == false {
+		t.Fatalf("Value not correct: %v", shorts)
+	}
+
+	// The same with the last byte missing.
+
+	exifData = getTestIfdExifData(
+		exifcommon.TestDefaultByteOrder,
+		testIfdEntry{tagId: 0x0102, tagType: exifcommon.TypeShort, unitCount: 3, value: value[:5]})
+
+	_, index, err = Collect(im, ti, exifData)
+	log.PanicIf(err)
+
+	ite, _, err = index.FindTag(exifcommon.IfdStandardIfdIdentity.String(), 0x0102)
+	log.PanicIf(err)
+
+	_, err = ite.Value()
+	if err != exifcommon.ErrNotEnoughData {
+		t.Fatalf("Expected ErrNotEnoughData for Value(): %v", err)
+	}
+
+	_, err = ite.GetRawBytes()
+	if err != exifcommon.ErrNotEnoughData {
+		t.Fatalf("Expected ErrNotEnoughData for GetRawBytes(): %v", err)
+	}
+
+	_, err = ite.Format()
+	if log.Is(err, exifcommon.ErrNotEnoughData) == false {
+		t.Fatalf("Expected ErrNotEnoughData for Format(): %v", err)
+	}
+}
+
+func TestIfdTagEntry_isInline(t *testing.T) {
+	exifData := getTestIfdExifData(
+		exifcommon.TestDefaultByteOrder,
+		testIfdEntry{tagId: 0x0100, tagType: exifcommon.TypeShort, unitCount: 2, value: make([]byte, 4)},
+		testIfdEntry{tagId: 0x0102, tagType: exifcommon.TypeShort, unitCount: 3, value: make([]byte, 6)},
+		testIfdEntry{tagId: 0x010f, tagType: exifcommon.TypeAscii, unitCount: 4, value: []byte("abc\000")},
+		testIfdEntry{tagId: 0x0110, tagType: exifcommon.TypeAscii, unitCount: 5, value: []byte("abcd\000")},
+		testIfdEntry{tagId: 0x011a, tagType: exifcommon.TypeRational, unitCount: 1, value: make([]byte, 8)})
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	_, index, err := Collect(im, ti, exifData)
+	log.PanicIf(err)
+
+	cases := map[uint16]bool{
+		0x0100: true,
+		0x0102: false,
+		0x010f: true,
+		0x0110: false,
+		0x011a: false,
+	}
+
+	for tagId, expected := range cases {
+		ite, _, err := index.FindTag(exifcommon.IfdStandardIfdIdentity.String(), tagId)
+		log.PanicIf(err)
+
+		isInline, err := ite.isInline()
+		log.PanicIf(err)
+
+		if isInline != expected {
+			t.Fatalf("Inline-ness of (0x%04x) not correct: %v", tagId, isInline)
+		}
+	}
+}
+
+func TestIfdTagEntry_Value__ZeroUnitCount(t *testing.T) {
+	// Whatever is in the value-offset field must not be read.
+	exifData := getTestIfdExifData(
+		exifcommon.TestDefaultByteOrder,
+		testIfdEntry{tagId: 0x010f, tagType: exifcommon.TypeAscii, unitCount: 0, value: []byte("abc\000")},
+		testIfdEntry{tagId: 0x0102, tagType: exifcommon.TypeShort, unitCount: 0, value: []byte{0x11, 0x22, 0x33, 0x44}})
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	_, index, err := Collect(im, ti, exifData)
+	log.PanicIf(err)
+
+	asciiIte, _, err := index.FindTag(exifcommon.IfdStandardIfdIdentity.String(), 0x010f)
+	log.PanicIf(err)
+
+	asciiValue, err := asciiIte.Value()
+	log.PanicIf(err)
+
+	if asciiValue.(string) != "" {
+		t.Fatalf("ASCII value not empty: [%s]", asciiValue)
+	}
+
+	shortIte, _, err := index.FindTag(exifcommon.IfdStandardIfdIdentity.String(), 0x0102)
+	log.PanicIf(err)
+
+	shortValue, err := shortIte.Value()
+	log.PanicIf(err)
+
+	if len(shortValue.([]uint16)) != 0 {
+		t.Fatalf("SHORT value not empty: %v", shortValue)
+	}
+
+	rawBytes, err := shortIte.GetRawBytes()
+	log.PanicIf(err)
+
+	if len(rawBytes) != 0 {
+		t.Fatalf("SHORT bytes not empty: %v", rawBytes)
+	}
+}
+
+func TestIfdTagEntry_Value__InlineByteOrder(t *testing.T) {
+	// The bytes are laid out by hand so that this doesn't depend on the
+	// builder getting the order right.
+	cases := []struct {
+		byteOrder  binary.ByteOrder
+		shortBytes []byte
+		longBytes  []byte
+	}{
+		{binary.LittleEndian, []byte{0x02, 0x01, 0x04, 0x03}, []byte{0x04, 0x03, 0x02, 0x01}},
+		{binary.BigEndian, []byte{0x01, 0x02, 0x03, 0x04}, []byte{0x01, 0x02, 0x03, 0x04}},
+	}
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	for _, c := range cases {
+		// YCbCrSubSampling (two SHORTs) and ImageWidth (one LONG).
+		exifData := getTestIfdExifData(
+			c.byteOrder,
+			testIfdEntry{tagId: 0x0212, tagType: exifcommon.TypeShort, unitCount: 2, value: c.shortBytes},
+			testIfdEntry{tagId: 0x0100, tagType: exifcommon.TypeLong, unitCount: 1, value: c.longBytes})
+
+		_, index, err := Collect(im, ti, exifData)
+		log.PanicIf(err)
+
+		shortIte, _, err := index.FindTag(exifcommon.IfdStandardIfdIdentity.String(), 0x0212)
+		log.PanicIf(err)
+
+		longIte, _, err := index.FindTag(exifcommon.IfdStandardIfdIdentity.String(), 0x0100)
+		log.PanicIf(err)
+
+		shortValue, err := shortIte.Value()
+		log.PanicIf(err)
+
+		longValue, err := longIte.Value()
+		log.PanicIf(err)
+
+		if reflect.DeepEqual(shortValue, []uint16{0x0102, 0x0304}) == false {
+			t.Fatalf("SHORT value not correct for %s: %v", c.byteOrder, shortValue)
+		} else if reflect.DeepEqual(longValue, []uint32{0x01020304}) == false {
+			t.Fatalf("LONG value not correct for %s: %v", c.byteOrder, longValue)
+		}
+
+		shortRawBytes, err := shortIte.GetRawBytes()
+		log.PanicIf(err)
+
+		if bytes.Equal(shortRawBytes, c.shortBytes) == false {
+			t.Fatalf("SHORT bytes not correct for %s: %v", c.byteOrder, shortRawBytes)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package exif
+
+import (
+	"bytes"
+	"errors"
+
+	"encoding/binary"
+
+	"github.com/dsoprea/go-logging"
+)
+
+var (
+	jpegLogger = newPackageLogger("exif.jpeg")
+)
+
+var (
+	// ErrNotJpeg means that the data does not start with the JPEG SOI marker.
+	ErrNotJpeg = errors.New("not a JPEG")
+)
+
+const (
+	jpegMarkerSoi  = 0xd8
+	jpegMarkerEoi  = 0xd9
+	jpegMarkerSos  = 0xda
+	jpegMarkerApp1 = 0xe1
+	jpegMarkerTem  = 0x01
+	jpegMarkerRst0 = 0xd0
+	jpegMarkerRst7 = 0xd7
+)
+
+var (
+	// JpegExifPrefix is the identifier at the front of the APP1 segment that
+	// has the EXIF data.
+	JpegExifPrefix = []byte{'E', 'x', 'i', 'f', 0, 0}
+)
+
+// SearchJpegAndExtractExif walks the JPEG segment markers and returns the
+// EXIF data from the first APP1 segment that starts with the "Exif\0\0"
+// identifier. Other APP1 segments (e.g. XMP) are skipped. Unlike
+// SearchAndExtractExif(), this does not scan the image for anything that
+// looks like a TIFF header and the data that is returned ends with the
+// segment. The identifier is stripped, so the data starts at the TIFF header
+// like everywhere else in this package. ErrNotJpeg is returned (unwrapped) if
+// the data is not a JPEG and ErrNoExif if there is no EXIF segment before the
+// image data.
+func SearchJpegAndExtractExif(jpegData []byte) (rawExif []byte, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	if len(jpegData) < 2 || jpegData[0] != 0xff || jpegData[1] != jpegMarkerSoi {
+		return nil, ErrNotJpeg
+	}
+
+	offset := 2
+	for offset < len(jpegData) {
+		if jpegData[offset] != 0xff {
+			jpegLogger.Warningf(nil, "Expected a JPEG marker at offset (%d) but found (0x%02x).", offset, jpegData[offset])
+			break
+		}
+
+		// Markers may be preceded by any number of fill bytes.
+		for offset < len(jpegData) && jpegData[offset] == 0xff {
+			offset++
+		}
+
+		if offset >= len(jpegData) {
+			break
+		}
+
+		marker := jpegData[offset]
+		offset++
+
+		if marker == jpegMarkerSos || marker == jpegMarkerEoi {
+			break
+		} else if marker == jpegMarkerTem || (marker >= jpegMarkerRst0 && marker <= jpegMarkerRst7) {
+			// These have no length or payload.
+			continue
+		}
+
+		if offset+2 > len(jpegData) {
+			break
+		}
+
+		// The length includes itself but not the marker.
+		length := int(binary.BigEndian.Uint16(jpegData[offset : offset+2]))
+		if length < 2 || offset+length > len(jpegData) {
+			jpegLogger.Warningf(nil, "JPEG segment (0x%02x) at offset (%d) has an invalid length: (%d)", marker, offset, length)
+			break
+		}
+
+		payload := jpegData[offset+2 : offset+length]
+
+		if marker == jpegMarkerApp1 && bytes.HasPrefix(payload, JpegExifPrefix) == true {
+			return payload[len(JpegExifPrefix):], nil
+		}
+
+		offset += length
+	}
+
+	return nil, ErrNoExif
+}
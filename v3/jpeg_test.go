@@ -0,0 +1,75 @@
+package exif
+
+import (
+	"bytes"
+	"testing"
+
+	"encoding/binary"
+	"io/ioutil"
+
+	"github.com/dsoprea/go-logging"
+)
+
+func getTestJpegSegment(marker byte, payload []byte) []byte {
+	segment := []byte{0xff, marker, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+
+	return append(segment, payload...)
+}
+
+func TestSearchJpegAndExtractExif(t *testing.T) {
+	jpegData, err := ioutil.ReadFile(getTestImageFilepath())
+	log.PanicIf(err)
+
+	rawExif, err := SearchJpegAndExtractExif(jpegData)
+	log.PanicIf(err)
+
+	// The scanning search returns everything to the end of the file, so ours
+	// should be a prefix of it.
+	scannedRawExif, err := SearchAndExtractExif(jpegData)
+	log.PanicIf(err)
+
+	if len(rawExif) >= len(scannedRawExif) {
+		t.Fatalf("EXIF data should be bounded by its segment: (%d) >= (%d)", len(rawExif), len(scannedRawExif))
+	} else if bytes.Equal(rawExif, scannedRawExif[:len(rawExif)]) != true {
+		t.Fatalf("EXIF data not correct.")
+	}
+}
+
+func TestSearchJpegAndExtractExif__SkipsOtherApp1(t *testing.T) {
+	exifData := getTestExifData()
+
+	jpegData := []byte{0xff, 0xd8}
+	jpegData = append(jpegData, getTestJpegSegment(0xe0, []byte("JFIF\000\001\002"))...)
+	jpegData = append(jpegData, getTestJpegSegment(0xe1, []byte("http://ns.adobe.com/xap/1.0/\000<x/>"))...)
+	jpegData = append(jpegData, getTestJpegSegment(0xe1, append([]byte("Exif\000\000"), exifData...))...)
+	jpegData = append(jpegData, 0xff, 0xd9)
+
+	rawExif, err := SearchJpegAndExtractExif(jpegData)
+	log.PanicIf(err)
+
+	if bytes.Equal(rawExif, exifData) != true {
+		t.Fatalf("EXIF data not correct.")
+	}
+}
+
+func TestSearchJpegAndExtractExif__NoExif(t *testing.T) {
+	jpegData := []byte{0xff, 0xd8}
+	jpegData = append(jpegData, getTestJpegSegment(0xe0, []byte("JFIF\000\001\002"))...)
+	jpegData = append(jpegData, getTestJpegSegment(0xda, []byte{1, 2, 3})...)
+
+	// Anything after the start of the image data is not looked at.
+	jpegData = append(jpegData, getTestJpegSegment(0xe1, append([]byte("Exif\000\000"), getTestExifData()...))...)
+
+	_, err := SearchJpegAndExtractExif(jpegData)
+	if err != ErrNoExif {
+		t.Fatalf("Expected no-EXIF error: %v", err)
+	}
+}
+
+func TestSearchJpegAndExtractExif__NotJpeg(t *testing.T) {
+	_, err := SearchJpegAndExtractExif(getTestExifData())
+	if err != ErrNotJpeg {
+		t.Fatalf("Expected not-JPEG error: %v", err)
+	}
+}
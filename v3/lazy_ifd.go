@@ -0,0 +1,390 @@
+package exif
+
+import (
+	"context"
+	"sync"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/mschilli/go-exif/v3/common"
+)
+
+// lazyIfdTree is the state shared by all of the nodes of one lazy tree. The
+// enumerator's own IFD-chain cycle detection is shared as well, since every
+// node is parsed by the same enumerator.
+type lazyIfdTree struct {
+	lock sync.Mutex
+
+	ie *IfdEnumerate
+
+	// nodes are the IFDs that have already been parsed, by IFD-path and
+	// offset.
+	nodes map[string]map[uint32]*LazyIfd
+
+	count int
+}
+
+// LazyIfd is an IFD whose children and next IFD are only parsed the first
+// time that they are asked for (and then cached). See `IfdEnumerate.Root()`.
+// It is safe to navigate from several goroutines.
+type LazyIfd struct {
+	tree  *lazyIfdTree
+	ifd   *Ifd
+	depth int
+
+	childrenLoaded bool
+	children       []*LazyIfd
+
+	// childrenByTagIndex are the children that have been loaded so far, by
+	// the index of the tag that points to them.
+	childrenByTagIndex map[int]*LazyIfd
+
+	nextLoaded bool
+	next       *LazyIfd
+}
+
+// Root returns the root IFD of a lazy tree. Only the root IFD itself is
+// parsed. Every call returns a new tree.
+func (ie *IfdEnumerate) Root() (root *LazyIfd, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	lazyIe, eh, err := ie.headerEnumerator()
+	log.PanicIf(err)
+
+	tree := &lazyIfdTree{
+		ie:    lazyIe,
+		nodes: make(map[string]map[uint32]*LazyIfd),
+	}
+
+	qi := QueuedIfd{
+		IfdIdentity: exifcommon.IfdStandardIfdIdentity,
+		Offset:      eh.FirstIfdOffset,
+	}
+
+	root, err = tree.load(qi, true)
+	if err != nil {
+		if knownErr := asKnownReadError(err); knownErr != nil {
+			return nil, knownErr
+		}
+
+		log.Panic(err)
+	}
+
+	return root, nil
+}
+
+// load parses the queued IFD or returns the node that was already parsed for
+// it. A nil node is returned if the IFD was skipped (see collect()). The
+// caller must hold the lock.
+func (tree *lazyIfdTree) load(qi QueuedIfd, isRequired bool) (node *LazyIfd, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	ie := tree.ie
+	ii := qi.IfdIdentity
+	ifdPath := ii.UnindexedString()
+
+	visitedOffsets, found := tree.nodes[ifdPath]
+	if found == false {
+		visitedOffsets = make(map[uint32]*LazyIfd)
+		tree.nodes[ifdPath] = visitedOffsets
+	}
+
+	if node, found := visitedOffsets[qi.Offset]; found == true {
+		// Children may be shared, but a chain that links back to itself
+		// would never end.
+		if qi.Parent != nil {
+			return node, nil
+		} else if ie.strictMode == true {
+			return nil, ErrIfdCycle
+		}
+
+		ifdEnumerateLogger.Warningf(nil, "IFD [%s] at offset (0x%08x) has already been parsed. There might be a cycle. Skipping.", ii.String(), qi.Offset)
+		return nil, nil
+	}
+
+	if qi.Depth > DefaultMaxIfdDepth {
+		ifdEnumerateLogger.Warningf(nil, "IFD [%s] at offset (0x%08x) is nested deeper than (%d). Skipping.", ii.String(), qi.Offset, DefaultMaxIfdDepth)
+		return nil, nil
+	}
+
+	if tree.count >= DefaultMaxIfdCount {
+		ifdEnumerateLogger.Warningf(nil, "More than (%d) IFDs were found. Giving up.", DefaultMaxIfdCount)
+		return nil, ErrTooManyIfds
+	}
+
+	isRequired = isRequired || ie.strictMode == true
+
+	bp, err := ie.getByteParser(qi.Offset)
+	if err != nil {
+		if err == ErrOffsetInvalid && isRequired == false {
+			ifdEnumerateLogger.Warningf(nil, "IFD [%s] at offset (0x%08x) is beyond the end of the data. Skipping.", ii.String(), qi.Offset)
+			return nil, nil
+		} else if err == ErrOffsetInvalid {
+			return nil, err
+		}
+
+		log.Panic(err)
+	}
+
+	nextIfdOffset, entries, thumbnailData, err := ie.parseIfd(context.Background(), ii, bp, nil, false, nil)
+	if err != nil {
+		knownErr := asKnownReadError(err)
+		if (knownErr == ErrTagCountInvalid || knownErr == ErrTruncatedData) && isRequired == false {
+			ifdEnumerateLogger.Warningf(nil, "IFD [%s] at offset (0x%08x) runs past the end of the data. Skipping.", ii.String(), qi.Offset)
+			return nil, nil
+		} else if knownErr != nil {
+			return nil, knownErr
+		}
+
+		log.Panic(err)
+	}
+
+	entriesByTagId := make(map[uint16][]*IfdTagEntry)
+	for _, ite := range entries {
+		tagId := ite.TagId()
+		entriesByTagId[tagId] = append(entriesByTagId[tagId], ite)
+	}
+
+	ifd := &Ifd{
+		ifdIdentity: ii,
+
+		byteOrder: ie.byteOrder,
+
+		id: tree.count,
+
+		parentIfd:      qi.Parent,
+		parentTagIndex: qi.ParentTagIndex,
+
+		offset:         qi.Offset,
+		entries:        entries,
+		entriesByTagId: entriesByTagId,
+
+		// Children are never linked here. See `LazyIfd.Children()`.
+		children: make([]*Ifd, 0),
+
+		nextIfdOffset: nextIfdOffset,
+		thumbnailData: thumbnailData,
+
+		ifdMapping: ie.ifdMapping,
+		tagIndex:   ie.tagIndex,
+	}
+
+	tree.count++
+
+	node = &LazyIfd{
+		tree:               tree,
+		ifd:                ifd,
+		depth:              qi.Depth,
+		childrenByTagIndex: make(map[int]*LazyIfd),
+	}
+
+	visitedOffsets[qi.Offset] = node
+
+	return node, nil
+}
+
+// Ifd returns the parsed IFD. Its entries are all available, but its
+// children and next IFD are not linked (use Children(), Child(), and
+// NextIfd()).
+func (li *LazyIfd) Ifd() *Ifd {
+	return li.ifd
+}
+
+// Children returns the child IFDs, parsing the ones that haven't been parsed
+// yet. Children that can't be parsed are skipped with a warning, as in
+// Collect(), unless the enumerator is in strict mode.
+func (li *LazyIfd) Children() (children []*LazyIfd, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	li.tree.lock.Lock()
+	defer li.tree.lock.Unlock()
+
+	if li.childrenLoaded == true {
+		return li.children, nil
+	}
+
+	children = make([]*LazyIfd, 0)
+
+	for i, ite := range li.ifd.entries {
+		if ite.getChildIfdIdentity() == nil {
+			continue
+		}
+
+		child, err := li.loadChild(i, ite)
+		if err != nil {
+			if knownErr := asKnownReadError(err); knownErr != nil {
+				return nil, knownErr
+			} else if err == ErrTooManyIfds {
+				return nil, err
+			}
+
+			log.Panic(err)
+		}
+
+		if child != nil {
+			children = append(children, child)
+		}
+	}
+
+	// The SubIFDs tag refers to any number of child IFDs.
+	for i, ite := range li.ifd.entries {
+		if ite.TagId() != SubIfdsTagId || ite.ChildIfdPath() != "" {
+			continue
+		}
+
+		subIfdQis, err := subIfdQueue(li.ifd, ite, i, li.depth)
+		log.PanicIf(err)
+
+		for _, qi := range subIfdQis {
+			child, err := li.tree.load(qi, false)
+			if err != nil {
+				if knownErr := asKnownReadError(err); knownErr != nil {
+					return nil, knownErr
+				} else if err == ErrTooManyIfds {
+					return nil, err
+				}
+
+				log.Panic(err)
+			}
+
+			if child != nil {
+				children = append(children, child)
+			}
+		}
+	}
+
+	li.children = children
+	li.childrenLoaded = true
+
+	return children, nil
+}
+
+// Child returns the child IFD with the given name (e.g. "Exif" or "GPSInfo"),
+// parsing only that one if it hasn't been parsed yet. ErrTagNotFound is
+// returned (unwrapped) if this IFD doesn't point to a child with that name or
+// if the child was skipped.
+func (li *LazyIfd) Child(ifdName string) (child *LazyIfd, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	li.tree.lock.Lock()
+	defer li.tree.lock.Unlock()
+
+	for i, ite := range li.ifd.entries {
+		if ite.getChildIfdIdentity() == nil || ite.ChildIfdName() != ifdName {
+			continue
+		}
+
+		child, err := li.loadChild(i, ite)
+		if err != nil {
+			if knownErr := asKnownReadError(err); knownErr != nil {
+				return nil, knownErr
+			} else if err == ErrTooManyIfds {
+				return nil, err
+			}
+
+			log.Panic(err)
+		}
+
+		if child == nil {
+			return nil, ErrTagNotFound
+		}
+
+		return child, nil
+	}
+
+	return nil, ErrTagNotFound
+}
+
+// loadChild returns the child that the tag at the given index points to. The
+// caller must hold the lock.
+func (li *LazyIfd) loadChild(tagIndex int, ite *IfdTagEntry) (child *LazyIfd, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	if child, found := li.childrenByTagIndex[tagIndex]; found == true {
+		return child, nil
+	}
+
+	qi := QueuedIfd{
+		IfdIdentity: ite.getChildIfdIdentity(),
+
+		Offset:         ite.getValueOffset(),
+		Parent:         li.ifd,
+		ParentTagIndex: tagIndex,
+		Depth:          li.depth + 1,
+	}
+
+	child, err = li.tree.load(qi, false)
+	if err != nil {
+		return nil, err
+	}
+
+	li.childrenByTagIndex[tagIndex] = child
+
+	return child, nil
+}
+
+// NextIfd returns the next IFD in the chain, parsing it if it hasn't been
+// parsed yet, or nil if this is the last one. A link back to an IFD that is
+// earlier in the chain ends the chain with a warning (or fails with
+// ErrIfdCycle in strict mode), as in Collect().
+func (li *LazyIfd) NextIfd() (next *LazyIfd, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	li.tree.lock.Lock()
+	defer li.tree.lock.Unlock()
+
+	if li.nextLoaded == true {
+		return li.next, nil
+	}
+
+	nextIfdOffset := li.ifd.nextIfdOffset
+	if nextIfdOffset != 0 {
+		ii := li.ifd.ifdIdentity
+
+		qi := QueuedIfd{
+			IfdIdentity: ii.NewSibling(ii.Index() + 1),
+			Offset:      nextIfdOffset,
+			Depth:       li.depth,
+		}
+
+		next, err = li.tree.load(qi, false)
+		if err != nil {
+			if knownErr := asKnownReadError(err); knownErr != nil {
+				return nil, knownErr
+			} else if err == ErrTooManyIfds {
+				return nil, err
+			}
+
+			log.Panic(err)
+		}
+	}
+
+	li.next = next
+	li.nextLoaded = true
+
+	return next, nil
+}
@@ -0,0 +1,145 @@
+package exif
+
+import (
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/mschilli/go-exif/v3/common"
+)
+
+func TestIfdEnumerate_Root(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, _, err := NewIfdEnumerateWithBytes(im, ti, getTestExifData())
+	log.PanicIf(err)
+
+	root, err := ie.Root()
+	log.PanicIf(err)
+
+	_, index, err := Collect(im, ti, getTestExifData())
+	log.PanicIf(err)
+
+	if root.Ifd().Offset() != index.RootIfd.Offset() {
+		t.Fatalf("Root offset not correct: (0x%08x)", root.Ifd().Offset())
+	} else if len(root.Ifd().Entries()) != len(index.RootIfd.Entries()) {
+		t.Fatalf("Root entry count not correct: (%d)", len(root.Ifd().Entries()))
+	} else if root.tree.count != 1 {
+		t.Fatalf("Expected only the root to be parsed: (%d)", root.tree.count)
+	}
+
+	exifIfd, err := root.Child("Exif")
+	log.PanicIf(err)
+
+	if exifIfd.Ifd().ifdIdentity.String() != "IFD/Exif" {
+		t.Fatalf("Child not correct: [%s]", exifIfd.Ifd().ifdIdentity.String())
+	} else if exifIfd.Ifd().Path() != "IFD/Exif" {
+		t.Fatalf("Child path not correct: [%s]", exifIfd.Ifd().Path())
+	} else if root.tree.count != 2 {
+		t.Fatalf("Expected only the root and the Exif IFD to be parsed: (%d)", root.tree.count)
+	}
+
+	again, err := root.Child("Exif")
+	log.PanicIf(err)
+
+	if again != exifIfd {
+		t.Fatalf("Child not cached.")
+	}
+
+	children, err := root.Children()
+	log.PanicIf(err)
+
+	if len(children) != 2 {
+		t.Fatalf("Child count not correct: (%d)", len(children))
+	} else if children[0] != exifIfd {
+		t.Fatalf("Cached child not reused.")
+	} else if children[1].Ifd().ifdIdentity.String() != "IFD/GPSInfo" {
+		t.Fatalf("Second child not correct: [%s]", children[1].Ifd().ifdIdentity.String())
+	}
+
+	nextIfd, err := root.NextIfd()
+	log.PanicIf(err)
+
+	if nextIfd.Ifd().ifdIdentity.String() != ThumbnailFqIfdPath {
+		t.Fatalf("Next IFD not correct: [%s]", nextIfd.Ifd().ifdIdentity.String())
+	}
+
+	thumbnailData, err := nextIfd.Ifd().Thumbnail()
+	log.PanicIf(err)
+
+	if len(thumbnailData) != 21491 {
+		t.Fatalf("Thumbnail size not correct: (%d)", len(thumbnailData))
+	}
+
+	lastIfd, err := nextIfd.NextIfd()
+	log.PanicIf(err)
+
+	if lastIfd != nil {
+		t.Fatalf("Expected the chain to end.")
+	}
+}
+
+func TestLazyIfd_Child__NotFound(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, _, err := NewIfdEnumerateWithBytes(im, ti, getTestExifData())
+	log.PanicIf(err)
+
+	root, err := ie.Root()
+	log.PanicIf(err)
+
+	_, err = root.Child("Iop")
+	if err != ErrTagNotFound {
+		t.Fatalf("Expected ErrTagNotFound: %v", err)
+	}
+}
+
+func TestLazyIfd_NextIfd__Cycle(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	// IFD0 (at 8) links to IFD1 (at 26), which links back to IFD0.
+	exifData := getTestIfdChainExifData(26, 8)
+
+	ie, _, err := NewIfdEnumerateWithBytes(im, ti, exifData)
+	log.PanicIf(err)
+
+	root, err := ie.Root()
+	log.PanicIf(err)
+
+	count := 0
+	for current := root; current != nil; {
+		count++
+		if count > 10 {
+			t.Fatalf("Chain did not end.")
+		}
+
+		current, err = current.NextIfd()
+		log.PanicIf(err)
+	}
+
+	if count != 2 {
+		t.Fatalf("Chain length not correct: (%d)", count)
+	}
+
+	ie.SetStrictMode(true)
+
+	root, err = ie.Root()
+	log.PanicIf(err)
+
+	nextIfd, err := root.NextIfd()
+	log.PanicIf(err)
+
+	_, err = nextIfd.NextIfd()
+	if err != ErrIfdCycle {
+		t.Fatalf("Expected ErrIfdCycle: %v", err)
+	}
+}
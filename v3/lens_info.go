@@ -0,0 +1,134 @@
+package exif
+
+import (
+	"github.com/dsoprea/go-logging"
+
+	"github.com/mschilli/go-exif/v3/common"
+)
+
+const (
+	lensSpecificationTagId = 0xa432
+	lensMakeTagId          = 0xa433
+	lensModelTagId         = 0xa434
+)
+
+// LensField flags one of the fields of LensInfo.
+type LensField uint
+
+const (
+	// LensFieldMake flags `LensInfo.Make`.
+	LensFieldMake LensField = 1 << iota
+
+	// LensFieldModel flags `LensInfo.Model`.
+	LensFieldModel
+
+	// LensFieldSpecification flags `LensInfo.MinFocalLength`,
+	// `LensInfo.MaxFocalLength`, `LensInfo.MinFNumberAtMinFocalLength`, and
+	// `LensInfo.MinFNumberAtMaxFocalLength`.
+	LensFieldSpecification
+)
+
+// LensInfo has the lens tags from the Exif IFD. Fields whose tags are missing
+// (or don't have the expected shape) are left zero and are not flagged in
+// `Present`.
+type LensInfo struct {
+	// Make is the lens manufacturer.
+	Make string
+
+	// Model is the lens model (e.g. "EF16-35mm f/4L IS USM").
+	Model string
+
+	// MinFocalLength is the shortest focal-length in millimeters.
+	MinFocalLength float64
+
+	// MaxFocalLength is the longest focal-length in millimeters. This is the
+	// same as `MinFocalLength` for a prime lens.
+	MaxFocalLength float64
+
+	// MinFNumberAtMinFocalLength is the widest aperture at the shortest
+	// focal-length.
+	MinFNumberAtMinFocalLength float64
+
+	// MinFNumberAtMaxFocalLength is the widest aperture at the longest
+	// focal-length.
+	MinFNumberAtMaxFocalLength float64
+
+	// Present flags which of the fields were found. Parts of the
+	// specification that the camera recorded as unknown (0/0) are zero.
+	Present LensField
+}
+
+// Has returns true if the given field was found.
+func (li *LensInfo) Has(field LensField) bool {
+	return li.Present&field != 0
+}
+
+// LensInfo returns the lens tags from the Exif IFD. Missing tags are not an
+// error. See `LensInfo`.
+func (index IfdIndex) LensInfo() (li *LensInfo, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	li = new(LensInfo)
+
+	exifIfd, found := index.Lookup[exifcommon.IfdExifStandardIfdIdentity.String()]
+	if found == false {
+		return li, nil
+	}
+
+	if value, found := lensValue(exifIfd, lensMakeTagId); found == true {
+		if s, ok := value.(string); ok == true {
+			li.Make = s
+			li.Present |= LensFieldMake
+		}
+	}
+
+	if value, found := lensValue(exifIfd, lensModelTagId); found == true {
+		if s, ok := value.(string); ok == true {
+			li.Model = s
+			li.Present |= LensFieldModel
+		}
+	}
+
+	if value, found := lensValue(exifIfd, lensSpecificationTagId); found == true {
+		if rationals, ok := value.([]exifcommon.Rational); ok == true && len(rationals) == 4 {
+			li.MinFocalLength = rationals[0].Float64()
+			li.MaxFocalLength = rationals[1].Float64()
+			li.MinFNumberAtMinFocalLength = rationals[2].Float64()
+			li.MinFNumberAtMaxFocalLength = rationals[3].Float64()
+			li.Present |= LensFieldSpecification
+		}
+	}
+
+	return li, nil
+}
+
+// lensValue returns the value of the given tag. Values that can't be read are
+// logged and treated as missing.
+func lensValue(ifd *Ifd, tagId uint16) (value interface{}, found bool) {
+	ite, found := ifd.EntryByTagId(tagId)
+	if found == false {
+		return nil, false
+	}
+
+	value, err := ite.Value()
+	if err != nil {
+		ifdEnumerateLogger.Warningf(nil, "Could not read lens tag (0x%04x) and it will be ignored: %v", tagId, err)
+		return nil, false
+	}
+
+	return value, true
+}
+
+// LensInfo returns the lens tags from the Exif IFD. See `IfdIndex.LensInfo()`.
+func (ie *IfdEnumerate) LensInfo() (li *LensInfo, err error) {
+	index, err := ie.getHeaderIndex(false)
+	if err != nil {
+		return nil, err
+	}
+
+	return index.LensInfo()
+}
@@ -0,0 +1,54 @@
+package exif
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/mschilli/go-exif/v3/common"
+)
+
+func TestIfdEnumerate_LensInfo(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, _, err := NewIfdEnumerateWithBytes(im, ti, getTestExifData())
+	log.PanicIf(err)
+
+	li, err := ie.LensInfo()
+	log.PanicIf(err)
+
+	// There is no LensMake tag.
+	expected := &LensInfo{
+		Model:          "EF16-35mm f/4L IS USM",
+		MinFocalLength: 16,
+		MaxFocalLength: 35,
+		Present:        LensFieldModel | LensFieldSpecification,
+	}
+
+	if reflect.DeepEqual(li, expected) == false {
+		t.Fatalf("Lens-info not correct: %v != %v", li, expected)
+	} else if li.Has(LensFieldMake) == true {
+		t.Fatalf("Expected Make to not be present.")
+	}
+}
+
+func TestIfdEnumerate_LensInfo__Missing(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, _, err := NewIfdEnumerateWithBytes(im, ti, getTestOrientationExifData(1))
+	log.PanicIf(err)
+
+	li, err := ie.LensInfo()
+	log.PanicIf(err)
+
+	if reflect.DeepEqual(li, &LensInfo{}) == false {
+		t.Fatalf("Expected empty lens-info: %v", li)
+	}
+}
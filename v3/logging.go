@@ -0,0 +1,99 @@
+package exif
+
+import (
+	"context"
+	"sync"
+
+	"github.com/dsoprea/go-logging"
+)
+
+// Logger is what the package logs through. *log.Logger (go-logging)
+// satisfies it.
+type Logger interface {
+	Debugf(ctx context.Context, format string, args ...interface{})
+	Infof(ctx context.Context, format string, args ...interface{})
+	Warningf(ctx context.Context, format string, args ...interface{})
+	Errorf(ctx context.Context, errRaw interface{}, format string, args ...interface{})
+}
+
+var (
+	injectedLogger     Logger
+	injectedLoggerLock sync.RWMutex
+)
+
+// SetLogger routes all of the logging in this package through the given
+// logger instead of the go-logging loggers that we otherwise create for each
+// file. Use NopLogger to silence it entirely. Passing nil restores the
+// default behavior.
+func SetLogger(logger Logger) {
+	injectedLoggerLock.Lock()
+	defer injectedLoggerLock.Unlock()
+
+	injectedLogger = logger
+}
+
+// nopLogger discards everything.
+type nopLogger struct{}
+
+func (nopLogger) Debugf(ctx context.Context, format string, args ...interface{}) {
+}
+
+func (nopLogger) Infof(ctx context.Context, format string, args ...interface{}) {
+}
+
+func (nopLogger) Warningf(ctx context.Context, format string, args ...interface{}) {
+}
+
+func (nopLogger) Errorf(ctx context.Context, errRaw interface{}, format string, args ...interface{}) {
+}
+
+var (
+	// NopLogger can be passed to SetLogger in order to discard all logging.
+	NopLogger Logger = nopLogger{}
+)
+
+// packageLogger forwards to the injected logger, if there is one, and
+// otherwise to its own go-logging logger.
+type packageLogger struct {
+	defaultLogger *log.Logger
+}
+
+// newPackageLogger returns a logger for the given noun that honors
+// SetLogger().
+func newPackageLogger(noun string) *packageLogger {
+	return &packageLogger{
+		defaultLogger: log.NewLogger(noun),
+	}
+}
+
+// logger returns the logger that messages should currently go to.
+func (pl *packageLogger) logger() Logger {
+	injectedLoggerLock.RLock()
+	defer injectedLoggerLock.RUnlock()
+
+	if injectedLogger != nil {
+		return injectedLogger
+	}
+
+	return pl.defaultLogger
+}
+
+// Debugf logs a debug message.
+func (pl *packageLogger) Debugf(ctx context.Context, format string, args ...interface{}) {
+	pl.logger().Debugf(ctx, format, args...)
+}
+
+// Infof logs an informational message.
+func (pl *packageLogger) Infof(ctx context.Context, format string, args ...interface{}) {
+	pl.logger().Infof(ctx, format, args...)
+}
+
+// Warningf logs a warning.
+func (pl *packageLogger) Warningf(ctx context.Context, format string, args ...interface{}) {
+	pl.logger().Warningf(ctx, format, args...)
+}
+
+// Errorf logs an error.
+func (pl *packageLogger) Errorf(ctx context.Context, errRaw interface{}, format string, args ...interface{}) {
+	pl.logger().Errorf(ctx, errRaw, format, args...)
+}
@@ -0,0 +1,92 @@
+package exif
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/mschilli/go-exif/v3/common"
+)
+
+type recordingLogger struct {
+	messages []string
+}
+
+func (rl *recordingLogger) record(level, format string, args []interface{}) {
+	rl.messages = append(rl.messages, level+": "+fmt.Sprintf(format, args...))
+}
+
+func (rl *recordingLogger) Debugf(ctx context.Context, format string, args ...interface{}) {
+	rl.record("DEBUG", format, args)
+}
+
+func (rl *recordingLogger) Infof(ctx context.Context, format string, args ...interface{}) {
+	rl.record("INFO", format, args)
+}
+
+func (rl *recordingLogger) Warningf(ctx context.Context, format string, args ...interface{}) {
+	rl.record("WARNING", format, args)
+}
+
+func (rl *recordingLogger) Errorf(ctx context.Context, errRaw interface{}, format string, args ...interface{}) {
+	rl.record("ERROR", format, args)
+}
+
+func TestSetLogger(t *testing.T) {
+	rl := new(recordingLogger)
+
+	SetLogger(rl)
+	defer SetLogger(nil)
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	_, _, err = Collect(im, ti, getTestExifData())
+	log.PanicIf(err)
+
+	if len(rl.messages) == 0 {
+		t.Fatalf("Nothing was logged through the injected logger.")
+	}
+
+	expected := "DEBUG: Parsing IFD [IFD] (0) at offset (0x0008) (Collect)."
+
+	found := false
+	for _, message := range rl.messages {
+		if message == expected {
+			found = true
+			break
+		}
+	}
+
+	if found == false {
+		t.Fatalf("Expected message not logged: [%s]\n%v", expected, rl.messages)
+	}
+
+	SetLogger(nil)
+
+	count := len(rl.messages)
+
+	_, _, err = Collect(im, ti, getTestExifData())
+	log.PanicIf(err)
+
+	if len(rl.messages) != count {
+		t.Fatalf("Logger was still used after being reset.")
+	}
+}
+
+func TestSetLogger__Nop(t *testing.T) {
+	SetLogger(NopLogger)
+	defer SetLogger(nil)
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	_, _, err = Collect(im, ti, getTestExifData())
+	log.PanicIf(err)
+}
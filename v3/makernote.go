@@ -0,0 +1,252 @@
+package exif
+
+import (
+	"strings"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/mschilli/go-exif/v3/common"
+)
+
+const (
+	// MakerNoteTagId is the ID of the MakerNote tag in the Exif IFD.
+	MakerNoteTagId = 0x927c
+
+	// MakerNoteIfdName is the name that the IFD produced from a MakerNote
+	// is given (e.g. "IFD/Exif/MakerNote").
+	MakerNoteIfdName = "MakerNote"
+
+	makeTagId = 0x010f
+)
+
+// MakerNoteParser knows how to parse the vendor-specific MakerNote data of one
+// camera make into an IFD.
+type MakerNoteParser interface {
+	// ParseMakerNote returns the IFD that the MakerNote tag `ite` describes.
+	// The returned IFD must be assigned the identity `ii`.
+	ParseMakerNote(ie *IfdEnumerate, ii *exifcommon.IfdIdentity, ite *IfdTagEntry) (ifd *Ifd, err error)
+}
+
+var (
+	makerNoteParsers = make(map[string]MakerNoteParser)
+)
+
+// RegisterMakerNoteParser registers a parser for the MakerNote data written by
+// cameras whose IFD0 Make tag is `cameraMake` (e.g. "Canon"). No parsers are
+// registered by default, so MakerNotes are left as opaque UNDEFINED tags
+// unless this is called.
+func RegisterMakerNoteParser(cameraMake string, parser MakerNoteParser) {
+	_, found := makerNoteParsers[cameraMake]
+	if found == true {
+		log.Panicf("maker-note parser already registered: [%s]", cameraMake)
+	}
+
+	makerNoteParsers[cameraMake] = parser
+}
+
+// parseMakerNote returns the IFD for the MakerNote tag in `exifIfd` if there
+// is a parser registered for the make given in `rootIfd`. If there isn't, or
+// if the parser fails, nil is returned and the tag stays opaque.
+func (ie *IfdEnumerate) parseMakerNote(rootIfd, exifIfd *Ifd) (makerNoteIfd *Ifd, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	if len(makerNoteParsers) == 0 || rootIfd == nil {
+		return nil, nil
+	}
+
+	ite, found := exifIfd.EntryByTagId(MakerNoteTagId)
+	if found == false {
+		return nil, nil
+	}
+
+	makeIte, found := rootIfd.EntryByTagId(makeTagId)
+	if found == false {
+		return nil, nil
+	}
+
+	makeRaw, err := makeIte.Value()
+	if err != nil {
+		ifdEnumerateLogger.Warningf(nil, "Could not read the Make tag. Not parsing the MakerNote: %v", err)
+		return nil, nil
+	}
+
+	cameraMake, ok := makeRaw.(string)
+	if ok == false {
+		return nil, nil
+	}
+
+	cameraMake = strings.TrimRight(cameraMake, " \000")
+
+	parser, found := makerNoteParsers[cameraMake]
+	if found == false {
+		return nil, nil
+	}
+
+	exifIfdTag := exifIfd.ifdIdentity.IfdTag()
+
+	makerNoteIfdTag :=
+		exifcommon.NewIfdTag(
+			&exifIfdTag,
+			MakerNoteTagId,
+			MakerNoteIfdName)
+
+	iiMakerNote := exifIfd.ifdIdentity.NewChild(makerNoteIfdTag, 0)
+
+	makerNoteIfd, err = parser.ParseMakerNote(ie, iiMakerNote, ite)
+	if err != nil {
+		ifdEnumerateLogger.Warningf(nil, "Could not parse the [%s] MakerNote. Leaving it opaque: %v", cameraMake, err)
+		return nil, nil
+	}
+
+	makerNoteIfd.parentIfd = exifIfd
+
+	for i, entry := range exifIfd.entries {
+		if entry == ite {
+			makerNoteIfd.parentTagIndex = i
+			break
+		}
+	}
+
+	return makerNoteIfd, nil
+}
+
+// ParseRawIfd parses the IFD at `ifdOffset` without consulting the tag index
+// or the IFD mapping. This is meant for vendor IFDs whose tags we don't know,
+// so the tags will not have names. Tags with an invalid type are skipped. The
+// next-IFD offset is not followed.
+func (ie *IfdEnumerate) ParseRawIfd(ii *exifcommon.IfdIdentity, ifdOffset uint32) (ifd *Ifd, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	ifd, err = ie.ParseRawIfdWithBaseOffset(ii, ifdOffset, 0)
+	if err != nil {
+		if err == ErrOffsetInvalid || err == ErrTagCountInvalid {
+			return nil, err
+		}
+
+		log.Panic(err)
+	}
+
+	return ifd, nil
+}
+
+// ParseRawIfdWithBaseOffset is ParseRawIfd() for IFDs whose value-offsets are
+// relative to `baseOffset` rather than to the start of the EXIF data, like the
+// MakerNotes of Nikon and Olympus, whose offsets count from the start of the
+// MakerNote. `ifdOffset` is still relative to the start of the EXIF data.
+func (ie *IfdEnumerate) ParseRawIfdWithBaseOffset(ii *exifcommon.IfdIdentity, ifdOffset uint32, baseOffset uint32) (ifd *Ifd, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	bp, err := ie.getByteParser(ifdOffset)
+	if err != nil {
+		if err == ErrOffsetInvalid {
+			return nil, err
+		}
+
+		log.Panic(err)
+	}
+
+	tagCount, err := bp.getUint16()
+	log.PanicIf(err)
+
+	requiredLength := int64(bp.CurrentOffset()) + int64(tagCount)*12 + 4
+	if requiredLength > bp.dataLength {
+		return nil, ErrTagCountInvalid
+	}
+
+	entries := make([]*IfdTagEntry, 0, tagCount)
+	entriesByTagId := make(map[uint16][]*IfdTagEntry)
+
+	for i := 0; i < int(tagCount); i++ {
+		tagId, err := bp.getUint16()
+		log.PanicIf(err)
+
+		tagTypeRaw, err := bp.getUint16()
+		log.PanicIf(err)
+
+		unitCount, err := bp.getUint32()
+		log.PanicIf(err)
+
+		valueOffset, rawValueOffset, err := bp.getUint32WithRaw()
+		log.PanicIf(err)
+
+		// MakerNotes are plain TIFF IFDs, even in a BigTIFF.
+		tagType := exifcommon.TagTypePrimitive(tagTypeRaw)
+		if tagType.IsValid() == false || tagType.IsBigTiffOnly() == true {
+			ifdEnumerateLogger.Warningf(nil, "Raw tag (0x%04x) in IFD [%s] has invalid type (0x%04x) and will be skipped.", tagId, ii, tagTypeRaw)
+			continue
+		}
+
+		rs, err := ie.getValueReadSeeker()
+		log.PanicIf(err)
+
+		ite := newIfdTagEntry(
+			ii,
+			tagId,
+			i,
+			tagType,
+			unitCount,
+			valueOffset,
+			rawValueOffset,
+			rs,
+			ie.byteOrder)
+
+		ite.baseOffset = baseOffset
+
+		entries = append(entries, ite)
+		entriesByTagId[tagId] = append(entriesByTagId[tagId], ite)
+	}
+
+	nextIfdOffset, err := bp.getUint32()
+	log.PanicIf(err)
+
+	ifd = &Ifd{
+		ifdIdentity: ii,
+
+		byteOrder: ie.byteOrder,
+
+		offset:         ifdOffset,
+		entries:        entries,
+		entriesByTagId: entriesByTagId,
+
+		children:      make([]*Ifd, 0),
+		childIfdIndex: make(map[string]*Ifd),
+
+		nextIfdOffset: nextIfdOffset,
+
+		ifdMapping: ie.ifdMapping,
+		tagIndex:   ie.tagIndex,
+	}
+
+	return ifd, nil
+}
+
+// CanonMakerNoteParser parses Canon MakerNotes. These are a plain IFD, in the
+// byte-order of the image, whose offsets are relative to the start of the EXIF
+// data like all other offsets.
+type CanonMakerNoteParser struct{}
+
+// ParseMakerNote returns the IFD that the MakerNote tag `ite` describes.
+func (CanonMakerNoteParser) ParseMakerNote(ie *IfdEnumerate, ii *exifcommon.IfdIdentity, ite *IfdTagEntry) (ifd *Ifd, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	ifd, err = ie.ParseRawIfd(ii, ite.getValueOffset())
+	log.PanicIf(err)
+
+	return ifd, nil
+}
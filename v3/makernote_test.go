@@ -0,0 +1,170 @@
+package exif
+
+import (
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/mschilli/go-exif/v3/common"
+)
+
+func TestCanonMakerNoteParser(t *testing.T) {
+	RegisterMakerNoteParser("Canon", CanonMakerNoteParser{})
+	defer delete(makerNoteParsers, "Canon")
+
+	rawExif, err := SearchFileAndExtractExif(getTestImageFilepath())
+	log.PanicIf(err)
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	_, index, err := Collect(im, ti, rawExif)
+	log.PanicIf(err)
+
+	exifIfd := index.Lookup[exifcommon.IfdExifStandardIfdIdentity.String()]
+
+	makerNoteIfd := exifIfd.MakerNoteIfd()
+	if makerNoteIfd == nil {
+		t.Fatalf("MakerNote IFD not found.")
+	} else if makerNoteIfd.IfdIdentity().UnindexedString() != "IFD/Exif/MakerNote" {
+		t.Fatalf("MakerNote IFD-path not correct: [%s]", makerNoteIfd.IfdIdentity().UnindexedString())
+	} else if index.Lookup["IFD/Exif/MakerNote"] != makerNoteIfd {
+		t.Fatalf("MakerNote IFD not in lookup.")
+	} else if len(makerNoteIfd.Entries()) != 40 {
+		t.Fatalf("MakerNote tag count not correct: (%d)", len(makerNoteIfd.Entries()))
+	}
+
+	// Canon's ImageType.
+	ite, found := makerNoteIfd.EntryByTagId(0x0006)
+	if found == false {
+		t.Fatalf("ImageType tag not found.")
+	}
+
+	value, err := ite.Value()
+	log.PanicIf(err)
+
+	if value.(string) != "Canon EOS 5D Mark III" {
+		t.Fatalf("ImageType not correct: [%s]", value)
+	}
+
+	// The MakerNote tag itself is still a plain UNDEFINED value.
+	makerNoteIte, found := exifIfd.EntryByTagId(MakerNoteTagId)
+	if found == false {
+		t.Fatalf("MakerNote tag not found.")
+	} else if makerNoteIte.ChildIfdPath() != "" {
+		t.Fatalf("MakerNote tag should not be an IFD pointer.")
+	}
+}
+
+func TestIfdEnumerate_Collect__MakerNoteNotRegistered(t *testing.T) {
+	rawExif, err := SearchFileAndExtractExif(getTestImageFilepath())
+	log.PanicIf(err)
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	_, index, err := Collect(im, ti, rawExif)
+	log.PanicIf(err)
+
+	exifIfd := index.Lookup[exifcommon.IfdExifStandardIfdIdentity.String()]
+
+	if exifIfd.MakerNoteIfd() != nil {
+		t.Fatalf("Expected no MakerNote IFD.")
+	} else if len(index.Ifds) != 5 {
+		t.Fatalf("IFD count not correct: (%d)", len(index.Ifds))
+	}
+
+	if len(index.SkippedIfds) != 1 {
+		t.Fatalf("Expected exactly one skipped IFD: %v", index.SkippedIfds)
+	}
+
+	si := index.SkippedIfds[0]
+	if si.FqIfdPath != "IFD/Exif/MakerNote" {
+		t.Fatalf("Skipped IFD path not correct: [%s]", si.FqIfdPath)
+	} else if si.TagId != MakerNoteTagId {
+		t.Fatalf("Skipped IFD tag not correct: (0x%04x)", si.TagId)
+	} else if si.Reason != SkipReasonNoMakerNoteParser {
+		t.Fatalf("Skipped IFD reason not correct: [%s]", si.Reason)
+	} else if si.Offset == 0 {
+		t.Fatalf("Skipped IFD offset not set.")
+	}
+}
+
+// relativeMakerNoteParser parses MakerNotes that are a plain IFD whose offsets
+// are relative to the start of the MakerNote.
+type relativeMakerNoteParser struct{}
+
+func (relativeMakerNoteParser) ParseMakerNote(ie *IfdEnumerate, ii *exifcommon.IfdIdentity, ite *IfdTagEntry) (ifd *Ifd, err error) {
+	return ie.ParseRawIfdWithBaseOffset(ii, ite.getValueOffset(), ite.getValueOffset())
+}
+
+func TestIfdEnumerate_ParseRawIfdWithBaseOffset(t *testing.T) {
+	RegisterMakerNoteParser("TestMake", relativeMakerNoteParser{})
+	defer delete(makerNoteParsers, "TestMake")
+
+	byteOrder := exifcommon.TestDefaultByteOrder
+
+	// IFD0 (at 8) has the Make and points to the Exif IFD (at 48), whose
+	// MakerNote (at 66) is an IFD with one ASCII tag at (18) relative to the
+	// start of the MakerNote.
+	exifData := make([]byte, 90)
+	copy(exifData, ExifBigEndianSignature[:])
+	byteOrder.PutUint32(exifData[4:], ExifDefaultFirstIfdOffset)
+
+	byteOrder.PutUint16(exifData[8:], 2)
+	putTestIfdEntry(exifData, byteOrder, 10, makeTagId, exifcommon.TypeAscii, 9)
+	byteOrder.PutUint32(exifData[18:], 38)
+	putTestIfdEntry(exifData, byteOrder, 22, exifcommon.IfdExifStandardIfdIdentity.TagId(), exifcommon.TypeLong, 1)
+	byteOrder.PutUint32(exifData[30:], 48)
+	byteOrder.PutUint32(exifData[34:], 0)
+	copy(exifData[38:], "TestMake\000")
+
+	byteOrder.PutUint16(exifData[48:], 1)
+	putTestIfdEntry(exifData, byteOrder, 50, MakerNoteTagId, exifcommon.TypeUndefined, 24)
+	byteOrder.PutUint32(exifData[58:], 66)
+	byteOrder.PutUint32(exifData[62:], 0)
+
+	byteOrder.PutUint16(exifData[66:], 1)
+	putTestIfdEntry(exifData, byteOrder, 68, 0x0001, exifcommon.TypeAscii, 6)
+	byteOrder.PutUint32(exifData[76:], 18)
+	byteOrder.PutUint32(exifData[80:], 0)
+	copy(exifData[84:], "hello\000")
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	_, index, err := Collect(im, ti, exifData)
+	log.PanicIf(err)
+
+	exifIfd := index.Lookup[exifcommon.IfdExifStandardIfdIdentity.String()]
+
+	makerNoteIfd := exifIfd.MakerNoteIfd()
+	if makerNoteIfd == nil {
+		t.Fatalf("MakerNote IFD not found.")
+	}
+
+	ite, found := makerNoteIfd.EntryByTagId(0x0001)
+	if found == false {
+		t.Fatalf("MakerNote tag not found.")
+	} else if ite.BaseOffset() != 66 {
+		t.Fatalf("Base offset not correct: (%d)", ite.BaseOffset())
+	}
+
+	value, err := ite.Value()
+	log.PanicIf(err)
+
+	if value.(string) != "hello" {
+		t.Fatalf("Value not correct: [%s]", value)
+	}
+
+	// The value-offset is still the one that was stored.
+	if ite.getValueOffset() != 18 {
+		t.Fatalf("Value-offset not correct: (%d)", ite.getValueOffset())
+	}
+}
@@ -0,0 +1,152 @@
+package exif
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/mschilli/go-exif/v3/common"
+)
+
+const (
+	// orientationTagId is the tag-ID of the Orientation tag in IFD0.
+	orientationTagId = 0x0112
+)
+
+var (
+	// ErrOrientationNotValid means that the Orientation tag has a value that
+	// is not one of the eight that are defined.
+	ErrOrientationNotValid = errors.New("orientation not valid")
+)
+
+// Orientation describes where the first row and column of the stored image
+// belong when it is displayed. The names are from the TIFF specification
+// (row, then column): e.g. RightTop means the first row is the right side of
+// the displayed image and the first column is its top.
+type Orientation uint16
+
+const (
+	// OrientationTopLeft is the normal orientation. This is the default when
+	// there is no Orientation tag.
+	OrientationTopLeft Orientation = 1
+
+	// OrientationTopRight is mirrored horizontally.
+	OrientationTopRight Orientation = 2
+
+	// OrientationBottomRight is rotated 180 degrees.
+	OrientationBottomRight Orientation = 3
+
+	// OrientationBottomLeft is mirrored vertically.
+	OrientationBottomLeft Orientation = 4
+
+	// OrientationLeftTop is mirrored horizontally and then rotated 90 degrees
+	// counter-clockwise (a transpose).
+	OrientationLeftTop Orientation = 5
+
+	// OrientationRightTop has to be rotated 90 degrees clockwise.
+	OrientationRightTop Orientation = 6
+
+	// OrientationRightBottom is mirrored horizontally and then rotated 90
+	// degrees clockwise (a transverse).
+	OrientationRightBottom Orientation = 7
+
+	// OrientationLeftBottom has to be rotated 90 degrees counter-clockwise.
+	OrientationLeftBottom Orientation = 8
+)
+
+const (
+	// OrientationRotateCW90 is an alias for OrientationRightTop.
+	OrientationRotateCW90 = OrientationRightTop
+
+	// OrientationRotate180 is an alias for OrientationBottomRight.
+	OrientationRotate180 = OrientationBottomRight
+
+	// OrientationRotateCCW90 is an alias for OrientationLeftBottom.
+	OrientationRotateCCW90 = OrientationLeftBottom
+)
+
+var (
+	orientationNames = map[Orientation]string{
+		OrientationTopLeft:     "TopLeft",
+		OrientationTopRight:    "TopRight",
+		OrientationBottomRight: "BottomRight",
+		OrientationBottomLeft:  "BottomLeft",
+		OrientationLeftTop:     "LeftTop",
+		OrientationRightTop:    "RightTop",
+		OrientationRightBottom: "RightBottom",
+		OrientationLeftBottom:  "LeftBottom",
+	}
+)
+
+// IsValid returns true if the orientation is one of the eight that are
+// defined.
+func (o Orientation) IsValid() bool {
+	return o >= OrientationTopLeft && o <= OrientationLeftBottom
+}
+
+// NeedsTranspose returns true if the width and height of the stored image
+// have to be swapped in order to display it (the four orientations that
+// involve a 90-degree rotation).
+func (o Orientation) NeedsTranspose() bool {
+	return o >= OrientationLeftTop && o <= OrientationLeftBottom
+}
+
+// String returns the name of the orientation.
+func (o Orientation) String() string {
+	name, found := orientationNames[o]
+	if found == false {
+		return fmt.Sprintf("Orientation<%d>", uint16(o))
+	}
+
+	return name
+}
+
+// Orientation returns the Orientation tag from IFD0. OrientationTopLeft is
+// returned if the tag is not present, per the specification. The returned
+// orientation will always be valid unless ErrOrientationNotValid is returned
+// (unwrapped), in which case the value is returned as it was stored.
+func (index IfdIndex) Orientation() (o Orientation, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	ifdPath := exifcommon.IfdStandardIfdIdentity.String()
+
+	ite, _, err := index.FindTag(ifdPath, orientationTagId)
+	if err != nil {
+		if err == ErrTagNotFound {
+			return OrientationTopLeft, nil
+		}
+
+		log.Panic(err)
+	}
+
+	valueRaw, err := ite.Value()
+	log.PanicIf(err)
+
+	values, ok := valueRaw.([]uint16)
+	if ok == false || len(values) != 1 {
+		log.Panicf("orientation tag is not a single SHORT: [%s] (%d)", ite.TagType(), ite.UnitCount())
+	}
+
+	o = Orientation(values[0])
+	if o.IsValid() == false {
+		return o, ErrOrientationNotValid
+	}
+
+	return o, nil
+}
+
+// Orientation returns the Orientation tag from IFD0. Only the root chain is
+// parsed. See `IfdIndex.Orientation()`.
+func (ie *IfdEnumerate) Orientation() (o Orientation, err error) {
+	index, err := ie.getHeaderIndex(true)
+	if err != nil {
+		return o, err
+	}
+
+	return index.Orientation()
+}
@@ -0,0 +1,112 @@
+package exif
+
+import (
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/mschilli/go-exif/v3/common"
+)
+
+// getTestOrientationExifData returns an EXIF blob whose IFD0 has the given
+// Orientation or no Orientation tag at all if it is zero.
+func getTestOrientationExifData(orientation uint16) []byte {
+	var tags []testBuilderTag
+	if orientation != 0 {
+		tags = []testBuilderTag{
+			{tagId: orientationTagId, value: []uint16{orientation}},
+		}
+	}
+
+	return getTestBuilderExifData(exifcommon.TestDefaultByteOrder, tags)
+}
+
+func TestOrientation_NeedsTranspose(t *testing.T) {
+	for o := OrientationTopLeft; o <= OrientationLeftBottom; o++ {
+		expected := o >= 5
+		if o.NeedsTranspose() != expected {
+			t.Fatalf("NeedsTranspose() not correct for [%s]: %v", o, o.NeedsTranspose())
+		}
+	}
+}
+
+func TestOrientation_String(t *testing.T) {
+	if OrientationRotateCW90.String() != "RightTop" {
+		t.Fatalf("String() not correct: [%s]", OrientationRotateCW90.String())
+	} else if Orientation(9).String() != "Orientation<9>" {
+		t.Fatalf("String() not correct for an invalid orientation: [%s]", Orientation(9).String())
+	}
+}
+
+func TestIfdEnumerate_Orientation(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, _, err := NewIfdEnumerateWithBytes(im, ti, getTestOrientationExifData(6))
+	log.PanicIf(err)
+
+	o, err := ie.Orientation()
+	log.PanicIf(err)
+
+	if o != OrientationRotateCW90 {
+		t.Fatalf("Orientation not correct: [%s]", o)
+	} else if o.NeedsTranspose() != true {
+		t.Fatalf("Expected transpose.")
+	}
+}
+
+func TestIfdEnumerate_Orientation__Missing(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, _, err := NewIfdEnumerateWithBytes(im, ti, getTestOrientationExifData(0))
+	log.PanicIf(err)
+
+	o, err := ie.Orientation()
+	log.PanicIf(err)
+
+	if o != OrientationTopLeft {
+		t.Fatalf("Expected the default orientation: [%s]", o)
+	}
+}
+
+func TestIfdEnumerate_Orientation__NotValid(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, _, err := NewIfdEnumerateWithBytes(im, ti, getTestOrientationExifData(9))
+	log.PanicIf(err)
+
+	o, err := ie.Orientation()
+	if err != ErrOrientationNotValid {
+		t.Fatalf("Expected ErrOrientationNotValid: %v", err)
+	} else if o != 9 {
+		t.Fatalf("Expected the stored value to be returned: (%d)", o)
+	}
+}
+
+func TestIfdIndex_Orientation(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, eh, err := NewIfdEnumerateWithBytes(im, ti, getTestExifData())
+	log.PanicIf(err)
+
+	index, err := ie.Collect(eh.FirstIfdOffset)
+	log.PanicIf(err)
+
+	o, err := index.Orientation()
+	log.PanicIf(err)
+
+	if o != OrientationTopLeft {
+		t.Fatalf("Orientation not correct: [%s]", o)
+	}
+}
@@ -0,0 +1,93 @@
+package exif
+
+import (
+	"github.com/dsoprea/go-logging"
+
+	"github.com/mschilli/go-exif/v3/common"
+)
+
+const (
+	pixelXDimensionTagId = 0xa002
+	pixelYDimensionTagId = 0xa003
+)
+
+// PixelDimensions returns the PixelXDimension and PixelYDimension tags from
+// the Exif IFD, which are the dimensions of the (compressed) image. Writers
+// store them as either SHORTs or LONGs. ErrTagNotFound is returned
+// (unwrapped) if either is missing.
+func (index IfdIndex) PixelDimensions() (width, height uint32, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	ifdPath := exifcommon.IfdExifStandardIfdIdentity.String()
+
+	width, err = index.findDimension(ifdPath, pixelXDimensionTagId)
+	if err != nil {
+		if err == ErrTagNotFound {
+			return 0, 0, err
+		}
+
+		log.Panic(err)
+	}
+
+	height, err = index.findDimension(ifdPath, pixelYDimensionTagId)
+	if err != nil {
+		if err == ErrTagNotFound {
+			return 0, 0, err
+		}
+
+		log.Panic(err)
+	}
+
+	return width, height, nil
+}
+
+// findDimension returns the value of a dimension tag, whether it was stored as
+// a SHORT or as a LONG.
+func (index IfdIndex) findDimension(fqIfdPath string, tagId uint16) (dimension uint32, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	ite, _, err := index.FindTag(fqIfdPath, tagId)
+	if err != nil {
+		if err == ErrTagNotFound {
+			return 0, err
+		}
+
+		log.Panic(err)
+	}
+
+	valueRaw, err := ite.Value()
+	log.PanicIf(err)
+
+	switch values := valueRaw.(type) {
+	case []uint16:
+		if len(values) == 1 {
+			return uint32(values[0]), nil
+		}
+	case []uint32:
+		if len(values) == 1 {
+			return values[0], nil
+		}
+	}
+
+	log.Panicf("dimension tag (0x%04x) is not a single SHORT or LONG: [%s] (%d)", tagId, ite.TagType(), ite.UnitCount())
+	return 0, nil
+}
+
+// PixelDimensions returns the PixelXDimension and PixelYDimension tags from
+// the Exif IFD. See `IfdIndex.PixelDimensions()`.
+func (ie *IfdEnumerate) PixelDimensions() (width, height uint32, err error) {
+	index, err := ie.getHeaderIndex(false)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return index.PixelDimensions()
+}
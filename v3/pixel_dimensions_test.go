@@ -0,0 +1,72 @@
+package exif
+
+import (
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/mschilli/go-exif/v3/common"
+)
+
+// getTestPixelDimensionsExifData returns EXIF data whose Exif IFD has the
+// width as a LONG and the height as a SHORT.
+func getTestPixelDimensionsExifData() []byte {
+	exifIfd := testBuilderIfd{
+		ii: exifcommon.IfdExifStandardIfdIdentity,
+		tags: []testBuilderTag{
+			{tagId: pixelXDimensionTagId, value: []uint32{70000}},
+			{tagId: pixelYDimensionTagId, value: []uint16{4000}, tagType: exifcommon.TypeShort},
+		},
+	}
+
+	return getTestBuilderExifData(exifcommon.TestDefaultByteOrder, nil, exifIfd)
+}
+
+func TestIfdEnumerate_PixelDimensions(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, _, err := NewIfdEnumerateWithBytes(im, ti, getTestExifData())
+	log.PanicIf(err)
+
+	width, height, err := ie.PixelDimensions()
+	log.PanicIf(err)
+
+	if width != 3840 || height != 2560 {
+		t.Fatalf("Dimensions not correct: (%d) x (%d)", width, height)
+	}
+}
+
+func TestIfdEnumerate_PixelDimensions__LongAndShort(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, _, err := NewIfdEnumerateWithBytes(im, ti, getTestPixelDimensionsExifData())
+	log.PanicIf(err)
+
+	width, height, err := ie.PixelDimensions()
+	log.PanicIf(err)
+
+	if width != 70000 || height != 4000 {
+		t.Fatalf("Dimensions not correct: (%d) x (%d)", width, height)
+	}
+}
+
+func TestIfdEnumerate_PixelDimensions__Missing(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, _, err := NewIfdEnumerateWithBytes(im, ti, getTestOrientationExifData(1))
+	log.PanicIf(err)
+
+	_, _, err = ie.PixelDimensions()
+	if err != ErrTagNotFound {
+		t.Fatalf("Expected ErrTagNotFound: %v", err)
+	}
+}
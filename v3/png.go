@@ -0,0 +1,105 @@
+package exif
+
+import (
+	"bytes"
+	"errors"
+
+	"encoding/binary"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/mschilli/go-exif/v3/common"
+)
+
+var (
+	pngLogger = newPackageLogger("exif.png")
+)
+
+var (
+	// ErrNotPng means that the data does not start with the PNG signature.
+	ErrNotPng = errors.New("not a PNG")
+)
+
+var (
+	// PngSignature is the eight bytes at the front of every PNG file.
+	PngSignature = [8]byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1a, '\n'}
+
+	pngExifChunkType = [4]byte{'e', 'X', 'I', 'f'}
+	pngEndChunkType  = [4]byte{'I', 'E', 'N', 'D'}
+)
+
+// SearchPngAndExtractExif returns the EXIF data from the eXIf chunk of a PNG.
+// Unlike in JPEGs, the chunk payload is the raw TIFF stream with no "Exif"
+// prefix. ErrNotPng is returned (unwrapped) if the data is not a PNG and
+// ErrNoExif if there is no eXIf chunk.
+func SearchPngAndExtractExif(pngData []byte) (rawExif []byte, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	if len(pngData) < len(PngSignature) || bytes.Equal(pngData[:len(PngSignature)], PngSignature[:]) == false {
+		return nil, ErrNotPng
+	}
+
+	// Each chunk is a four-byte length, a four-byte type, the data, and a
+	// four-byte CRC.
+
+	offset := len(PngSignature)
+	for offset+8 <= len(pngData) {
+		length := int(binary.BigEndian.Uint32(pngData[offset : offset+4]))
+
+		var chunkType [4]byte
+		copy(chunkType[:], pngData[offset+4:offset+8])
+
+		dataOffset := offset + 8
+		if length < 0 || dataOffset+length+4 > len(pngData) {
+			pngLogger.Warningf(nil, "PNG chunk [%s] at offset (%d) runs past the end of the data.", string(chunkType[:]), offset)
+			break
+		}
+
+		if chunkType == pngExifChunkType {
+			return pngData[dataOffset : dataOffset+length], nil
+		} else if chunkType == pngEndChunkType {
+			break
+		}
+
+		offset = dataOffset + length + 4
+	}
+
+	return nil, ErrNoExif
+}
+
+// NewIfdEnumerateFromPng returns an IfdEnumerate for the EXIF data in the
+// eXIf chunk of the given PNG. The byte-order is taken from the TIFF header
+// at the front of the chunk, and the header is returned in order to provide
+// the offset of the first IFD. ErrNotPng or ErrNoExif is returned (unwrapped)
+// if there is no usable EXIF.
+func NewIfdEnumerateFromPng(ifdMapping *exifcommon.IfdMapping, tagIndex *TagIndex, pngData []byte) (ie *IfdEnumerate, eh ExifHeader, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	rawExif, err := SearchPngAndExtractExif(pngData)
+	if err != nil {
+		if err == ErrNotPng || err == ErrNoExif {
+			return nil, eh, err
+		}
+
+		log.Panic(err)
+	}
+
+	ie, eh, err = NewIfdEnumerateWithBytes(ifdMapping, tagIndex, rawExif)
+	if err != nil {
+		if err == ErrNoExif {
+			return nil, eh, err
+		}
+
+		log.Panic(err)
+	}
+
+	return ie, eh, nil
+}
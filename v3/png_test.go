@@ -0,0 +1,107 @@
+package exif
+
+import (
+	"bytes"
+	"testing"
+
+	"encoding/binary"
+	"hash/crc32"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/mschilli/go-exif/v3/common"
+)
+
+func getTestPngData(chunks ...[]interface{}) []byte {
+	b := new(bytes.Buffer)
+	b.Write(PngSignature[:])
+
+	for _, chunk := range chunks {
+		chunkType := chunk[0].(string)
+		data := chunk[1].([]byte)
+
+		err := binary.Write(b, binary.BigEndian, uint32(len(data)))
+		log.PanicIf(err)
+
+		b.WriteString(chunkType)
+		b.Write(data)
+
+		crc := crc32.ChecksumIEEE(append([]byte(chunkType), data...))
+
+		err = binary.Write(b, binary.BigEndian, crc)
+		log.PanicIf(err)
+	}
+
+	return b.Bytes()
+}
+
+func TestSearchPngAndExtractExif(t *testing.T) {
+	exifData := getTestExifData()
+
+	pngData := getTestPngData(
+		[]interface{}{"IHDR", make([]byte, 13)},
+		[]interface{}{"eXIf", exifData},
+		[]interface{}{"IEND", []byte{}})
+
+	rawExif, err := SearchPngAndExtractExif(pngData)
+	log.PanicIf(err)
+
+	if bytes.Equal(rawExif, exifData) != true {
+		t.Fatalf("EXIF data not correct.")
+	}
+}
+
+func TestSearchPngAndExtractExif__NoExif(t *testing.T) {
+	pngData := getTestPngData(
+		[]interface{}{"IHDR", make([]byte, 13)},
+		[]interface{}{"IEND", []byte{}})
+
+	_, err := SearchPngAndExtractExif(pngData)
+	if err != ErrNoExif {
+		t.Fatalf("Expected no-EXIF error: %v", err)
+	}
+}
+
+func TestSearchPngAndExtractExif__NotPng(t *testing.T) {
+	_, err := SearchPngAndExtractExif(getTestExifData())
+	if err != ErrNotPng {
+		t.Fatalf("Expected not-PNG error: %v", err)
+	}
+}
+
+func TestSearchPngAndExtractExif__Truncated(t *testing.T) {
+	pngData := getTestPngData(
+		[]interface{}{"IHDR", make([]byte, 13)},
+		[]interface{}{"eXIf", getTestExifData()})
+
+	_, err := SearchPngAndExtractExif(pngData[:len(pngData)-10])
+	if err != ErrNoExif {
+		t.Fatalf("Expected no-EXIF error: %v", err)
+	}
+}
+
+func TestNewIfdEnumerateFromPng(t *testing.T) {
+	pngData := getTestPngData(
+		[]interface{}{"IHDR", make([]byte, 13)},
+		[]interface{}{"eXIf", getTestExifData()},
+		[]interface{}{"IEND", []byte{}})
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, eh, err := NewIfdEnumerateFromPng(im, ti, pngData)
+	log.PanicIf(err)
+
+	if eh.ByteOrder != binary.LittleEndian {
+		t.Fatalf("Byte-order not correct.")
+	}
+
+	index, err := ie.Collect(eh.FirstIfdOffset)
+	log.PanicIf(err)
+
+	if len(index.Ifds) != 5 {
+		t.Fatalf("IFD count not correct: (%d)", len(index.Ifds))
+	}
+}
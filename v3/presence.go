@@ -0,0 +1,42 @@
+package exif
+
+import (
+	"github.com/dsoprea/go-logging"
+)
+
+// HasTag returns true if the IFD with the given fully-qualified path (e.g.
+// "IFD/Exif") has the given tag. The value is not decoded. The tree is
+// collected the first time and is reused after that, so checking many tags is
+// cheap.
+func (ie *IfdEnumerate) HasTag(ifdName string, tagId uint16) (found bool, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	index, err := ie.getHeaderIndex(false)
+	log.PanicIf(err)
+
+	ifd, found := index.Lookup[ifdName]
+	if found == false {
+		return false, nil
+	}
+
+	_, found = ifd.EntryByTagId(tagId)
+	return found, nil
+}
+
+// HasIfd returns true if there is an IFD with the given fully-qualified path
+// (e.g. "IFD/GPSInfo"). If the tree can't be collected, the error is logged
+// and false is returned. See HasTag().
+func (ie *IfdEnumerate) HasIfd(ifdName string) bool {
+	index, err := ie.getHeaderIndex(false)
+	if err != nil {
+		ifdEnumerateLogger.Warningf(nil, "Could not collect the IFDs in order to look for IFD [%s]: %v", ifdName, err)
+		return false
+	}
+
+	_, found := index.Lookup[ifdName]
+	return found
+}
@@ -0,0 +1,85 @@
+package exif
+
+import (
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/mschilli/go-exif/v3/common"
+)
+
+func TestIfdEnumerate_HasTag(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, _, err := NewIfdEnumerateWithBytes(im, ti, getTestExifData())
+	log.PanicIf(err)
+
+	found, err := ie.HasTag("IFD/Exif", exposureTimeTagId)
+	log.PanicIf(err)
+
+	if found != true {
+		t.Fatalf("Expected ExposureTime to be found.")
+	}
+
+	found, err = ie.HasTag("IFD", 0x8298)
+	log.PanicIf(err)
+
+	if found != true {
+		t.Fatalf("Expected Copyright to be found.")
+	}
+
+	found, err = ie.HasTag("IFD", exposureTimeTagId)
+	log.PanicIf(err)
+
+	if found != false {
+		t.Fatalf("Expected ExposureTime to not be found in IFD0.")
+	}
+
+	found, err = ie.HasTag("IFD/Invalid", exposureTimeTagId)
+	log.PanicIf(err)
+
+	if found != false {
+		t.Fatalf("Expected nothing to be found in an IFD that doesn't exist.")
+	}
+
+	if ie.headerIndex == nil {
+		t.Fatalf("Expected the tree to be kept.")
+	}
+
+	headerIndex := ie.headerIndex
+
+	_, err = ie.HasTag("IFD", 0x8298)
+	log.PanicIf(err)
+
+	if ie.headerIndex != headerIndex {
+		t.Fatalf("Expected the tree to be reused.")
+	}
+}
+
+func TestIfdEnumerate_HasIfd(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, _, err := NewIfdEnumerateWithBytes(im, ti, getTestExifData())
+	log.PanicIf(err)
+
+	if ie.HasIfd("IFD/GPSInfo") != true {
+		t.Fatalf("Expected GPS IFD to be found.")
+	} else if ie.HasIfd("IFD1") != true {
+		t.Fatalf("Expected IFD1 to be found.")
+	}
+
+	ie, _, err = NewIfdEnumerateWithBytes(im, ti, getTestOrientationExifData(1))
+	log.PanicIf(err)
+
+	if ie.HasIfd("IFD/GPSInfo") != false {
+		t.Fatalf("Expected GPS IFD to not be found.")
+	} else if ie.HasIfd("IFD") != true {
+		t.Fatalf("Expected root IFD to be found.")
+	}
+}
@@ -0,0 +1,86 @@
+package exif
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/mschilli/go-exif/v3/common"
+	"github.com/mschilli/go-exif/v3/undefined"
+)
+
+// readAllWithBytes runs the read paths that look at all of the data (values,
+// thumbnails, offsets) against the given EXIF data.
+func readAllWithBytes(exifData []byte) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, eh, err := NewIfdEnumerateWithBytes(im, ti, exifData)
+	log.PanicIf(err)
+
+	co := &CollectOptions{
+		CaptureValues: true,
+	}
+
+	index, err := ie.CollectWithOptions(eh.FirstIfdOffset, co)
+	log.PanicIf(err)
+
+	for _, ifd := range index.Ifds {
+		for _, ite := range ifd.Entries() {
+			_, err := ite.GetRawBytes()
+			if err != nil && err != exifundefined.ErrUnparseableValue {
+				log.Panic(err)
+			}
+		}
+	}
+
+	_, err = ie.ToMap()
+	log.PanicIf(err)
+
+	_, err = ie.ThumbnailBytes()
+	log.PanicIf(err)
+
+	_, err = ie.ValidateOffsets(index.RootIfd)
+	log.PanicIf(err)
+
+	_, err = ie.Fingerprint(index.RootIfd)
+	log.PanicIf(err)
+
+	_, err = ie.Root()
+	log.PanicIf(err)
+}
+
+func TestNewIfdEnumerateWithBytes__NotMutated(t *testing.T) {
+	exifData := make([]byte, len(getTestExifData()))
+	copy(exifData, getTestExifData())
+
+	readAllWithBytes(exifData)
+
+	if bytes.Equal(exifData, getTestExifData()) != true {
+		t.Fatalf("EXIF data was changed by reading it.")
+	}
+}
+
+func TestNewExifReadSeekerWithBytes__NotCopied(t *testing.T) {
+	exifData := []byte{1, 2, 3, 4}
+
+	ers := NewExifReadSeekerWithBytes(exifData)
+
+	// We read the caller's data in place.
+	exifData[0] = 5
+
+	rs, err := ers.GetReadSeeker(0)
+	log.PanicIf(err)
+
+	b := make([]byte, 1)
+
+	_, err = rs.Read(b)
+	log.PanicIf(err)
+
+	if b[0] != 5 {
+		t.Fatalf("Data was copied: (%d)", b[0])
+	}
+}
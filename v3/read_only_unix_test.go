@@ -0,0 +1,44 @@
+//go:build linux || darwin
+// +build linux darwin
+
+package exif
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"syscall"
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/mschilli/go-exif/v3/common"
+)
+
+func TestNewIfdEnumerateWithBytes__ReadOnlyMmap(t *testing.T) {
+	assetsPath := exifcommon.GetTestAssetsPath()
+	filepath := path.Join(assetsPath, "NDM_8901.jpg.exif")
+
+	f, err := os.Open(filepath)
+	log.PanicIf(err)
+
+	defer f.Close()
+
+	fi, err := f.Stat()
+	log.PanicIf(err)
+
+	// Any write into this memory faults.
+	exifData, err := syscall.Mmap(int(f.Fd()), 0, int(fi.Size()), syscall.PROT_READ, syscall.MAP_SHARED)
+	log.PanicIf(err)
+
+	defer syscall.Munmap(exifData)
+
+	readAllWithBytes(exifData)
+
+	original, err := ioutil.ReadFile(filepath)
+	log.PanicIf(err)
+
+	if string(exifData) != string(original) {
+		t.Fatalf("Mapped data does not match the file.")
+	}
+}
@@ -0,0 +1,152 @@
+package exif
+
+import (
+	"errors"
+	"math"
+
+	"io/ioutil"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/mschilli/go-exif/v3/common"
+)
+
+var (
+	// ErrRelayoutRequired means that the changes to the tree can't be made
+	// to the original EXIF data in place. The EXIF has to be rebuilt instead
+	// (e.g. with NewIfdBuilderFromExistingChain() and an IfdByteEncoder).
+	ErrRelayoutRequired = errors.New("changes require the EXIF to be laid out again")
+)
+
+// Rewrite returns a copy of the EXIF data with the values that were changed
+// in the given tree (see `IfdTagEntry.SetValue()`) written back to it. The
+// tree must have been collected from this enumerator's data. Everything else
+// is copied byte for byte, including the IFDs and values that we don't
+// understand (see `CollectOptions.KeepUnknownIfds`), so only the entries of
+// the changed tags and the space for their values differ:
+//
+//   - A value of four bytes or fewer is stored in the entry itself.
+//   - A value that fits in the space that the original value had is written
+//     there and the rest of that space is zeroed.
+//   - Any other value is appended to the end of the data (on a word boundary)
+//     and the space that the original value had is zeroed.
+//
+// The IFDs themselves are never moved. ErrRelayoutRequired is returned
+// (unwrapped) if a changed tag holds offsets (a child-IFD pointer, SubIFDs,
+// or the thumbnail's offset or size), if the tree was merged from several
+// segments or is from a BigTIFF, or if an appended value would be beyond the
+// reach of a 32-bit offset. The data is returned unchanged if nothing was changed.
+func (ie *IfdEnumerate) Rewrite(modified *Ifd) (exifData []byte, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	rs, err := ie.ebs.GetReadSeeker(0)
+	log.PanicIf(err)
+
+	exifData, err = ioutil.ReadAll(rs)
+	log.PanicIf(err)
+
+	byteOrder := ie.byteOrder
+
+	visitor := func(ifd *Ifd, depth int) error {
+		for _, ite := range ifd.entries {
+			if ite.IsModified() == false {
+				continue
+			}
+
+			if ite.getChildIfdIdentity() != nil || ite.TagId() == SubIfdsTagId || ite.IsThumbnailOffset() == true || ite.IsThumbnailSize() == true {
+				return ErrRelayoutRequired
+			} else if ite.Segment() != 0 || ite.isBigTiff == true {
+				return ErrRelayoutRequired
+			}
+
+			entryOffset := int64(ExifAddressableAreaStart) + int64(ifd.offset) + 2 + int64(ite.tagIndex)*int64(IfdTagEntrySize)
+			if entryOffset+int64(IfdTagEntrySize) > int64(len(exifData)) {
+				log.Panicf("entry for tag (0x%04x) in IFD [%s] is past the end of the EXIF data", ite.TagId(), ifd.ifdIdentity)
+			}
+
+			entry := exifData[entryOffset : entryOffset+int64(IfdTagEntrySize)]
+
+			if byteOrder.Uint16(entry[0:]) != ite.TagId() {
+				log.Panicf("entry for tag (0x%04x) in IFD [%s] isn't where it was found: the tree wasn't collected from this data", ite.TagId(), ifd.ifdIdentity)
+			}
+
+			// Find the space that the original value had, if it wasn't in the
+			// entry itself and it's within the data.
+
+			originalType := exifcommon.TagTypePrimitive(byteOrder.Uint16(entry[2:]))
+
+			var originalOffset, originalLength int64
+			if originalType.IsValid() == true {
+				originalLength = int64(originalType.Size()) * int64(byteOrder.Uint32(entry[4:]))
+				originalOffset = int64(ExifAddressableAreaStart) + int64(byteOrder.Uint32(entry[8:]))
+
+				if originalLength <= 4 || originalOffset+originalLength > int64(len(exifData)) {
+					originalOffset = 0
+					originalLength = 0
+				}
+			}
+
+			value := ite.modifiedValue
+			valueLength := int64(len(value))
+
+			valueField := make([]byte, 4)
+
+			if valueLength <= 4 {
+				copy(valueField, value)
+				zeroBytes(exifData[originalOffset : originalOffset+originalLength])
+			} else if valueLength <= originalLength {
+				n := copy(exifData[originalOffset:originalOffset+originalLength], value)
+				zeroBytes(exifData[originalOffset+int64(n) : originalOffset+originalLength])
+
+				byteOrder.PutUint32(valueField, uint32(originalOffset-int64(ExifAddressableAreaStart)))
+			} else {
+				zeroBytes(exifData[originalOffset : originalOffset+originalLength])
+
+				if len(exifData)%2 != 0 {
+					exifData = append(exifData, 0)
+				}
+
+				valueOffset := int64(len(exifData)) - int64(ExifAddressableAreaStart)
+				if valueOffset+valueLength > math.MaxUint32 {
+					return ErrRelayoutRequired
+				}
+
+				exifData = append(exifData, value...)
+
+				// The entry has to be found again since the data might have
+				// been moved.
+				entry = exifData[entryOffset : entryOffset+int64(IfdTagEntrySize)]
+
+				byteOrder.PutUint32(valueField, uint32(valueOffset))
+			}
+
+			byteOrder.PutUint16(entry[2:], uint16(ite.TagType()))
+			byteOrder.PutUint32(entry[4:], ite.UnitCount())
+			copy(entry[8:], valueField)
+		}
+
+		return nil
+	}
+
+	err = modified.Walk(visitor)
+	if err != nil {
+		if err == ErrRelayoutRequired {
+			return nil, err
+		}
+
+		log.Panic(err)
+	}
+
+	return exifData, nil
+}
+
+// zeroBytes sets every byte in the slice to zero.
+func zeroBytes(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
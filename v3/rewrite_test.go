@@ -0,0 +1,191 @@
+package exif
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/mschilli/go-exif/v3/common"
+)
+
+// rewriteTestModel sets the Model tag in a tree collected from the test EXIF
+// and returns the rewritten data along with the original.
+func rewriteTestModel(model string) (original, exifData []byte, err error) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	testExifData := getTestExifData()
+	original = make([]byte, len(testExifData))
+	copy(original, testExifData)
+
+	ie, eh, err := NewIfdEnumerateWithBytes(im, ti, original)
+	log.PanicIf(err)
+
+	index, err := ie.Collect(eh.FirstIfdOffset)
+	log.PanicIf(err)
+
+	ite, _, err := index.FindTag(exifcommon.IfdStandardIfdIdentity.String(), 0x0110)
+	log.PanicIf(err)
+
+	err = ite.SetValue(model)
+	log.PanicIf(err)
+
+	exifData, err = ie.Rewrite(index.RootIfd)
+	return original, exifData, err
+}
+
+// rewriteTestCheck checks the Model tag in the rewritten data and that the
+// rest of the tags weren't disturbed.
+func rewriteTestCheck(t *testing.T, exifData []byte, model string) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	_, index, err := Collect(im, ti, exifData)
+	log.PanicIf(err)
+
+	ite, _, err := index.FindTag(exifcommon.IfdStandardIfdIdentity.String(), 0x0110)
+	log.PanicIf(err)
+
+	value, err := ite.Value()
+	log.PanicIf(err)
+
+	if value.(string) != model {
+		t.Fatalf("Model not correct: [%s] != [%s]", value, model)
+	}
+
+	ite, _, err = index.FindTag(exifcommon.IfdStandardIfdIdentity.String(), 0x010f)
+	log.PanicIf(err)
+
+	value, err = ite.Value()
+	log.PanicIf(err)
+
+	if value.(string) != "Canon" {
+		t.Fatalf("Make not correct: [%s]", value)
+	}
+
+	dateTimeOriginal, err := index.DateTimeOriginal()
+	log.PanicIf(err)
+
+	if dateTimeOriginal.Format(ExifDateTimeLayout) != "2017:12:02 08:18:50" {
+		t.Fatalf("DateTimeOriginal not correct: [%s]", dateTimeOriginal)
+	}
+}
+
+func TestIfdEnumerate_Rewrite__InPlace(t *testing.T) {
+	original, exifData, err := rewriteTestModel("Canon EOS R")
+	log.PanicIf(err)
+
+	if len(exifData) != len(original) {
+		t.Fatalf("EXIF length changed: (%d) != (%d)", len(exifData), len(original))
+	} else if bytes.Equal(original, getTestExifData()) == false {
+		t.Fatalf("Original EXIF data was modified.")
+	}
+
+	rewriteTestCheck(t, exifData, "Canon EOS R")
+
+	// Only the entry and the value should differ.
+
+	eh, err := ParseExifHeader(original)
+	log.PanicIf(err)
+
+	entryOffset := int(eh.FirstIfdOffset) + 2 + int(IfdTagEntrySize)
+	valueOffset := int(eh.ByteOrder.Uint32(original[entryOffset+8:]))
+
+	for i := range original {
+		if original[i] == exifData[i] {
+			continue
+		}
+
+		if (i < entryOffset || i >= entryOffset+int(IfdTagEntrySize)) && (i < valueOffset || i >= valueOffset+22) {
+			t.Fatalf("Byte (%d) changed outside of the Model tag.", i)
+		}
+	}
+}
+
+func TestIfdEnumerate_Rewrite__Embedded(t *testing.T) {
+	original, exifData, err := rewriteTestModel("EOS")
+	log.PanicIf(err)
+
+	if len(exifData) != len(original) {
+		t.Fatalf("EXIF length changed: (%d) != (%d)", len(exifData), len(original))
+	}
+
+	rewriteTestCheck(t, exifData, "EOS")
+
+	// The original space is zeroed.
+
+	eh, err := ParseExifHeader(original)
+	log.PanicIf(err)
+
+	entryOffset := int(eh.FirstIfdOffset) + 2 + int(IfdTagEntrySize)
+	valueOffset := int(eh.ByteOrder.Uint32(original[entryOffset+8:]))
+
+	if bytes.Equal(exifData[valueOffset:valueOffset+22], make([]byte, 22)) == false {
+		t.Fatalf("Original space not zeroed: %v", exifData[valueOffset:valueOffset+22])
+	}
+}
+
+func TestIfdEnumerate_Rewrite__Relocated(t *testing.T) {
+	model := "Canon EOS 5D Mark III (with a much longer name)"
+
+	original, exifData, err := rewriteTestModel(model)
+	log.PanicIf(err)
+
+	expectedLength := len(original) + len(original)%2 + len(model) + 1
+	if len(exifData) != expectedLength {
+		t.Fatalf("EXIF length not correct: (%d) != (%d)", len(exifData), expectedLength)
+	} else if bytes.Equal(exifData[len(exifData)-len(model)-1:], append([]byte(model), 0)) == false {
+		t.Fatalf("Value not appended.")
+	}
+
+	rewriteTestCheck(t, exifData, model)
+}
+
+func TestIfdEnumerate_Rewrite__Unchanged(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, eh, err := NewIfdEnumerateWithBytes(im, ti, getTestExifData())
+	log.PanicIf(err)
+
+	index, err := ie.Collect(eh.FirstIfdOffset)
+	log.PanicIf(err)
+
+	exifData, err := ie.Rewrite(index.RootIfd)
+	log.PanicIf(err)
+
+	if bytes.Equal(exifData, getTestExifData()) == false {
+		t.Fatalf("EXIF data changed.")
+	}
+}
+
+func TestIfdEnumerate_Rewrite__RelayoutRequired(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, eh, err := NewIfdEnumerateWithBytes(im, ti, getTestExifData())
+	log.PanicIf(err)
+
+	index, err := ie.Collect(eh.FirstIfdOffset)
+	log.PanicIf(err)
+
+	ite, _, err := index.FindTag(exifcommon.IfdStandardIfdIdentity.String(), exifcommon.IfdExifStandardIfdIdentity.TagId())
+	log.PanicIf(err)
+
+	err = ite.SetValue([]uint32{0x1234})
+	log.PanicIf(err)
+
+	_, err = ie.Rewrite(index.RootIfd)
+	if err != ErrRelayoutRequired {
+		t.Fatalf("Expected relayout error: %v", err)
+	}
+}
@@ -0,0 +1,203 @@
+package exif
+
+import (
+	"github.com/dsoprea/go-logging"
+
+	"github.com/mschilli/go-exif/v3/common"
+)
+
+var (
+	segmentsLogger = newPackageLogger("exif.segments")
+)
+
+// CollectSegments collects each of the given EXIF blobs and merges them into
+// one tree. This is for images that have more than one EXIF segment (e.g.
+// several APP1 segments in a JPEG that was edited). Every blob must start
+// with its own TIFF header, so each one has its own byte-order and offsets.
+//
+// The first blob is the base. For every IFD in a later blob, the IFD at the
+// same path in the tree receives its tags: a tag that is already there is
+// replaced (the later blob wins) and any other tag is appended. IFDs, chain
+// links, thumbnails, and MakerNote IFDs that the tree doesn't have yet are
+// attached from the later blob as they are. `Ifd.Segment()` and
+// `IfdTagEntry.Segment()` tell which blob each IFD and tag came from. Tag
+// values are still read from their own blobs, though this means that the
+// offsets in a merged IFD are not all relative to the same blob.
+//
+// Blobs that don't have a TIFF header are skipped. ErrNoExif is returned
+// (unwrapped) if there isn't at least one that does.
+func CollectSegments(ifdMapping *exifcommon.IfdMapping, tagIndex *TagIndex, segments [][]byte) (index IfdIndex, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	var rootIfd *Ifd
+	skippedIfds := make([]SkippedIfd, 0)
+
+	for i, exifData := range segments {
+		ie, eh, err := NewIfdEnumerateWithBytes(ifdMapping, tagIndex, exifData)
+		if err != nil {
+			if err == ErrNoExif {
+				segmentsLogger.Warningf(nil, "Segment (%d) does not have EXIF data. Skipping.", i)
+				continue
+			}
+
+			log.Panic(err)
+		}
+
+		segmentIndex, err := ie.Collect(eh.FirstIfdOffset)
+		log.PanicIf(err)
+
+		for _, ifd := range segmentIndex.Ifds {
+			ifd.segment = i
+
+			for _, ite := range ifd.entries {
+				ite.segment = i
+			}
+		}
+
+		skippedIfds = append(skippedIfds, segmentIndex.SkippedIfds...)
+
+		if rootIfd == nil {
+			rootIfd = segmentIndex.RootIfd
+			continue
+		}
+
+		mergeIfd(rootIfd, segmentIndex.RootIfd, make(map[*Ifd]struct{}))
+	}
+
+	if rootIfd == nil {
+		return index, ErrNoExif
+	}
+
+	index = newIfdIndexFromTree(rootIfd)
+	index.SkippedIfds = skippedIfds
+
+	return index, nil
+}
+
+// mergeIfd merges the tags, children, thumbnail, and chain of `src` into
+// `dst`. `merged` has the IFDs from `src` that have already been merged (an
+// IFD can be shared by more than one parent).
+func mergeIfd(dst, src *Ifd, merged map[*Ifd]struct{}) {
+	if _, found := merged[src]; found == true {
+		return
+	}
+
+	merged[src] = struct{}{}
+
+	replaced := make(map[uint16]struct{})
+
+	for _, ite := range src.entries {
+		tagId := ite.tagId
+
+		existing := dst.entriesByTagId[tagId]
+
+		if len(existing) == 0 {
+			dst.entries = append(dst.entries, ite)
+			dst.entriesByTagId[tagId] = append(dst.entriesByTagId[tagId], ite)
+
+			continue
+		} else if ite.ChildIfdPath() != "" {
+			// The IFD that it points to is merged below. The offset is only
+			// meaningful in its own blob, so keep the tag that we have.
+			continue
+		} else if _, found := replaced[tagId]; found == true {
+			// The later blob has the tag more than once.
+			dst.entries = append(dst.entries, ite)
+			dst.entriesByTagId[tagId] = append(dst.entriesByTagId[tagId], ite)
+
+			continue
+		}
+
+		replaced[tagId] = struct{}{}
+
+		// Replace the first occurrence and drop the rest.
+
+		entries := make([]*IfdTagEntry, 0, len(dst.entries))
+		isReplaced := false
+		for _, dstIte := range dst.entries {
+			if dstIte.tagId != tagId {
+				entries = append(entries, dstIte)
+			} else if isReplaced == false {
+				entries = append(entries, ite)
+				isReplaced = true
+			}
+		}
+
+		dst.entries = entries
+		dst.entriesByTagId[tagId] = []*IfdTagEntry{ite}
+	}
+
+	if dst.childIfdIndex == nil {
+		dst.childIfdIndex = make(map[string]*Ifd)
+	}
+
+	for _, srcChildIfd := range src.children {
+		childIfdPath := srcChildIfd.ifdIdentity.String()
+
+		if dstChildIfd, found := dst.childIfdIndex[childIfdPath]; found == true {
+			mergeIfd(dstChildIfd, srcChildIfd, merged)
+			continue
+		}
+
+		srcChildIfd.parentIfd = dst
+
+		dst.children = append(dst.children, srcChildIfd)
+		dst.childIfdIndex[childIfdPath] = srcChildIfd
+	}
+
+	if src.thumbnailData != nil {
+		dst.thumbnailData = src.thumbnailData
+	}
+
+	if src.makerNoteIfd != nil {
+		dst.makerNoteIfd = src.makerNoteIfd
+	}
+
+	if src.nextIfd != nil {
+		if dst.nextIfd != nil {
+			mergeIfd(dst.nextIfd, src.nextIfd, merged)
+		} else {
+			dst.nextIfd = src.nextIfd
+		}
+	}
+}
+
+// newIfdIndexFromTree builds an index for the tree under the given root IFD.
+// The IFDs are (re)numbered in the order that they are found.
+func newIfdIndexFromTree(rootIfd *Ifd) (index IfdIndex) {
+	ifds := make([]*Ifd, 0)
+	tree := make(map[int]*Ifd)
+	lookup := make(map[string]*Ifd)
+
+	add := func(ifd *Ifd) {
+		ifd.id = len(ifds)
+
+		ifds = append(ifds, ifd)
+		tree[ifd.id] = ifd
+		lookup[ifd.ifdIdentity.String()] = ifd
+	}
+
+	visitor := func(ifd *Ifd, depth int) error {
+		add(ifd)
+
+		if ifd.makerNoteIfd != nil {
+			add(ifd.makerNoteIfd)
+		}
+
+		return nil
+	}
+
+	err := rootIfd.Walk(visitor)
+	log.PanicIf(err)
+
+	index.RootIfd = rootIfd
+	index.Ifds = ifds
+	index.Tree = tree
+	index.Lookup = lookup
+
+	return index
+}
@@ -0,0 +1,168 @@
+package exif
+
+import (
+	"testing"
+
+	"encoding/binary"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/mschilli/go-exif/v3/common"
+)
+
+// getTestSegmentExifData returns an EXIF blob with the given Make and child
+// IFD (Exif or GPS) in IFD0. IFD0 also has an Artist with the Exif IFD and a
+// Software with the GPS IFD.
+func getTestSegmentExifData(byteOrder binary.ByteOrder, makeValue string, iiChild *exifcommon.IfdIdentity) []byte {
+	tags := []testBuilderTag{
+		{tagId: 0x010f, value: makeValue},
+	}
+
+	childIfd := testBuilderIfd{
+		ii: iiChild,
+	}
+
+	if iiChild == exifcommon.IfdExifStandardIfdIdentity {
+		tags = append(tags, testBuilderTag{tagId: 0x013b, value: "some artist"})
+		childIfd.tags = []testBuilderTag{
+			{tagId: 0x9003, value: "2020:01:02 03:04:05"},
+		}
+	} else {
+		tags = append(tags, testBuilderTag{tagId: 0x0131, value: "some software"})
+		childIfd.tags = []testBuilderTag{
+			{tagId: 0x0000, value: []byte{2, 2, 0, 0}},
+		}
+	}
+
+	return getTestBuilderExifData(byteOrder, tags, childIfd)
+}
+
+func TestCollectSegments(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	segments := [][]byte{
+		getTestSegmentExifData(binary.BigEndian, "first make", exifcommon.IfdExifStandardIfdIdentity),
+		getTestSegmentExifData(binary.LittleEndian, "second make", exifcommon.IfdGpsInfoStandardIfdIdentity),
+	}
+
+	index, err := CollectSegments(im, ti, segments)
+	log.PanicIf(err)
+
+	rootIfd := index.RootIfd
+
+	if rootIfd.Segment() != 0 {
+		t.Fatalf("Root IFD segment not correct: (%d)", rootIfd.Segment())
+	} else if len(index.Ifds) != 3 {
+		t.Fatalf("IFD count not correct: (%d)", len(index.Ifds))
+	}
+
+	// The later segment wins. In both, the Make follows the Model.
+
+	makeIte, err := rootIfd.FindTagWithId(0x010f)
+	log.PanicIf(err)
+
+	if len(makeIte) != 1 {
+		t.Fatalf("Expected exactly one Make tag: (%d)", len(makeIte))
+	}
+
+	value, err := makeIte[0].Value()
+	log.PanicIf(err)
+
+	if value.(string) != "second make" {
+		t.Fatalf("Make not correct: [%s]", value)
+	} else if makeIte[0].Segment() != 1 {
+		t.Fatalf("Make segment not correct: (%d)", makeIte[0].Segment())
+	} else if rootIfd.Entries()[1] != makeIte[0] {
+		t.Fatalf("Replaced tag should keep its position.")
+	}
+
+	// Tags that are only in one segment are retained.
+
+	artistIte, found := rootIfd.EntryByTagId(0x013b)
+	if found == false {
+		t.Fatalf("Artist not found.")
+	} else if artistIte.Segment() != 0 {
+		t.Fatalf("Artist segment not correct: (%d)", artistIte.Segment())
+	}
+
+	softwareIte, found := rootIfd.EntryByTagId(0x0131)
+	if found == false {
+		t.Fatalf("Software not found.")
+	} else if softwareIte.Segment() != 1 {
+		t.Fatalf("Software segment not correct: (%d)", softwareIte.Segment())
+	}
+
+	// Both child IFDs are in the tree.
+
+	exifIfd, err := rootIfd.ChildWithIfdPath(exifcommon.IfdExifStandardIfdIdentity)
+	log.PanicIf(err)
+
+	if exifIfd.Segment() != 0 {
+		t.Fatalf("Exif IFD segment not correct: (%d)", exifIfd.Segment())
+	}
+
+	gpsIfd, err := rootIfd.ChildWithIfdPath(exifcommon.IfdGpsInfoStandardIfdIdentity)
+	log.PanicIf(err)
+
+	if gpsIfd.Segment() != 1 {
+		t.Fatalf("GPS IFD segment not correct: (%d)", gpsIfd.Segment())
+	} else if gpsIfd.parentIfd != rootIfd {
+		t.Fatalf("GPS IFD not parented to the merged root.")
+	}
+
+	_, _, err = index.FindTag(exifcommon.IfdGpsInfoStandardIfdIdentity.String(), 0x0000)
+	log.PanicIf(err)
+
+	dateTimeOriginal, err := index.DateTimeOriginal()
+	log.PanicIf(err)
+
+	if dateTimeOriginal.Year() != 2020 {
+		t.Fatalf("DateTimeOriginal not correct: [%s]", dateTimeOriginal)
+	}
+
+	// Each IFD has a unique ID.
+	for i, ifd := range index.Ifds {
+		if index.Tree[i] != ifd {
+			t.Fatalf("IFD (%d) not in the tree under its ID.", i)
+		}
+	}
+}
+
+func TestCollectSegments__SkipsNonExif(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	segments := [][]byte{
+		[]byte("not exif"),
+		getTestSegmentExifData(binary.BigEndian, "some make", exifcommon.IfdExifStandardIfdIdentity),
+	}
+
+	index, err := CollectSegments(im, ti, segments)
+	log.PanicIf(err)
+
+	if index.RootIfd.Segment() != 1 {
+		t.Fatalf("Root IFD segment not correct: (%d)", index.RootIfd.Segment())
+	}
+}
+
+func TestCollectSegments__NoExif(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	_, err = CollectSegments(im, ti, [][]byte{[]byte("not exif")})
+	if err != ErrNoExif {
+		t.Fatalf("Expected ErrNoExif: %v", err)
+	}
+
+	_, err = CollectSegments(im, ti, nil)
+	if err != ErrNoExif {
+		t.Fatalf("Expected ErrNoExif for no segments: %v", err)
+	}
+}
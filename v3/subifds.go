@@ -0,0 +1,62 @@
+package exif
+
+import (
+	"github.com/dsoprea/go-logging"
+
+	"github.com/mschilli/go-exif/v3/common"
+)
+
+const (
+	// SubIfdsTagId is the tag-ID of the TIFF SubIFDs tag. Its value is a list
+	// of offsets, each of which is a child IFD (e.g. the full-resolution image
+	// in a RAW file or the other resolutions of a multi-resolution TIFF).
+	SubIfdsTagId = 0x014a
+
+	// SubIfdsIfdName is the name that the IFDs referenced by a SubIFDs tag
+	// are given (e.g. "IFD/SubIFD", "IFD/SubIFD1").
+	SubIfdsIfdName = "SubIFD"
+)
+
+// isSubIfd returns true if the IFD was referenced by a SubIFDs tag. Its tags
+// are the same as those in IFD0.
+func isSubIfd(ii *exifcommon.IfdIdentity) bool {
+	return ii.TagId() == SubIfdsTagId && ii.Name() == SubIfdsIfdName
+}
+
+// subIfdQueue returns a QueuedIfd for each child IFD that the SubIFDs tag
+// refers to. An empty list is returned if the tag can't be read.
+func subIfdQueue(ifd *Ifd, ite *IfdTagEntry, tagIndex int, depth int) (queue []QueuedIfd, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	valueRaw, err := ite.Value()
+	if err != nil {
+		ifdEnumerateLogger.Warningf(nil, "Could not read the SubIFDs tag in IFD [%s]. Its IFDs will be skipped: %v", ifd.ifdIdentity, err)
+		return nil, nil
+	}
+
+	offsets, err := uint32sFromValue(valueRaw)
+	if err != nil {
+		ifdEnumerateLogger.Warningf(nil, "SubIFDs tag in IFD [%s] is not a list of LONGs within the first 4 GiB. Its IFDs will be skipped: [%s] %v", ifd.ifdIdentity, ite.TagType(), err)
+		return nil, nil
+	}
+
+	parentIfdTag := ifd.ifdIdentity.IfdTag()
+	subIfdsIfdTag := exifcommon.NewIfdTag(&parentIfdTag, SubIfdsTagId, SubIfdsIfdName)
+
+	queue = make([]QueuedIfd, len(offsets))
+	for i, offset := range offsets {
+		queue[i] = QueuedIfd{
+			IfdIdentity:    ifd.ifdIdentity.NewChild(subIfdsIfdTag, i),
+			Offset:         offset,
+			Parent:         ifd,
+			ParentTagIndex: tagIndex,
+			Depth:          depth + 1,
+		}
+	}
+
+	return queue, nil
+}
@@ -0,0 +1,265 @@
+package exif
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/mschilli/go-exif/v3/common"
+)
+
+// getTestSubIfdsExifData returns EXIF data whose root IFD has an ImageWidth
+// and a SubIFDs tag that refers to two child IFDs, each with its own
+// ImageWidth.
+func getTestSubIfdsExifData() []byte {
+	byteOrder := exifcommon.TestDefaultByteOrder
+
+	// Header (8), IFD0 (30), the SubIFDs value (8), and two child IFDs (18
+	// each).
+	exifData := make([]byte, 8+30+8+18+18)
+	copy(exifData, ExifBigEndianSignature[:])
+	byteOrder.PutUint32(exifData[4:], ExifDefaultFirstIfdOffset)
+
+	// IFD0
+	byteOrder.PutUint16(exifData[8:], 2)
+	putTestIfdEntry(exifData, byteOrder, 10, 0x0100, exifcommon.TypeShort, 1)
+	byteOrder.PutUint16(exifData[18:], 50)
+
+	putTestIfdEntry(exifData, byteOrder, 22, SubIfdsTagId, exifcommon.TypeLong, 2)
+	byteOrder.PutUint32(exifData[30:], 38)
+
+	byteOrder.PutUint32(exifData[38:], 46)
+	byteOrder.PutUint32(exifData[42:], 64)
+
+	// The two child IFDs.
+	for i, offset := range []int{46, 64} {
+		byteOrder.PutUint16(exifData[offset:], 1)
+		putTestIfdEntry(exifData, byteOrder, offset+2, 0x0100, exifcommon.TypeShort, 1)
+		byteOrder.PutUint16(exifData[offset+10:], uint16(100*(i+1)))
+	}
+
+	return exifData
+}
+
+func TestIfdEnumerate_Collect__SubIfds(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	_, index, err := Collect(im, ti, getTestSubIfdsExifData())
+	log.PanicIf(err)
+
+	if len(index.RootIfd.Children()) != 2 {
+		t.Fatalf("Expected two child IFDs: (%d)", len(index.RootIfd.Children()))
+	}
+
+	for i, fqIfdPath := range []string{"IFD/SubIFD", "IFD/SubIFD1"} {
+		ifd, found := index.Lookup[fqIfdPath]
+		if found == false {
+			t.Fatalf("IFD [%s] not found.", fqIfdPath)
+		} else if ifd != index.RootIfd.Children()[i] {
+			t.Fatalf("IFD [%s] is not child (%d) of the root IFD.", fqIfdPath, i)
+		} else if ifd.ParentTagIndex() != 1 {
+			t.Fatalf("Parent tag-index for IFD [%s] not correct: (%d)", fqIfdPath, ifd.ParentTagIndex())
+		}
+
+		ite, found := ifd.EntryByTagId(0x0100)
+		if found == false {
+			t.Fatalf("ImageWidth not found in IFD [%s].", fqIfdPath)
+		} else if ite.TagName() != "ImageWidth" {
+			t.Fatalf("Tag name not correct: [%s]", ite.TagName())
+		}
+
+		value, err := ite.Value()
+		log.PanicIf(err)
+
+		expected := []uint16{uint16(100 * (i + 1))}
+		if reflect.DeepEqual(value, expected) == false {
+			t.Fatalf("ImageWidth for IFD [%s] not correct: %v != %v", fqIfdPath, value, expected)
+		}
+	}
+}
+
+func TestIfdEnumerate_Collect__SubIfdsRootOnly(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, eh, err := NewIfdEnumerateWithBytes(im, ti, getTestSubIfdsExifData())
+	log.PanicIf(err)
+
+	co := &CollectOptions{
+		RootOnly: true,
+	}
+
+	index, err := ie.CollectWithOptions(eh.FirstIfdOffset, co)
+	log.PanicIf(err)
+
+	if len(index.Ifds) != 1 {
+		t.Fatalf("Expected only the root IFD: (%d)", len(index.Ifds))
+	}
+}
+
+func TestIfd_ChildIfdIndex__SubIfds(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	_, index, err := Collect(im, ti, getTestSubIfdsExifData())
+	log.PanicIf(err)
+
+	childIfdIndex := index.RootIfd.ChildIfdIndex()
+
+	if len(childIfdIndex) != 2 {
+		t.Fatalf("Expected two child IFDs in the index: %v", childIfdIndex)
+	}
+
+	for i, fqIfdPath := range []string{"IFD/SubIFD", "IFD/SubIFD1"} {
+		if childIfdIndex[fqIfdPath] != index.RootIfd.Children()[i] {
+			t.Fatalf("Child IFD [%s] not indexed correctly.", fqIfdPath)
+		}
+	}
+}
+
+func TestIfd_DumpTags__SubIfds(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	_, index, err := Collect(im, ti, getTestSubIfdsExifData())
+	log.PanicIf(err)
+
+	tags := index.RootIfd.DumpTags()
+
+	actual := make([]string, len(tags))
+	for i, ite := range tags {
+		actual[i] = ite.String()
+	}
+
+	expected := []string{
+		"IfdTagEntry<TAG-IFD-PATH=[IFD] TAG-ID=(0x0100) TAG-TYPE=[SHORT] UNIT-COUNT=(1)>",
+		"IfdTagEntry<TAG-IFD-PATH=[IFD] TAG-ID=(0x014a) TAG-TYPE=[LONG] UNIT-COUNT=(2)>",
+		"IfdTagEntry<TAG-IFD-PATH=[IFD/SubIFD] TAG-ID=(0x0100) TAG-TYPE=[SHORT] UNIT-COUNT=(1)>",
+		"IfdTagEntry<TAG-IFD-PATH=[IFD/SubIFD1] TAG-ID=(0x0100) TAG-TYPE=[SHORT] UNIT-COUNT=(1)>",
+	}
+
+	if reflect.DeepEqual(actual, expected) == false {
+		t.Fatalf("Tags not correct:\n%v", actual)
+	}
+}
+
+func TestIfd_DumpTree__SubIfds(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	_, index, err := Collect(im, ti, getTestSubIfdsExifData())
+	log.PanicIf(err)
+
+	actual := index.RootIfd.DumpTree()
+
+	expected := []string{
+		"> IFD [ROOT]->[IFD]:(0) TOP",
+		"  - (0x0100)",
+		"  - (0x014a)",
+		"  > IFD [IFD]->[IFD/SubIFD]:(0) TOP",
+		"    - (0x0100)",
+		"  < IFD [IFD]->[IFD/SubIFD]:(0) BOTTOM",
+		"  > IFD [IFD]->[IFD/SubIFD]:(1) TOP",
+		"    - (0x0100)",
+		"  < IFD [IFD]->[IFD/SubIFD]:(1) BOTTOM",
+		"< IFD [ROOT]->[IFD]:(0) BOTTOM",
+	}
+
+	if reflect.DeepEqual(actual, expected) == false {
+		t.Fatalf("Tree not correct:\n%v", actual)
+	}
+}
+
+func TestIfd_FprintIfdTree__SubIfds(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	_, index, err := Collect(im, ti, getTestSubIfdsExifData())
+	log.PanicIf(err)
+
+	b := new(bytes.Buffer)
+	index.RootIfd.FprintIfdTree(b)
+
+	expected := ` Ifd<ID=(0) IFD-PATH=[IFD] INDEX=(0) COUNT=(2) OFF=(0x0008) CHILDREN=(2) PARENT=(0x0000) NEXT-IFD=(0x0000)>
+   Ifd<ID=(1) IFD-PATH=[IFD/SubIFD] INDEX=(0) COUNT=(1) OFF=(0x002e) CHILDREN=(0) PARENT=(0x0008) NEXT-IFD=(0x0000)>
+   Ifd<ID=(2) IFD-PATH=[IFD/SubIFD] INDEX=(1) COUNT=(1) OFF=(0x0040) CHILDREN=(0) PARENT=(0x0008) NEXT-IFD=(0x0000)>
+`
+
+	if b.String() != expected {
+		t.Fatalf("IFD tree not correct:\n%s", b.String())
+	}
+}
+
+func TestIfd_FprintTagTree__SubIfds(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	_, index, err := Collect(im, ti, getTestSubIfdsExifData())
+	log.PanicIf(err)
+
+	b := new(bytes.Buffer)
+	index.RootIfd.FprintTagTree(b, true)
+
+	expected := ` IFD: Ifd<ID=(0) IFD-PATH=[IFD] INDEX=(0) COUNT=(2) OFF=(0x0008) CHILDREN=(2) PARENT=(0x0000) NEXT-IFD=(0x0000)>
+ - TAG: IfdTagEntry<TAG-IFD-PATH=[IFD] TAG-ID=(0x0100) TAG-TYPE=[SHORT] UNIT-COUNT=(1)> NAME=[ImageWidth] VALUE=[[50]]
+ - TAG: IfdTagEntry<TAG-IFD-PATH=[IFD] TAG-ID=(0x014a) TAG-TYPE=[LONG] UNIT-COUNT=(2)> NAME=[SubIFDs] VALUE=[[46 64]]
+   IFD: Ifd<ID=(1) IFD-PATH=[IFD/SubIFD] INDEX=(0) COUNT=(1) OFF=(0x002e) CHILDREN=(0) PARENT=(0x0008) NEXT-IFD=(0x0000)>
+   - TAG: IfdTagEntry<TAG-IFD-PATH=[IFD/SubIFD] TAG-ID=(0x0100) TAG-TYPE=[SHORT] UNIT-COUNT=(1)> NAME=[ImageWidth] VALUE=[[100]]
+   IFD: Ifd<ID=(2) IFD-PATH=[IFD/SubIFD] INDEX=(1) COUNT=(1) OFF=(0x0040) CHILDREN=(0) PARENT=(0x0008) NEXT-IFD=(0x0000)>
+   - TAG: IfdTagEntry<TAG-IFD-PATH=[IFD/SubIFD1] TAG-ID=(0x0100) TAG-TYPE=[SHORT] UNIT-COUNT=(1)> NAME=[ImageWidth] VALUE=[[200]]
+`
+
+	if b.String() != expected {
+		t.Fatalf("Tag tree not correct:\n%s", b.String())
+	}
+}
+
+func TestIfd_EnumerateTagsRecursively__SubIfds(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	_, index, err := Collect(im, ti, getTestSubIfdsExifData())
+	log.PanicIf(err)
+
+	actual := make([]string, 0)
+
+	visitor := func(ifd *Ifd, ite *IfdTagEntry) error {
+		actual = append(actual, ite.String())
+		return nil
+	}
+
+	err = index.RootIfd.EnumerateTagsRecursively(visitor)
+	log.PanicIf(err)
+
+	// SubIFDs is a list of offsets rather than a child-IFD pointer, so it's
+	// visited along with the tags of the IFDs that it refers to.
+	expected := []string{
+		"IfdTagEntry<TAG-IFD-PATH=[IFD] TAG-ID=(0x0100) TAG-TYPE=[SHORT] UNIT-COUNT=(1)>",
+		"IfdTagEntry<TAG-IFD-PATH=[IFD] TAG-ID=(0x014a) TAG-TYPE=[LONG] UNIT-COUNT=(2)>",
+		"IfdTagEntry<TAG-IFD-PATH=[IFD/SubIFD] TAG-ID=(0x0100) TAG-TYPE=[SHORT] UNIT-COUNT=(1)>",
+		"IfdTagEntry<TAG-IFD-PATH=[IFD/SubIFD1] TAG-ID=(0x0100) TAG-TYPE=[SHORT] UNIT-COUNT=(1)>",
+	}
+
+	if reflect.DeepEqual(actual, expected) == false {
+		t.Fatalf("Visited tags not correct:\n%v", actual)
+	}
+}
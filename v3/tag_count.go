@@ -0,0 +1,197 @@
+package exif
+
+import (
+	"github.com/dsoprea/go-logging"
+
+	"github.com/mschilli/go-exif/v3/common"
+)
+
+// countQueuedIfd is an IFD that CountAllTags() still has to count.
+type countQueuedIfd struct {
+	mi     *exifcommon.MappedIfd
+	offset uint32
+	depth  int
+
+	// isChained is true if the IFD was linked-to by a next-IFD offset rather
+	// than by a tag.
+	isChained bool
+}
+
+// CountAllTags returns the number of tags in the root IFD, the IFDs that
+// follow it in the chain, and their (standard) child IFDs without building
+// any of the tags: only the tag-counts, the tag-IDs, the offsets of the child
+// IFDs, and the next-IFD offsets are read. This is the same count that
+// `IfdIndex.CountTags()` gives for a well-formed file, except that the tags
+// are counted as they are stored (so tags that Collect() would drop, like
+// those with an invalid type, are counted) and SubIFDs and MakerNote IFDs are
+// not followed. An IFD that is linked-to from more than one place is only
+// counted once. IFDs other than the root that aren't within the data are
+// skipped with a warning unless the enumerator is in strict mode.
+func (ie *IfdEnumerate) CountAllTags() (count int, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	countIe, eh, err := ie.headerEnumerator()
+	log.PanicIf(err)
+
+	rootMi, err := ie.ifdMapping.GetWithPath(exifcommon.IfdStandardIfdIdentity.UnindexedString())
+	log.PanicIf(err)
+
+	queue := []countQueuedIfd{
+		{
+			mi:     rootMi,
+			offset: eh.FirstIfdOffset,
+		},
+	}
+
+	// Offsets that have already been counted, by IFD-path.
+	visited := make(map[*exifcommon.MappedIfd]map[uint32]struct{})
+
+	ifdCount := 0
+
+	for len(queue) > 0 {
+		qi := queue[0]
+		queue = queue[1:]
+
+		ifdPath := qi.mi.PathPhrase()
+
+		visitedOffsets, found := visited[qi.mi]
+		if found == false {
+			visitedOffsets = make(map[uint32]struct{})
+			visited[qi.mi] = visitedOffsets
+		}
+
+		if _, found := visitedOffsets[qi.offset]; found == true {
+			if qi.isChained == true && ie.strictMode == true {
+				return 0, ErrIfdCycle
+			} else if qi.isChained == true {
+				ifdEnumerateLogger.Warningf(nil, "IFD [%s] at offset (0x%08x) has already been counted. There might be a cycle. Skipping.", ifdPath, qi.offset)
+			}
+
+			continue
+		}
+
+		visitedOffsets[qi.offset] = struct{}{}
+
+		if qi.depth > DefaultMaxIfdDepth {
+			ifdEnumerateLogger.Warningf(nil, "IFD [%s] at offset (0x%08x) is nested deeper than (%d). Skipping.", ifdPath, qi.offset, DefaultMaxIfdDepth)
+			continue
+		}
+
+		if ifdCount >= DefaultMaxIfdCount {
+			ifdEnumerateLogger.Warningf(nil, "More than (%d) IFDs were found. Giving up.", DefaultMaxIfdCount)
+			return 0, ErrTooManyIfds
+		}
+
+		isRequired := ifdCount == 0 || ie.strictMode == true
+
+		ifdCount++
+
+		tagCount, nextIfdOffset, childQis, err := countIe.countIfdTags(qi)
+		if err != nil {
+			if (err == ErrOffsetInvalid || err == ErrTagCountInvalid) && isRequired == false {
+				ifdEnumerateLogger.Warningf(nil, "IFD [%s] at offset (0x%08x) is not within the data. Skipping.", ifdPath, qi.offset)
+				continue
+			} else if err == ErrOffsetInvalid || err == ErrTagCountInvalid {
+				return 0, err
+			}
+
+			log.Panic(err)
+		}
+
+		count += tagCount
+
+		queue = append(queue, childQis...)
+
+		if nextIfdOffset != 0 {
+			nextQi := countQueuedIfd{
+				mi:        qi.mi,
+				offset:    nextIfdOffset,
+				depth:     qi.depth,
+				isChained: true,
+			}
+
+			queue = append(queue, nextQi)
+		}
+	}
+
+	return count, nil
+}
+
+// countIfdTags reads the tag-count of one IFD and its next-IFD offset, along
+// with the IFDs that its tags point to. The rest of each tag is skipped.
+// ErrOffsetInvalid (or ErrTagCountInvalid) is returned (unwrapped) if the IFD
+// isn't within the data.
+func (ie *IfdEnumerate) countIfdTags(qi countQueuedIfd) (tagCount int, nextIfdOffset uint32, childQis []countQueuedIfd, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	bp, err := ie.getByteParser(qi.offset)
+	if err != nil {
+		if err == ErrOffsetInvalid {
+			return 0, 0, nil, err
+		}
+
+		log.Panic(err)
+	}
+
+	tagCount, err = bp.getTagCount()
+	if err != nil {
+		if err == ErrTagCountInvalid {
+			return 0, 0, nil, err
+		}
+
+		log.Panic(err)
+	}
+
+	for i := 0; i < tagCount; i++ {
+		tagId, err := bp.getUint16()
+		log.PanicIf(err)
+
+		childMi, found := qi.mi.Children[tagId]
+		if found == false {
+			// The type, the unit-count, and the value-offset.
+			err := bp.skip(bp.entryLength() - 2)
+			log.PanicIf(err)
+
+			continue
+		}
+
+		tagTypeRaw, err := bp.getUint16()
+		log.PanicIf(err)
+
+		_, childOffset, _, err := bp.getUnitCountAndValueOffset(exifcommon.TagTypePrimitive(tagTypeRaw))
+		if err != nil {
+			if err == ErrBigTiff {
+				ifdEnumerateLogger.Warningf(nil, "Child IFD for tag (0x%04x) is beyond the first 4 GiB and will not be counted.", tagId)
+				continue
+			}
+
+			log.Panic(err)
+		}
+
+		childQi := countQueuedIfd{
+			mi:     childMi,
+			offset: childOffset,
+			depth:  qi.depth + 1,
+		}
+
+		childQis = append(childQis, childQi)
+	}
+
+	nextIfdOffset, err = bp.getOffset()
+	if err == ErrBigTiff {
+		ifdEnumerateLogger.Warningf(nil, "Next IFD is beyond the first 4 GiB and will not be counted.")
+		nextIfdOffset = 0
+	} else if err != nil {
+		log.Panic(err)
+	}
+
+	return tagCount, nextIfdOffset, childQis, nil
+}
@@ -0,0 +1,145 @@
+package exif
+
+import (
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/mschilli/go-exif/v3/common"
+)
+
+func TestIfdEnumerate_CountAllTags(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, eh, err := NewIfdEnumerateWithBytes(im, ti, getTestExifData())
+	log.PanicIf(err)
+
+	count, err := ie.CountAllTags()
+	log.PanicIf(err)
+
+	index, err := ie.Collect(eh.FirstIfdOffset)
+	log.PanicIf(err)
+
+	if count != index.CountTags() {
+		t.Fatalf("Count not correct: (%d) != (%d)", count, index.CountTags())
+	}
+}
+
+func TestIfdEnumerate_CountAllTags__DroppedTag(t *testing.T) {
+	rawExif, err := SearchFileAndExtractExif(getTestGpsImageFilepath())
+	log.PanicIf(err)
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, eh, err := NewIfdEnumerateWithBytes(im, ti, rawExif)
+	log.PanicIf(err)
+
+	count, err := ie.CountAllTags()
+	log.PanicIf(err)
+
+	index, err := ie.Collect(eh.FirstIfdOffset)
+	log.PanicIf(err)
+
+	// The FocalLengthIn35mmFilm tag is stored as a LONG rather than a SHORT,
+	// so Collect() drops it, but it is still counted.
+	if count != index.CountTags()+1 {
+		t.Fatalf("Count not correct: (%d) != (%d)", count, index.CountTags()+1)
+	}
+}
+
+func TestIfdEnumerate_CountAllTags__Cycle(t *testing.T) {
+	// The third IFD links back to the second.
+	exifData := getTestIfdChainExifData(8+18, 8+18*2, 8+18)
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, _, err := NewIfdEnumerateWithBytes(im, ti, exifData)
+	log.PanicIf(err)
+
+	count, err := ie.CountAllTags()
+	log.PanicIf(err)
+
+	if count != 3 {
+		t.Fatalf("Count not correct: (%d)", count)
+	}
+
+	ie.SetStrictMode(true)
+
+	_, err = ie.CountAllTags()
+	if err != ErrIfdCycle {
+		t.Fatalf("Expected cycle error in strict mode: %v", err)
+	}
+}
+
+func TestIfdEnumerate_CountAllTags__NextIfdPastEnd(t *testing.T) {
+	exifData := getTestIfdChainExifData(8+18, 0x1000)
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, _, err := NewIfdEnumerateWithBytes(im, ti, exifData)
+	log.PanicIf(err)
+
+	count, err := ie.CountAllTags()
+	log.PanicIf(err)
+
+	if count != 2 {
+		t.Fatalf("Count not correct: (%d)", count)
+	}
+
+	ie.SetStrictMode(true)
+
+	_, err = ie.CountAllTags()
+	if err != ErrOffsetInvalid {
+		t.Fatalf("Expected offset error in strict mode: %v", err)
+	}
+}
+
+func BenchmarkIfdEnumerate_CountAllTags(b *testing.B) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, _, err := NewIfdEnumerateWithBytes(im, ti, getTestExifData())
+	log.PanicIf(err)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_, err := ie.CountAllTags()
+		log.PanicIf(err)
+	}
+}
+
+func BenchmarkIfdEnumerate_CountAllTags__Collect(b *testing.B) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, eh, err := NewIfdEnumerateWithBytes(im, ti, getTestExifData())
+	log.PanicIf(err)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		index, err := ie.Collect(eh.FirstIfdOffset)
+		log.PanicIf(err)
+
+		_ = index.CountTags()
+	}
+}
@@ -0,0 +1,94 @@
+package exif
+
+import (
+	"errors"
+
+	"io/ioutil"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/mschilli/go-exif/v3/common"
+)
+
+var (
+	// ErrValueTooLarge means that the new value doesn't fit in the space that
+	// the original value had. The EXIF has to be rewritten (e.g. with an
+	// IfdBuilder) in order to make it larger.
+	ErrValueTooLarge = errors.New("value too large for the space that it has")
+)
+
+// SetAsciiTag returns a copy of the EXIF blob with the value of the given
+// ASCII tag replaced. `fqIfdPath` is the fully-qualified path of the IFD that
+// has the tag (e.g. "IFD/Exif"). Since the value is patched in place, it has
+// to fit in the space that the original value had (including the trailing
+// NUL). Any space that is left over is filled with NULs so that the unit-
+// count and every offset stay the same. ErrValueTooLarge is returned
+// (unwrapped) if it doesn't fit and ErrTagNotFound if there is no such tag.
+func (ie *IfdEnumerate) SetAsciiTag(fqIfdPath string, tagId uint16, value string) (exifData []byte, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	rs, err := ie.ebs.GetReadSeeker(0)
+	log.PanicIf(err)
+
+	exifData, err = ioutil.ReadAll(rs)
+	log.PanicIf(err)
+
+	eh, err := ParseExifHeader(exifData)
+	log.PanicIf(err)
+
+	// Parse the copy, since that is what gets patched.
+	ebs := NewExifReadSeekerWithBytes(exifData)
+	patchIe := NewIfdEnumerate(ie.ifdMapping, ie.tagIndex, ebs, eh.ByteOrder)
+
+	index, err := patchIe.Collect(eh.FirstIfdOffset)
+	log.PanicIf(err)
+
+	ite, ifd, err := index.FindTag(fqIfdPath, tagId)
+	if err != nil {
+		if err == ErrTagNotFound {
+			return nil, err
+		}
+
+		log.Panic(err)
+	}
+
+	if ite.TagType() != exifcommon.TypeAscii {
+		log.Panicf("tag (0x%04x) in IFD [%s] is not ASCII: [%s]", tagId, fqIfdPath, ite.TagType())
+	}
+
+	unitCount := ite.UnitCount()
+	if uint32(len(value))+1 > unitCount {
+		return nil, ErrValueTooLarge
+	}
+
+	// Values that fit in four bytes (eight in a BigTIFF) are stored in the
+	// entry itself, where the offset would otherwise be.
+	isInline, err := ite.isInline()
+	log.PanicIf(err)
+
+	var valueOffset uint32
+	if isInline == true && ite.isBigTiff == true {
+		valueOffset = ExifAddressableAreaStart + ifd.Offset() + bigTiffOffsetSize + uint32(ite.tagIndex)*bigTiffEntryLength + 12
+	} else if isInline == true {
+		valueOffset = ExifAddressableAreaStart + ifd.Offset() + 2 + uint32(ite.tagIndex)*12 + 8
+	} else {
+		valueOffset = ExifAddressableAreaStart + ite.getValueOffset()
+	}
+
+	if int64(valueOffset)+int64(unitCount) > int64(len(exifData)) {
+		log.Panicf("value for tag (0x%04x) runs past the end of the EXIF data", tagId)
+	}
+
+	raw := exifData[valueOffset : valueOffset+unitCount]
+
+	n := copy(raw, value)
+	for i := n; i < len(raw); i++ {
+		raw[i] = 0
+	}
+
+	return exifData, nil
+}
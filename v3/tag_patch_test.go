@@ -0,0 +1,87 @@
+package exif
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/mschilli/go-exif/v3/common"
+)
+
+func TestIfdEnumerate_SetAsciiTag(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	original := getTestExifData()
+	originalCopy := make([]byte, len(original))
+	copy(originalCopy, original)
+
+	ie, _, err := NewIfdEnumerateWithBytes(im, ti, original)
+	log.PanicIf(err)
+
+	// DateTime
+	exifData, err := ie.SetAsciiTag(exifcommon.IfdStandardIfdIdentity.String(), 0x0132, "2020:01:02")
+	log.PanicIf(err)
+
+	if bytes.Equal(original, originalCopy) == false {
+		t.Fatalf("Original EXIF data was modified.")
+	} else if len(exifData) != len(original) {
+		t.Fatalf("EXIF length changed: (%d) != (%d)", len(exifData), len(original))
+	}
+
+	_, index, err := Collect(im, ti, exifData)
+	log.PanicIf(err)
+
+	ite, _, err := index.FindTag(exifcommon.IfdStandardIfdIdentity.String(), 0x0132)
+	log.PanicIf(err)
+
+	value, err := ite.Value()
+	log.PanicIf(err)
+
+	// The rest of the original space is filled with NULs.
+	if value.(string) != "2020:01:02\000\000\000\000\000\000\000\000\000" {
+		t.Fatalf("DateTime not correct: [%s]", value)
+	}
+
+	// Everything else should be the same.
+	dateTimeOriginal, err := index.DateTimeOriginal()
+	log.PanicIf(err)
+
+	if dateTimeOriginal.Format(ExifDateTimeLayout) != "2017:12:02 08:18:50" {
+		t.Fatalf("DateTimeOriginal not correct: [%s]", dateTimeOriginal)
+	}
+}
+
+func TestIfdEnumerate_SetAsciiTag__TooLarge(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, _, err := NewIfdEnumerateWithBytes(im, ti, getTestExifData())
+	log.PanicIf(err)
+
+	// DateTime has room for nineteen characters and a NUL.
+	_, err = ie.SetAsciiTag(exifcommon.IfdStandardIfdIdentity.String(), 0x0132, "2020:01:02 03:04:05Z")
+	if err != ErrValueTooLarge {
+		t.Fatalf("Expected too-large error: %v", err)
+	}
+}
+
+func TestIfdEnumerate_SetAsciiTag__NotFound(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, _, err := NewIfdEnumerateWithBytes(im, ti, getTestExifData())
+	log.PanicIf(err)
+
+	_, err = ie.SetAsciiTag(exifcommon.IfdStandardIfdIdentity.String(), 0xfffe, "abc")
+	if err != ErrTagNotFound {
+		t.Fatalf("Expected not-found error: %v", err)
+	}
+}
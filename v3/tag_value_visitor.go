@@ -0,0 +1,63 @@
+package exif
+
+import (
+	"github.com/dsoprea/go-logging"
+
+	"github.com/mschilli/go-exif/v3/common"
+)
+
+// TagValueVisitor is called for each tag when enumerating through the EXIF,
+// along with its decoded value. If the value couldn't be decoded, `value` is
+// nil and `valueErr` describes why; the scan carries on regardless unless the
+// visitor itself returns an error.
+type TagValueVisitor func(ifdPath string, ite *IfdTagEntry, value interface{}, valueErr error) (err error)
+
+// ScanValues is the same as Scan() but decodes the value of each tag before
+// passing it to the visitor.
+func (ie *IfdEnumerate) ScanValues(iiRoot *exifcommon.IfdIdentity, ifdOffset uint32, visitor TagValueVisitor, so *ScanOptions) (med *MiscellaneousExifData, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	tagVisitor := func(ite *IfdTagEntry) (err error) {
+		value, valueErr := ite.Value()
+		if valueErr != nil {
+			value = nil
+		}
+
+		return visitor(ite.IfdPath(), ite, value, valueErr)
+	}
+
+	med, err = ie.Scan(iiRoot, ifdOffset, tagVisitor, so)
+	if err != nil {
+		if asKnownReadError(err) != nil {
+			return nil, err
+		}
+
+		log.Panic(err)
+	}
+
+	return med, nil
+}
+
+// VisitValues is the same as Visit() but invokes a TagValueVisitor with the
+// decoded value of every tag.
+func VisitValues(rootIfdIdentity *exifcommon.IfdIdentity, ifdMapping *exifcommon.IfdMapping, tagIndex *TagIndex, exifData []byte, visitor TagValueVisitor, so *ScanOptions) (eh ExifHeader, furthestOffset uint32, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	ie, eh, err := NewIfdEnumerateWithBytes(ifdMapping, tagIndex, exifData)
+	log.PanicIf(err)
+
+	_, err = ie.ScanValues(rootIfdIdentity, eh.FirstIfdOffset, visitor, so)
+	log.PanicIf(err)
+
+	furthestOffset = ie.FurthestOffset()
+
+	return eh, furthestOffset, nil
+}
@@ -0,0 +1,90 @@
+package exif
+
+import (
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/mschilli/go-exif/v3/common"
+)
+
+func TestVisitValues(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	values := make(map[string]interface{})
+	count := 0
+
+	visitor := func(ifdPath string, ite *IfdTagEntry, value interface{}, valueErr error) (err error) {
+		log.PanicIf(valueErr)
+
+		if ifdPath != ite.IfdPath() {
+			t.Fatalf("IFD-path not correct: [%s] != [%s]", ifdPath, ite.IfdPath())
+		}
+
+		values[ite.TagName()] = value
+		count++
+
+		return nil
+	}
+
+	_, _, err = VisitValues(exifcommon.IfdStandardIfdIdentity, im, ti, getTestExifData(), visitor, nil)
+	log.PanicIf(err)
+
+	_, index, err := Collect(im, ti, getTestExifData())
+	log.PanicIf(err)
+
+	if count != len(index.RootIfd.DumpTags()) {
+		t.Fatalf("Visited tag count not correct: (%d) != (%d)", count, len(index.RootIfd.DumpTags()))
+	} else if values["Model"] != "Canon EOS 5D Mark III" {
+		t.Fatalf("Model value not correct: %v", values["Model"])
+	} else if values["ISOSpeedRatings"].([]uint16)[0] != 1600 {
+		t.Fatalf("ISOSpeedRatings value not correct: %v", values["ISOSpeedRatings"])
+	}
+}
+
+func TestVisitValues__BadValue(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	exifData := make([]byte, len(getTestExifData()))
+	copy(exifData, getTestExifData())
+
+	eh, err := ParseExifHeader(exifData)
+	log.PanicIf(err)
+
+	// Point the value of the Model tag (the second entry in IFD0) way past the
+	// end of the data.
+	valueOffsetPosition := eh.FirstIfdOffset + 2 + IfdTagEntrySize + 8
+	eh.ByteOrder.PutUint32(exifData[valueOffsetPosition:], 0xfffffff0)
+
+	failed := make([]string, 0)
+	count := 0
+
+	visitor := func(ifdPath string, ite *IfdTagEntry, value interface{}, valueErr error) (err error) {
+		count++
+
+		if valueErr != nil {
+			if value != nil {
+				t.Fatalf("Value not nil for failed tag: %v", value)
+			}
+
+			failed = append(failed, ite.TagName())
+		}
+
+		return nil
+	}
+
+	_, _, err = VisitValues(exifcommon.IfdStandardIfdIdentity, im, ti, exifData, visitor, nil)
+	log.PanicIf(err)
+
+	if len(failed) != 1 || failed[0] != "Model" {
+		t.Fatalf("Failed tags not correct: %v", failed)
+	} else if count != 59 {
+		t.Fatalf("Scan did not continue past the bad tag: (%d)", count)
+	}
+}
@@ -7,7 +7,7 @@ import (
 	"github.com/dsoprea/go-logging"
 	"gopkg.in/yaml.v2"
 
-	"github.com/dsoprea/go-exif/v3/common"
+	"github.com/mschilli/go-exif/v3/common"
 )
 
 const (
@@ -53,6 +53,40 @@ const (
 
 	// TagAltitudeRefId is the ID of the GPS altitude-orientation tag.
 	TagAltitudeRefId = 0x0005
+
+	// TagSpeedRefId is the ID of the GPS speed-unit tag.
+	TagSpeedRefId = 0x000c
+
+	// TagSpeedId is the ID of the GPS speed tag.
+	TagSpeedId = 0x000d
+
+	// TagImgDirectionRefId is the ID of the GPS image-direction reference
+	// tag.
+	TagImgDirectionRefId = 0x0010
+
+	// TagImgDirectionId is the ID of the GPS image-direction tag.
+	TagImgDirectionId = 0x0011
+
+	// TagDestLatitudeRefId is the ID of the GPS destination-latitude
+	// orientation tag.
+	TagDestLatitudeRefId = 0x0013
+
+	// TagDestLatitudeId is the ID of the GPS destination-latitude tag.
+	TagDestLatitudeId = 0x0014
+
+	// TagDestLongitudeRefId is the ID of the GPS destination-longitude
+	// orientation tag.
+	TagDestLongitudeRefId = 0x0015
+
+	// TagDestLongitudeId is the ID of the GPS destination-longitude tag.
+	TagDestLongitudeId = 0x0016
+
+	// TagDestBearingRefId is the ID of the GPS destination-bearing reference
+	// tag.
+	TagDestBearingRefId = 0x0017
+
+	// TagDestBearingId is the ID of the GPS destination-bearing tag.
+	TagDestBearingId = 0x0018
 )
 
 var (
@@ -66,7 +100,7 @@ var (
 )
 
 var (
-	tagsLogger = log.NewLogger("exif.tags")
+	tagsLogger = newPackageLogger("exif.tags")
 )
 
 // File structures.
@@ -285,6 +319,11 @@ func (ti *TagIndex) Get(ii *exifcommon.IfdIdentity, id uint16) (it *IndexedTag,
 
 	ifdPath := ii.UnindexedString()
 
+	// The IFDs referenced by a SubIFDs tag have the same tags as IFD0.
+	if isSubIfd(ii) == true {
+		ifdPath = exifcommon.IfdStandardIfdIdentity.UnindexedString()
+	}
+
 	it, err = ti.getOne(ifdPath, id)
 	if err == nil {
 		return it, nil
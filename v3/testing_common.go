@@ -5,11 +5,12 @@ import (
 	"reflect"
 	"testing"
 
+	"encoding/binary"
 	"io/ioutil"
 
 	"github.com/dsoprea/go-logging"
 
-	"github.com/dsoprea/go-exif/v3/common"
+	"github.com/mschilli/go-exif/v3/common"
 )
 
 var (
@@ -155,6 +156,215 @@ func validateExifSimpleTestIb(exifData []byte, t *testing.T) {
 	}
 }
 
+// getTestIfdChainExifData returns a big-endian EXIF blob with one minimal IFD
+// (a single ImageWidth tag) for each of the given next-IFD offsets. The IFDs
+// are eighteen bytes each and are laid out contiguously beginning at offset
+// (8). This is used to produce malformed chains.
+func getTestIfdChainExifData(nextIfdOffsets ...uint32) []byte {
+	byteOrder := exifcommon.TestDefaultByteOrder
+
+	exifData := make([]byte, 8+18*len(nextIfdOffsets))
+	copy(exifData, ExifBigEndianSignature[:])
+	byteOrder.PutUint32(exifData[4:], ExifDefaultFirstIfdOffset)
+
+	for i, nextIfdOffset := range nextIfdOffsets {
+		ifdData := exifData[8+18*i:]
+
+		byteOrder.PutUint16(ifdData[0:], 1)
+
+		// ImageWidth
+		byteOrder.PutUint16(ifdData[2:], 0x0100)
+		byteOrder.PutUint16(ifdData[4:], uint16(exifcommon.TypeShort))
+		byteOrder.PutUint32(ifdData[6:], 1)
+		byteOrder.PutUint16(ifdData[10:], uint16(i+1))
+
+		byteOrder.PutUint32(ifdData[14:], nextIfdOffset)
+	}
+
+	return exifData
+}
+
+// putTestIfdEntry writes the tag-ID, type, and unit-count of an IFD entry at
+// the given position. The value-offset (or the inline value) is left to the
+// caller.
+func putTestIfdEntry(data []byte, byteOrder binary.ByteOrder, position int, tagId uint16, tagType exifcommon.TagTypePrimitive, unitCount uint32) {
+	byteOrder.PutUint16(data[position:], tagId)
+	byteOrder.PutUint16(data[position+2:], uint16(tagType))
+	byteOrder.PutUint32(data[position+4:], unitCount)
+}
+
+// testIfdEntry describes one entry for getTestIfdExifData().
+type testIfdEntry struct {
+	tagId     uint16
+	tagType   exifcommon.TagTypePrimitive
+	unitCount uint32
+
+	// value is stored inline if the unit-count says that it fits and after
+	// the IFD otherwise. It may be shorter than the unit-count says so that
+	// the value runs past the end of the data.
+	value []byte
+}
+
+// getTestIfdExifData returns an EXIF blob in the given byte-order with a
+// single IFD (at 8) made of the given entries.
+func getTestIfdExifData(byteOrder binary.ByteOrder, entries ...testIfdEntry) []byte {
+	exifData := make([]byte, 8+2+12*len(entries)+4)
+
+	if byteOrder == binary.LittleEndian {
+		copy(exifData, ExifLittleEndianSignature[:])
+	} else {
+		copy(exifData, ExifBigEndianSignature[:])
+	}
+
+	byteOrder.PutUint32(exifData[4:], ExifDefaultFirstIfdOffset)
+	byteOrder.PutUint16(exifData[8:], uint16(len(entries)))
+
+	for i, entry := range entries {
+		position := 10 + 12*i
+		putTestIfdEntry(exifData, byteOrder, position, entry.tagId, entry.tagType, entry.unitCount)
+
+		unitSize, _ := entry.tagType.UnitSize()
+		if uint64(unitSize)*uint64(entry.unitCount) <= 4 {
+			copy(exifData[position+8:position+12], entry.value)
+		} else {
+			byteOrder.PutUint32(exifData[position+8:], uint32(len(exifData)))
+			exifData = append(exifData, entry.value...)
+		}
+	}
+
+	return exifData
+}
+
+// putTestBigTiffIfdEntry writes a (20-byte) BigTIFF IFD entry at the given
+// position. The type is raw so that the BigTIFF-only and invalid types can be
+// given. `valueOffset` is copied into the eight-byte value-offset field.
+func putTestBigTiffIfdEntry(data []byte, byteOrder binary.ByteOrder, position int, tagId uint16, tagType uint16, unitCount uint64, valueOffset []byte) {
+	byteOrder.PutUint16(data[position:], tagId)
+	byteOrder.PutUint16(data[position+2:], tagType)
+	byteOrder.PutUint64(data[position+4:], unitCount)
+	copy(data[position+12:position+20], valueOffset)
+}
+
+// testBuilderTag is a tag for getTestBuilderExifData(). If `tagType` is set,
+// the value is stored as that type rather than as the first type that the tag
+// supports.
+type testBuilderTag struct {
+	tagId   uint16
+	value   interface{}
+	tagType exifcommon.TagTypePrimitive
+}
+
+// testBuilderIfd is a child IFD for getTestBuilderExifData().
+type testBuilderIfd struct {
+	ii       *exifcommon.IfdIdentity
+	tags     []testBuilderTag
+	children []testBuilderIfd
+}
+
+// getTestBuilderExifData builds and encodes an EXIF blob whose IFD0 has a
+// Model tag followed by the given tags, and has the given child IFDs.
+func getTestBuilderExifData(byteOrder binary.ByteOrder, tags []testBuilderTag, children ...testBuilderIfd) []byte {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	root := testBuilderIfd{
+		ii:       exifcommon.IfdStandardIfdIdentity,
+		tags:     append([]testBuilderTag{{tagId: 0x0110, value: "some model"}}, tags...),
+		children: children,
+	}
+
+	ib := buildTestIfd(im, ti, byteOrder, root)
+
+	ibe := NewIfdByteEncoder()
+
+	exifData, err := ibe.EncodeToExif(ib)
+	log.PanicIf(err)
+
+	return exifData
+}
+
+// buildTestIfd returns a builder for the given IFD and its children.
+func buildTestIfd(im *exifcommon.IfdMapping, ti *TagIndex, byteOrder binary.ByteOrder, tbi testBuilderIfd) *IfdBuilder {
+	ib := NewIfdBuilder(im, ti, tbi.ii, byteOrder)
+
+	for _, tbt := range tbi.tags {
+		if tbt.tagType == 0 {
+			err := ib.AddStandard(tbt.tagId, tbt.value)
+			log.PanicIf(err)
+
+			continue
+		}
+
+		ed, err := exifcommon.NewValueEncoder(byteOrder).Encode(tbt.value)
+		log.PanicIf(err)
+
+		bt := NewBuilderTag(
+			tbi.ii.UnindexedString(),
+			tbt.tagId,
+			tbt.tagType,
+			NewIfdBuilderTagValueFromBytes(ed.Encoded),
+			byteOrder)
+
+		err = ib.Add(bt)
+		log.PanicIf(err)
+	}
+
+	for _, child := range tbi.children {
+		err := ib.AddChildIb(buildTestIfd(im, ti, byteOrder, child))
+		log.PanicIf(err)
+	}
+
+	return ib
+}
+
+// getTestStripThumbnailExifData returns a big-endian EXIF blob whose IFD1
+// describes a thumbnail with the given compression as two six-byte strips,
+// with PhotometricInterpretation (2) (RGB). The second strip is stored before
+// the first one so that the strip order can be checked.
+func getTestStripThumbnailExifData(compression uint16) []byte {
+	byteOrder := exifcommon.TestDefaultByteOrder
+
+	exifData := make([]byte, 100)
+	copy(exifData, ExifBigEndianSignature[:])
+	byteOrder.PutUint32(exifData[4:], ExifDefaultFirstIfdOffset)
+
+	// IFD0 (at 8), with only an Orientation tag.
+	byteOrder.PutUint16(exifData[8:], 1)
+	putTestIfdEntry(exifData, byteOrder, 10, 0x0112, exifcommon.TypeShort, 1)
+	byteOrder.PutUint16(exifData[18:], 1)
+	byteOrder.PutUint32(exifData[22:], 26)
+
+	// IFD1 (at 26).
+	byteOrder.PutUint16(exifData[26:], 4)
+
+	putTestIfdEntry(exifData, byteOrder, 28, compressionTagId, exifcommon.TypeShort, 1)
+	byteOrder.PutUint16(exifData[36:], compression)
+
+	putTestIfdEntry(exifData, byteOrder, 40, photometricInterpretationTagId, exifcommon.TypeShort, 1)
+	byteOrder.PutUint16(exifData[48:], 2)
+
+	putTestIfdEntry(exifData, byteOrder, 52, stripOffsetsTagId, exifcommon.TypeLong, 2)
+	byteOrder.PutUint32(exifData[60:], 80)
+
+	putTestIfdEntry(exifData, byteOrder, 64, stripByteCountsTagId, exifcommon.TypeShort, 2)
+	byteOrder.PutUint16(exifData[72:], 6)
+	byteOrder.PutUint16(exifData[74:], 6)
+
+	byteOrder.PutUint32(exifData[76:], 0)
+
+	// The strip offsets (at 80).
+	byteOrder.PutUint32(exifData[80:], 94)
+	byteOrder.PutUint32(exifData[84:], 88)
+
+	// The strips: the second one (at 88) and then the first one (at 94).
+	copy(exifData[88:], []byte{7, 8, 9, 10, 11, 12})
+	copy(exifData[94:], []byte{1, 2, 3, 4, 5, 6})
+
+	return exifData
+}
+
 func getTestImageFilepath() string {
 	assetsPath := exifcommon.GetTestAssetsPath()
 	testImageFilepath := path.Join(assetsPath, "NDM_8901.jpg")
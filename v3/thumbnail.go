@@ -0,0 +1,400 @@
+package exif
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"io"
+
+	"image/jpeg"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/mschilli/go-exif/v3/common"
+)
+
+const (
+	// imageWidthTagId is the tag-ID of the ImageWidth tag.
+	imageWidthTagId = 0x0100
+
+	// imageLengthTagId is the tag-ID of the ImageLength tag (the height).
+	imageLengthTagId = 0x0101
+
+	// compressionTagId is the tag-ID of the Compression tag.
+	compressionTagId = 0x0103
+
+	// photometricInterpretationTagId is the tag-ID of the
+	// PhotometricInterpretation tag (the color space of uncompressed data).
+	photometricInterpretationTagId = 0x0106
+
+	// stripOffsetsTagId is the tag-ID of the StripOffsets tag.
+	stripOffsetsTagId = 0x0111
+
+	// stripByteCountsTagId is the tag-ID of the StripByteCounts tag.
+	stripByteCountsTagId = 0x0117
+
+	// compressionUncompressed means that the image data is stored as-is, in
+	// strips.
+	compressionUncompressed = 1
+
+	// compressionJpegOld is the (obsolete) JPEG compression from TIFF 6.0.
+	// Some writers still use it for thumbnails.
+	compressionJpegOld = 6
+
+	// compressionJpeg is the JPEG compression that EXIF thumbnails use.
+	compressionJpeg = 7
+)
+
+var (
+	// ErrUnsupportedThumbnailFormat means that the thumbnail uses a
+	// compression that this package can't handle.
+	ErrUnsupportedThumbnailFormat = errors.New("thumbnail format not supported")
+)
+
+// ThumbnailData is the raw thumbnail from IFD1.
+type ThumbnailData struct {
+	// Compression is the value of the Compression tag: 1 for uncompressed
+	// data, and 6 or 7 for JPEG.
+	Compression uint16
+
+	// PhotometricInterpretation is the value of the PhotometricInterpretation
+	// tag (e.g. 2 for RGB). This is only set for uncompressed thumbnails.
+	PhotometricInterpretation uint16
+
+	// Data is the JPEG stream or, for uncompressed thumbnails, the strips
+	// concatenated in order.
+	Data []byte
+}
+
+// ThumbnailBytes returns the raw thumbnail from IFD1. JPEG thumbnails come
+// from the JPEGInterchangeFormat tags and uncompressed thumbnails are
+// assembled from their strips. If there is no Compression tag, the thumbnail
+// is assumed to be JPEG. ErrUnsupportedThumbnailFormat is returned
+// (unwrapped) for any other compression, ErrNoThumbnail if there is no
+// thumbnail, and exifcommon.ErrNotEnoughData if the strips run past the end of
+// the data.
+func (index IfdIndex) ThumbnailBytes() (td ThumbnailData, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	thumbnailIfd, compression, err := index.thumbnailIfd()
+	if err != nil {
+		if err == ErrNoThumbnail {
+			return td, err
+		}
+
+		log.Panic(err)
+	}
+
+	td.Compression = compression
+
+	switch td.Compression {
+	case compressionJpeg, compressionJpegOld:
+		td.Data, err = thumbnailIfd.Thumbnail()
+		if err != nil {
+			if err == ErrNoThumbnail {
+				return td, err
+			}
+
+			log.Panic(err)
+		}
+
+	case compressionUncompressed:
+		photometricIte, found := thumbnailIfd.EntryByTagId(photometricInterpretationTagId)
+		if found == false {
+			log.Panicf("uncompressed thumbnail has no PhotometricInterpretation tag")
+		}
+
+		td.PhotometricInterpretation, err = thumbnailShort(photometricIte)
+		log.PanicIf(err)
+
+		td.Data, err = thumbnailStrips(thumbnailIfd)
+		if err != nil {
+			if err == ErrNoThumbnail || err == exifcommon.ErrNotEnoughData {
+				return td, err
+			}
+
+			log.Panic(err)
+		}
+
+	default:
+		return td, ErrUnsupportedThumbnailFormat
+	}
+
+	return td, nil
+}
+
+// ThumbnailBytes returns the raw thumbnail from IFD1. Only the root chain is
+// parsed. See `IfdIndex.ThumbnailBytes()`.
+func (ie *IfdEnumerate) ThumbnailBytes() (td ThumbnailData, err error) {
+	index, err := ie.getHeaderIndex(true)
+	if err != nil {
+		return td, err
+	}
+
+	return index.ThumbnailBytes()
+}
+
+// thumbnailIfd returns the thumbnail IFD (IFD1) along with its compression.
+// If there is no Compression tag, the thumbnail is assumed to be JPEG.
+// ErrNoThumbnail is returned (unwrapped) if there is no IFD1.
+func (index IfdIndex) thumbnailIfd() (thumbnailIfd *Ifd, compression uint16, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	thumbnailIfd, found := index.Lookup[ThumbnailFqIfdPath]
+	if found == false {
+		return nil, 0, ErrNoThumbnail
+	}
+
+	compression = compressionJpeg
+
+	if compressionIte, found := thumbnailIfd.EntryByTagId(compressionTagId); found == true {
+		compression, err = thumbnailShort(compressionIte)
+		log.PanicIf(err)
+	}
+
+	return thumbnailIfd, compression, nil
+}
+
+// ThumbnailDimensions returns the width and height of the thumbnail as
+// declared by the ImageWidth and ImageLength tags in IFD1 (either may be a
+// SHORT or a LONG). The thumbnail itself isn't read. ErrNoThumbnail is
+// returned (unwrapped) if there is no IFD1 or if either tag is missing.
+func (index IfdIndex) ThumbnailDimensions() (width, height uint32, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	thumbnailIfd, _, err := index.thumbnailIfd()
+	if err != nil {
+		if err == ErrNoThumbnail {
+			return 0, 0, err
+		}
+
+		log.Panic(err)
+	}
+
+	widthIte, found := thumbnailIfd.EntryByTagId(imageWidthTagId)
+	if found == false {
+		return 0, 0, ErrNoThumbnail
+	}
+
+	heightIte, found := thumbnailIfd.EntryByTagId(imageLengthTagId)
+	if found == false {
+		return 0, 0, ErrNoThumbnail
+	}
+
+	width, err = thumbnailLong(widthIte)
+	log.PanicIf(err)
+
+	height, err = thumbnailLong(heightIte)
+	log.PanicIf(err)
+
+	return width, height, nil
+}
+
+// ThumbnailDimensions returns the width and height of the thumbnail as
+// declared in IFD1. Only the root chain is parsed. See
+// `IfdIndex.ThumbnailDimensions()`.
+func (ie *IfdEnumerate) ThumbnailDimensions() (width, height uint32, err error) {
+	index, err := ie.getHeaderIndex(true)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return index.ThumbnailDimensions()
+}
+
+// thumbnailShort returns the value of a tag that must be a single SHORT.
+func thumbnailShort(ite *IfdTagEntry) (value uint16, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	valueRaw, err := ite.Value()
+	log.PanicIf(err)
+
+	values, ok := valueRaw.([]uint16)
+	if ok == false || len(values) != 1 {
+		log.Panicf("tag (0x%04x) is not a single SHORT: [%s] (%d)", ite.TagId(), ite.TagType(), ite.UnitCount())
+	}
+
+	return values[0], nil
+}
+
+// thumbnailLongs returns the value of a tag that may be a list of either
+// SHORTs or LONGs (like the strip tags).
+func thumbnailLongs(ite *IfdTagEntry) (values []uint32, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	valueRaw, err := ite.Value()
+	log.PanicIf(err)
+
+	switch typedValues := valueRaw.(type) {
+	case []uint16:
+		values = make([]uint32, len(typedValues))
+		for i, value := range typedValues {
+			values[i] = uint32(value)
+		}
+
+	case []uint32:
+		values = typedValues
+
+	default:
+		log.Panicf("tag (0x%04x) is not a list of SHORTs or LONGs: [%s]", ite.TagId(), ite.TagType())
+	}
+
+	return values, nil
+}
+
+// thumbnailLong returns the value of a tag that must be a single SHORT or
+// LONG.
+func thumbnailLong(ite *IfdTagEntry) (value uint32, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	values, err := thumbnailLongs(ite)
+	log.PanicIf(err)
+
+	if len(values) != 1 {
+		log.Panicf("tag (0x%04x) is not a single SHORT or LONG: [%s] (%d)", ite.TagId(), ite.TagType(), ite.UnitCount())
+	}
+
+	return values[0], nil
+}
+
+// thumbnailStrips reads the strips of an uncompressed thumbnail and returns
+// them as one contiguous buffer. ErrNoThumbnail is returned if the strip tags
+// are missing and exifcommon.ErrNotEnoughData if a strip runs past the end of
+// the data.
+func thumbnailStrips(thumbnailIfd *Ifd) (data []byte, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	offsetsIte, found := thumbnailIfd.EntryByTagId(stripOffsetsTagId)
+	if found == false {
+		return nil, ErrNoThumbnail
+	}
+
+	byteCountsIte, found := thumbnailIfd.EntryByTagId(stripByteCountsTagId)
+	if found == false {
+		return nil, ErrNoThumbnail
+	}
+
+	offsets, err := thumbnailLongs(offsetsIte)
+	log.PanicIf(err)
+
+	byteCounts, err := thumbnailLongs(byteCountsIte)
+	log.PanicIf(err)
+
+	if len(offsets) != len(byteCounts) {
+		log.Panicf("strip offset count (%d) does not match strip byte-count count (%d)", len(offsets), len(byteCounts))
+	}
+
+	rs := offsetsIte.rs
+
+	dataLength, err := rs.Seek(0, io.SeekEnd)
+	log.PanicIf(err)
+
+	// The counts come from the data, so they're checked before anything is
+	// allocated. Together, the strips can't be larger than the data.
+
+	total := int64(0)
+	for i, byteCount := range byteCounts {
+		if int64(offsets[i])+int64(byteCount) > dataLength {
+			return nil, exifcommon.ErrNotEnoughData
+		}
+
+		total += int64(byteCount)
+		if total > dataLength {
+			return nil, exifcommon.ErrNotEnoughData
+		}
+	}
+
+	data = make([]byte, total)
+
+	position := int64(0)
+	for i, offset := range offsets {
+		_, err := rs.Seek(int64(offset), io.SeekStart)
+		log.PanicIf(err)
+
+		_, err = io.ReadFull(rs, data[position:position+int64(byteCounts[i])])
+		log.PanicIf(err)
+
+		position += int64(byteCounts[i])
+	}
+
+	return data, nil
+}
+
+// Thumbnail returns the decoded thumbnail from IFD1. Only JPEG thumbnails
+// are supported. ErrUnsupportedThumbnailFormat is returned (unwrapped) if the
+// Compression tag in IFD1 says that it is something else (use
+// ThumbnailBytes() to get uncompressed thumbnails), and ErrNoThumbnail if
+// there is no thumbnail.
+func (index IfdIndex) Thumbnail() (img image.Image, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	thumbnailIfd, compression, err := index.thumbnailIfd()
+	if err != nil {
+		if err == ErrNoThumbnail {
+			return nil, err
+		}
+
+		log.Panic(err)
+	}
+
+	if compression != compressionJpeg && compression != compressionJpegOld {
+		return nil, ErrUnsupportedThumbnailFormat
+	}
+
+	thumbnailData, err := thumbnailIfd.Thumbnail()
+	if err != nil {
+		if err == ErrNoThumbnail {
+			return nil, err
+		}
+
+		log.Panic(err)
+	}
+
+	img, err = jpeg.Decode(bytes.NewReader(thumbnailData))
+	log.PanicIf(err)
+
+	return img, nil
+}
+
+// Thumbnail returns the decoded thumbnail from IFD1. Only the root chain is
+// parsed. See `IfdIndex.Thumbnail()`.
+func (ie *IfdEnumerate) Thumbnail() (img image.Image, err error) {
+	index, err := ie.getHeaderIndex(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return index.Thumbnail()
+}
@@ -0,0 +1,278 @@
+package exif
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/mschilli/go-exif/v3/common"
+)
+
+func TestIfdEnumerate_Thumbnail(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, _, err := NewIfdEnumerateWithBytes(im, ti, getTestExifData())
+	log.PanicIf(err)
+
+	img, err := ie.Thumbnail()
+	log.PanicIf(err)
+
+	bounds := img.Bounds()
+	if bounds.Dx() == 0 || bounds.Dy() == 0 {
+		t.Fatalf("Thumbnail is empty: %v", bounds)
+	}
+}
+
+func TestIfdEnumerate_Thumbnail__Uncompressed(t *testing.T) {
+	original := getTestExifData()
+
+	exifData := make([]byte, len(original))
+	copy(exifData, original)
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	_, index, err := Collect(im, ti, exifData)
+	log.PanicIf(err)
+
+	thumbnailIfd := index.Lookup[ThumbnailFqIfdPath]
+
+	ite, found := thumbnailIfd.EntryByTagId(compressionTagId)
+	if found == false {
+		t.Fatalf("Compression tag not found.")
+	}
+
+	// Set the (embedded) compression to uncompressed.
+	valueOffset := ExifAddressableAreaStart + thumbnailIfd.Offset() + 2 + uint32(ite.tagIndex)*12 + 8
+	thumbnailIfd.ByteOrder().PutUint16(exifData[valueOffset:], 1)
+
+	ie, _, err := NewIfdEnumerateWithBytes(im, ti, exifData)
+	log.PanicIf(err)
+
+	_, err = ie.Thumbnail()
+	if err != ErrUnsupportedThumbnailFormat {
+		t.Fatalf("Expected ErrUnsupportedThumbnailFormat: %v", err)
+	}
+}
+
+func TestIfdEnumerate_Thumbnail__NoThumbnail(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, _, err := NewIfdEnumerateWithBytes(im, ti, getTestOrientationExifData(1))
+	log.PanicIf(err)
+
+	_, err = ie.Thumbnail()
+	if err != ErrNoThumbnail {
+		t.Fatalf("Expected ErrNoThumbnail: %v", err)
+	}
+}
+
+func TestIfdEnumerate_ThumbnailBytes__Jpeg(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, _, err := NewIfdEnumerateWithBytes(im, ti, getTestExifData())
+	log.PanicIf(err)
+
+	td, err := ie.ThumbnailBytes()
+	log.PanicIf(err)
+
+	if td.Compression != compressionJpegOld {
+		t.Fatalf("Compression not correct: (%d)", td.Compression)
+	} else if len(td.Data) != 21491 {
+		t.Fatalf("Thumbnail size not correct: (%d)", len(td.Data))
+	} else if td.Data[0] != 0xff || td.Data[1] != 0xd8 {
+		t.Fatalf("Thumbnail is not a JPEG: %x", td.Data[:2])
+	}
+}
+
+func TestIfdEnumerate_ThumbnailBytes__Uncompressed(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, _, err := NewIfdEnumerateWithBytes(im, ti, getTestStripThumbnailExifData(compressionUncompressed))
+	log.PanicIf(err)
+
+	td, err := ie.ThumbnailBytes()
+	log.PanicIf(err)
+
+	expected := []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}
+
+	if td.Compression != compressionUncompressed {
+		t.Fatalf("Compression not correct: (%d)", td.Compression)
+	} else if td.PhotometricInterpretation != 2 {
+		t.Fatalf("PhotometricInterpretation not correct: (%d)", td.PhotometricInterpretation)
+	} else if bytes.Equal(td.Data, expected) != true {
+		t.Fatalf("Strips not assembled correctly: %v", td.Data)
+	}
+
+	_, err = ie.Thumbnail()
+	if err != ErrUnsupportedThumbnailFormat {
+		t.Fatalf("Expected ErrUnsupportedThumbnailFormat from Thumbnail(): %v", err)
+	}
+}
+
+func TestIfdEnumerate_ThumbnailBytes__OversizedStrips(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	byteOrder := exifcommon.TestDefaultByteOrder
+
+	// Make StripByteCounts two LONGs (at 100) that are far larger than the
+	// data.
+	exifData := make([]byte, 108)
+	copy(exifData, getTestStripThumbnailExifData(compressionUncompressed))
+
+	putTestIfdEntry(exifData, byteOrder, 64, stripByteCountsTagId, exifcommon.TypeLong, 2)
+	byteOrder.PutUint32(exifData[72:], 100)
+
+	byteOrder.PutUint32(exifData[100:], 0xffffff00)
+	byteOrder.PutUint32(exifData[104:], 0xffffff00)
+
+	ie, _, err := NewIfdEnumerateWithBytes(im, ti, exifData)
+	log.PanicIf(err)
+
+	_, err = ie.ThumbnailBytes()
+	if err != exifcommon.ErrNotEnoughData {
+		t.Fatalf("Expected ErrNotEnoughData for oversized strips: %v", err)
+	}
+
+	// Each strip is within the data, but together they're larger than it.
+	byteOrder.PutUint32(exifData[80:], 0)
+	byteOrder.PutUint32(exifData[84:], 0)
+	byteOrder.PutUint32(exifData[100:], 100)
+	byteOrder.PutUint32(exifData[104:], 100)
+
+	ie, _, err = NewIfdEnumerateWithBytes(im, ti, exifData)
+	log.PanicIf(err)
+
+	_, err = ie.ThumbnailBytes()
+	if err != exifcommon.ErrNotEnoughData {
+		t.Fatalf("Expected ErrNotEnoughData for strips larger than the data: %v", err)
+	}
+}
+
+func TestIfdEnumerate_ThumbnailBytes__Unsupported(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	// LZW
+	ie, _, err := NewIfdEnumerateWithBytes(im, ti, getTestStripThumbnailExifData(5))
+	log.PanicIf(err)
+
+	_, err = ie.ThumbnailBytes()
+	if err != ErrUnsupportedThumbnailFormat {
+		t.Fatalf("Expected ErrUnsupportedThumbnailFormat: %v", err)
+	}
+}
+
+func TestIfdEnumerate_ThumbnailBytes__NoThumbnail(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, _, err := NewIfdEnumerateWithBytes(im, ti, getTestOrientationExifData(1))
+	log.PanicIf(err)
+
+	_, err = ie.ThumbnailBytes()
+	if err != ErrNoThumbnail {
+		t.Fatalf("Expected ErrNoThumbnail: %v", err)
+	}
+}
+
+func TestIfdEnumerate_ThumbnailDimensions(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ib := NewIfdBuilder(im, ti, exifcommon.IfdStandardIfdIdentity, exifcommon.TestDefaultByteOrder)
+
+	err = ib.AddStandardWithName("Orientation", []uint16{1})
+	log.PanicIf(err)
+
+	// IFD1, with a SHORT width and a LONG height.
+
+	nextIb := NewIfdBuilder(im, ti, exifcommon.IfdStandardIfdIdentity, exifcommon.TestDefaultByteOrder)
+
+	byteOrder := exifcommon.TestDefaultByteOrder
+
+	widthBytes := make([]byte, 2)
+	byteOrder.PutUint16(widthBytes, 160)
+
+	widthValue := NewIfdBuilderTagValueFromBytes(widthBytes)
+	widthBt := NewBuilderTag(exifcommon.IfdStandardIfdIdentity.UnindexedString(), imageWidthTagId, exifcommon.TypeShort, widthValue, byteOrder)
+
+	err = nextIb.Add(widthBt)
+	log.PanicIf(err)
+
+	err = nextIb.AddStandard(imageLengthTagId, []uint32{120})
+	log.PanicIf(err)
+
+	err = ib.SetNextIb(nextIb)
+	log.PanicIf(err)
+
+	ibe := NewIfdByteEncoder()
+
+	exifData, err := ibe.EncodeToExif(ib)
+	log.PanicIf(err)
+
+	ie, _, err := NewIfdEnumerateWithBytes(im, ti, exifData)
+	log.PanicIf(err)
+
+	width, height, err := ie.ThumbnailDimensions()
+	log.PanicIf(err)
+
+	if width != 160 || height != 120 {
+		t.Fatalf("Dimensions not correct: (%d) x (%d)", width, height)
+	}
+}
+
+func TestIfdEnumerate_ThumbnailDimensions__MissingTags(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	// IFD1 exists but has no dimension tags.
+	ie, _, err := NewIfdEnumerateWithBytes(im, ti, getTestStripThumbnailExifData(compressionUncompressed))
+	log.PanicIf(err)
+
+	_, _, err = ie.ThumbnailDimensions()
+	if err != ErrNoThumbnail {
+		t.Fatalf("Expected ErrNoThumbnail: %v", err)
+	}
+}
+
+func TestIfdEnumerate_ThumbnailDimensions__NoThumbnail(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, _, err := NewIfdEnumerateWithBytes(im, ti, getTestOrientationExifData(1))
+	log.PanicIf(err)
+
+	_, _, err = ie.ThumbnailDimensions()
+	if err != ErrNoThumbnail {
+		t.Fatalf("Expected ErrNoThumbnail: %v", err)
+	}
+}
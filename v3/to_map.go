@@ -0,0 +1,89 @@
+package exif
+
+import (
+	"fmt"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/mschilli/go-exif/v3/common"
+	"github.com/mschilli/go-exif/v3/undefined"
+)
+
+// ToMap returns every tag in the tree in one flat map, keyed by the IFD's
+// `Path()` and the tag-name (e.g. "IFD/Make" or "IFD/Exif/ISOSpeedRatings").
+// Tags without a name are keyed by their hex tag-ID instead (e.g.
+// "IFD/0xc4a5"). If the same key occurs more than once, the later occurrences
+// get an index suffix (e.g. "IFD/Make[1]"). Values are decoded. Unknown
+// UNDEFINED tags get a placeholder value and tags whose values can't be read
+// are skipped with a warning.
+func (index IfdIndex) ToMap() (tags map[string]interface{}, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	tags = make(map[string]interface{})
+
+	if index.RootIfd == nil {
+		return tags, nil
+	}
+
+	visitor := func(ifd *Ifd, depth int) (err error) {
+		ifdPath := ifd.Path()
+
+		for _, ite := range ifd.Entries() {
+			value, err := ite.Value()
+			if err != nil {
+				if err == exifcommon.ErrUnhandledUndefinedTypedTag {
+					value = exifundefined.UnparseableUnknownTagValuePlaceholder
+				} else if err == exifcommon.ErrNotEnoughData || log.Is(err, exifcommon.ErrParseFail) == true {
+					ifdEnumerateLogger.Warningf(nil,
+						"Could not read value for tag [%s] (0x%04x) [%s]. It will be skipped.",
+						ifdPath, ite.TagId(), ite.TagName())
+
+					continue
+				} else {
+					log.Panic(err)
+				}
+			}
+
+			tagName := ite.TagName()
+			if tagName == "" {
+				tagName = fmt.Sprintf("0x%04x", ite.TagId())
+			}
+
+			key := fmt.Sprintf("%s/%s", ifdPath, tagName)
+
+			if _, found := tags[key]; found == true {
+				for i := 1; ; i++ {
+					indexedKey := fmt.Sprintf("%s[%d]", key, i)
+					if _, found := tags[indexedKey]; found == false {
+						key = indexedKey
+						break
+					}
+				}
+			}
+
+			tags[key] = value
+		}
+
+		return nil
+	}
+
+	err = index.RootIfd.Walk(visitor)
+	log.PanicIf(err)
+
+	return tags, nil
+}
+
+// ToMap returns all of the tags in the tree in one flat map. See
+// `IfdIndex.ToMap()`.
+func (ie *IfdEnumerate) ToMap() (tags map[string]interface{}, err error) {
+	index, err := ie.getHeaderIndex(false)
+	if err != nil {
+		return nil, err
+	}
+
+	return index.ToMap()
+}
@@ -0,0 +1,84 @@
+package exif
+
+import (
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/mschilli/go-exif/v3/common"
+)
+
+func TestIfdEnumerate_ToMap(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, _, err := NewIfdEnumerateWithBytes(im, ti, getTestExifData())
+	log.PanicIf(err)
+
+	tags, err := ie.ToMap()
+	log.PanicIf(err)
+
+	if len(tags) != 59 {
+		t.Fatalf("Tag count not correct: (%d)", len(tags))
+	} else if tags["IFD/Model"] != "Canon EOS 5D Mark III" {
+		t.Fatalf("Model not correct: %v", tags["IFD/Model"])
+	} else if tags["IFD/Exif/ISOSpeedRatings"].([]uint16)[0] != 1600 {
+		t.Fatalf("ISOSpeedRatings not correct: %v", tags["IFD/Exif/ISOSpeedRatings"])
+	} else if tags["IFD/Exif/Iop/InteroperabilityIndex"] != "R98" {
+		t.Fatalf("InteroperabilityIndex not correct: %v", tags["IFD/Exif/Iop/InteroperabilityIndex"])
+	} else if _, found := tags["IFD1/Compression"]; found == false {
+		t.Fatalf("Thumbnail IFD tags not included.")
+	}
+}
+
+func TestIfdIndex_ToMap__Duplicates(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	_, index, err := Collect(im, ti, getTestManyTagsExifData(3))
+	log.PanicIf(err)
+
+	tags, err := index.ToMap()
+	log.PanicIf(err)
+
+	expected := map[string]uint16{
+		"IFD/ImageWidth":    0,
+		"IFD/ImageWidth[1]": 1,
+		"IFD/ImageWidth[2]": 2,
+	}
+
+	if len(tags) != len(expected) {
+		t.Fatalf("Keys not correct: %v", tags)
+	}
+
+	for key, value := range expected {
+		if tags[key].([]uint16)[0] != value {
+			t.Fatalf("Value for [%s] not correct: %v", key, tags[key])
+		}
+	}
+}
+
+func TestIfdIndex_ToMap__NoTagName(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	_, index, err := Collect(im, ti, getTestManyTagsExifData(1))
+	log.PanicIf(err)
+
+	// Unknown tags are normally skipped while parsing, so simulate one that
+	// made it through without a name.
+	index.RootIfd.Entries()[0].setTagName("")
+
+	tags, err := index.ToMap()
+	log.PanicIf(err)
+
+	if _, found := tags["IFD/0x0100"]; found == false {
+		t.Fatalf("Tag without a name not keyed by its ID: %v", tags)
+	}
+}
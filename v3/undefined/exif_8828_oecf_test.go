@@ -8,7 +8,7 @@ import (
 	"github.com/dsoprea/go-logging"
 	"github.com/dsoprea/go-utility/v2/filesystem"
 
-	"github.com/dsoprea/go-exif/v3/common"
+	"github.com/mschilli/go-exif/v3/common"
 )
 
 func TestTag8828Oecf_String(t *testing.T) {
@@ -7,7 +7,7 @@ import (
 
 	log "github.com/dsoprea/go-logging"
 
-	exifcommon "github.com/dsoprea/go-exif/v3/common"
+	exifcommon "github.com/mschilli/go-exif/v3/common"
 )
 
 var PrintImageMatchingHeader = []byte{0x50, 0x72, 0x69, 0x6e, 0x74, 0x49, 0x4d, 0x00}
@@ -8,7 +8,7 @@ import (
 	log "github.com/dsoprea/go-logging"
 	rifs "github.com/dsoprea/go-utility/v2/filesystem"
 
-	exifcommon "github.com/dsoprea/go-exif/v3/common"
+	exifcommon "github.com/mschilli/go-exif/v3/common"
 )
 
 func TestTagC4A5PrintImageMatching_String(t *testing.T) {
@@ -0,0 +1,154 @@
+package exif
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/mschilli/go-exif/v3/common"
+)
+
+const (
+	// tagTypeIfd is the TIFF IFD type. It is stored like a LONG, but each
+	// value is the offset of an IFD. It isn't one of the EXIF types, so these
+	// tags are otherwise dropped.
+	tagTypeIfd = exifcommon.TagTypePrimitive(13)
+)
+
+// UnknownIfdName returns the placeholder name given to an IFD that the given
+// tag points to but that we don't know (e.g. "Unknown<0xc634>"). See
+// `CollectOptions.KeepUnknownIfds`.
+func UnknownIfdName(tagId uint16) string {
+	return fmt.Sprintf("Unknown<0x%04x>", tagId)
+}
+
+// unknownIfdQueue returns a QueuedIfd for each IFD that a tag with the TIFF
+// IFD type (or the BigTIFF IFD8 type) in `ifd` points to. These tags were
+// dropped when the IFD was parsed, so the entries are read again from the
+// stream. Lists of offsets that can't be read are logged and skipped.
+func (ie *IfdEnumerate) unknownIfdQueue(ifd *Ifd, depth int) (queue []QueuedIfd, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	bp, err := ie.getByteParser(ifd.offset)
+	log.PanicIf(err)
+
+	tagCount, err := bp.getTagCount()
+	log.PanicIf(err)
+
+	parentIfdTag := ifd.ifdIdentity.IfdTag()
+	queue = make([]QueuedIfd, 0)
+
+	for i := 0; i < tagCount; i++ {
+		tagId, err := bp.getUint16()
+		log.PanicIf(err)
+
+		tagTypeRaw, err := bp.getUint16()
+		log.PanicIf(err)
+
+		tagType := exifcommon.TagTypePrimitive(tagTypeRaw)
+		isIfd := tagType == tagTypeIfd || (tagType == exifcommon.TypeIfd8 && bp.isBigTiff == true)
+
+		if tagType == tagTypeIfd {
+			tagType = exifcommon.TypeLong
+		}
+
+		unitCount, valueOffset, _, err := bp.getUnitCountAndValueOffset(tagType)
+		if err != nil && err != ErrBigTiff {
+			log.Panic(err)
+		}
+
+		if isIfd == false {
+			continue
+		} else if err == ErrBigTiff {
+			ifdEnumerateLogger.Warningf(nil, "IFDs for tag (0x%04x) in IFD [%s] are beyond the first 4 GiB. Skipping.", tagId, ifd.ifdIdentity)
+			continue
+		} else if unitCount == 0 {
+			continue
+		}
+
+		offsets := []uint32{valueOffset}
+
+		if unitCount > 1 {
+			offsets, err = ie.unknownIfdOffsets(valueOffset, unitCount, tagType)
+			if err != nil {
+				if err == ErrOffsetInvalid || err == ErrTruncatedData {
+					ifdEnumerateLogger.Warningf(nil, "Offsets of the IFDs for tag (0x%04x) in IFD [%s] are beyond the end of the data. Skipping.", tagId, ifd.ifdIdentity)
+					continue
+				}
+
+				log.Panic(err)
+			}
+		}
+
+		unknownIfdTag := exifcommon.NewIfdTag(&parentIfdTag, tagId, UnknownIfdName(tagId))
+
+		for j, offset := range offsets {
+			qi := QueuedIfd{
+				IfdIdentity:    ifd.ifdIdentity.NewChild(unknownIfdTag, j),
+				Offset:         offset,
+				Parent:         ifd,
+				ParentTagIndex: i,
+				Depth:          depth + 1,
+
+				isUnknown: true,
+			}
+
+			queue = append(queue, qi)
+		}
+	}
+
+	return queue, nil
+}
+
+// unknownIfdOffsets reads a list of IFD offsets that is stored outside of its
+// entry. They are LONGs unless `tagType` is IFD8. ErrOffsetInvalid or
+// ErrTruncatedData is returned (unwrapped) if it isn't within the data.
+func (ie *IfdEnumerate) unknownIfdOffsets(valueOffset uint32, unitCount uint32, tagType exifcommon.TagTypePrimitive) (offsets []uint32, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	bp, err := ie.getByteParser(valueOffset)
+	if err != nil {
+		if err == ErrOffsetInvalid {
+			return nil, err
+		}
+
+		log.Panic(err)
+	}
+
+	if int64(bp.CurrentOffset())+int64(unitCount)*int64(tagType.Size()) > bp.dataLength {
+		return nil, ErrTruncatedData
+	}
+
+	offsets = make([]uint32, 0, unitCount)
+	for i := uint32(0); i < unitCount; i++ {
+		var offset uint32
+
+		if tagType == exifcommon.TypeIfd8 {
+			offset64, err := bp.getUint64()
+			log.PanicIf(err)
+
+			if offset64 > math.MaxUint32 {
+				ifdEnumerateLogger.Warningf(nil, "IFD at offset (0x%016x) is beyond the first 4 GiB. Skipping.", offset64)
+				continue
+			}
+
+			offset = uint32(offset64)
+		} else {
+			offset, err = bp.getUint32()
+			log.PanicIf(err)
+		}
+
+		offsets = append(offsets, offset)
+	}
+
+	return offsets, nil
+}
@@ -0,0 +1,341 @@
+package exif
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/mschilli/go-exif/v3/common"
+)
+
+// getTestUnknownIfdExifData returns EXIF data whose IFD0 has a tag with the
+// TIFF IFD type (0xc634) that points to an IFD with a SHORT and an UNDEFINED
+// tag.
+func getTestUnknownIfdExifData() []byte {
+	byteOrder := exifcommon.TestDefaultByteOrder
+
+	exifData := make([]byte, 74)
+	copy(exifData, ExifBigEndianSignature[:])
+	byteOrder.PutUint32(exifData[4:], ExifDefaultFirstIfdOffset)
+
+	// IFD0 (at 8).
+	byteOrder.PutUint16(exifData[8:], 2)
+
+	putTestIfdEntry(exifData, byteOrder, 10, orientationTagId, exifcommon.TypeShort, 1)
+	byteOrder.PutUint16(exifData[18:], 1)
+
+	putTestIfdEntry(exifData, byteOrder, 22, 0xc634, tagTypeIfd, 1)
+	byteOrder.PutUint32(exifData[30:], 38)
+
+	byteOrder.PutUint32(exifData[34:], 0)
+
+	// The unknown IFD (at 38).
+	byteOrder.PutUint16(exifData[38:], 2)
+
+	putTestIfdEntry(exifData, byteOrder, 40, 0x0001, exifcommon.TypeShort, 1)
+	byteOrder.PutUint16(exifData[48:], 0x1234)
+
+	putTestIfdEntry(exifData, byteOrder, 52, 0x0002, exifcommon.TypeUndefined, 6)
+	byteOrder.PutUint32(exifData[60:], 68)
+
+	byteOrder.PutUint32(exifData[64:], 0)
+
+	// The UNDEFINED value (at 68).
+	copy(exifData[68:], []byte{1, 2, 3, 4, 5, 6})
+
+	return exifData
+}
+
+func TestIfdEnumerate_CollectWithOptions__KeepUnknownIfds(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, eh, err := NewIfdEnumerateWithBytes(im, ti, getTestUnknownIfdExifData())
+	log.PanicIf(err)
+
+	co := &CollectOptions{
+		KeepUnknownIfds: true,
+	}
+
+	index, err := ie.CollectWithOptions(eh.FirstIfdOffset, co)
+	log.PanicIf(err)
+
+	unknownIfd, found := index.Lookup["IFD/Unknown<0xc634>"]
+	if found == false {
+		t.Fatalf("Unknown IFD not collected: %v", index.Lookup)
+	} else if unknownIfd.parentIfd != index.RootIfd || unknownIfd.ParentTagIndex() != 1 {
+		t.Fatalf("Unknown IFD is not a child of the root IFD.")
+	} else if reflect.DeepEqual(index.RootIfd.Children(), []*Ifd{unknownIfd}) == false {
+		t.Fatalf("Root IFD children not correct: %v", index.RootIfd.Children())
+	}
+
+	entries := unknownIfd.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("Unknown IFD entries not correct: (%d)", len(entries))
+	}
+
+	shortIte := entries[0]
+	undefinedIte := entries[1]
+
+	if shortIte.IsRawOnly() != true || undefinedIte.IsRawOnly() != true {
+		t.Fatalf("Unknown IFD entries should be raw-only.")
+	} else if shortIte.TagName() != "" {
+		t.Fatalf("Unknown IFD entries should not have names: [%s]", shortIte.TagName())
+	}
+
+	value, err := shortIte.Value()
+	log.PanicIf(err)
+
+	if reflect.DeepEqual(value, []uint16{0x1234}) == false {
+		t.Fatalf("SHORT value not correct: %v", value)
+	}
+
+	_, err = undefinedIte.Value()
+	if err != exifcommon.ErrUnhandledUndefinedTypedTag {
+		t.Fatalf("Expected ErrUnhandledUndefinedTypedTag: %v", err)
+	}
+
+	rawBytes, err := undefinedIte.GetRawBytes()
+	log.PanicIf(err)
+
+	expected := []byte{1, 2, 3, 4, 5, 6}
+
+	if bytes.Equal(rawBytes, expected) != true {
+		t.Fatalf("Raw bytes not correct: %v", rawBytes)
+	} else if bytes.Equal(undefinedIte.RawValue(), expected) != true {
+		t.Fatalf("Raw value not captured: %v", undefinedIte.RawValue())
+	}
+
+	// The tags of the known IFDs are not affected.
+
+	orientationIte, found := index.RootIfd.EntryByTagId(orientationTagId)
+	if found == false {
+		t.Fatalf("Orientation tag not found.")
+	} else if orientationIte.IsRawOnly() != false {
+		t.Fatalf("Orientation tag should not be raw-only.")
+	} else if orientationIte.RawValue() != nil {
+		t.Fatalf("Orientation value should not have been captured.")
+	}
+}
+
+func TestIfdEnumerate_CollectWithOptions__UnknownIfdsDropped(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, eh, err := NewIfdEnumerateWithBytes(im, ti, getTestUnknownIfdExifData())
+	log.PanicIf(err)
+
+	index, err := ie.Collect(eh.FirstIfdOffset)
+	log.PanicIf(err)
+
+	if len(index.Ifds) != 1 {
+		t.Fatalf("Only the root IFD should have been collected: (%d)", len(index.Ifds))
+	} else if len(index.RootIfd.Entries()) != 1 {
+		t.Fatalf("The IFD-type tag should have been dropped: (%d)", len(index.RootIfd.Entries()))
+	}
+}
+
+func TestIfdEnumerate_CollectWithOptions__KeepUnknownIfds_OutOfRange(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	exifData := getTestUnknownIfdExifData()
+
+	// Point the tag past the end of the data.
+	exifcommon.TestDefaultByteOrder.PutUint32(exifData[30:], 0x1000)
+
+	ie, eh, err := NewIfdEnumerateWithBytes(im, ti, exifData)
+	log.PanicIf(err)
+
+	co := &CollectOptions{
+		KeepUnknownIfds: true,
+	}
+
+	index, err := ie.CollectWithOptions(eh.FirstIfdOffset, co)
+	log.PanicIf(err)
+
+	if len(index.Ifds) != 1 {
+		t.Fatalf("Only the root IFD should have been collected: (%d)", len(index.Ifds))
+	} else if len(index.SkippedIfds) != 1 || index.SkippedIfds[0].FqIfdPath != "IFD/Unknown<0xc634>" {
+		t.Fatalf("Unknown IFD not recorded as skipped: %v", index.SkippedIfds)
+	}
+}
+
+func TestUnknownIfdName(t *testing.T) {
+	if name := UnknownIfdName(0xc634); name != "Unknown<0xc634>" {
+		t.Fatalf("Name not correct: [%s]", name)
+	}
+}
+
+func TestIfd_DumpTags__KeepUnknownIfds(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, eh, err := NewIfdEnumerateWithBytes(im, ti, getTestUnknownIfdExifData())
+	log.PanicIf(err)
+
+	co := &CollectOptions{
+		KeepUnknownIfds: true,
+	}
+
+	index, err := ie.CollectWithOptions(eh.FirstIfdOffset, co)
+	log.PanicIf(err)
+
+	tags := index.RootIfd.DumpTags()
+
+	actual := make([]string, len(tags))
+	for i, ite := range tags {
+		actual[i] = ite.String()
+	}
+
+	// The tag that points to the unknown IFD was dropped, so the unknown IFD
+	// comes after the tags of its parent.
+	expected := []string{
+		"IfdTagEntry<TAG-IFD-PATH=[IFD] TAG-ID=(0x0112) TAG-TYPE=[SHORT] UNIT-COUNT=(1)>",
+		"IfdTagEntry<TAG-IFD-PATH=[IFD/Unknown<0xc634>] TAG-ID=(0x0001) TAG-TYPE=[SHORT] UNIT-COUNT=(1)>",
+		"IfdTagEntry<TAG-IFD-PATH=[IFD/Unknown<0xc634>] TAG-ID=(0x0002) TAG-TYPE=[UNDEFINED] UNIT-COUNT=(6)>",
+	}
+
+	if reflect.DeepEqual(actual, expected) == false {
+		t.Fatalf("Tags not correct:\n%v", actual)
+	}
+}
+
+func TestIfd_FprintIfdTree__KeepUnknownIfds(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, eh, err := NewIfdEnumerateWithBytes(im, ti, getTestUnknownIfdExifData())
+	log.PanicIf(err)
+
+	co := &CollectOptions{
+		KeepUnknownIfds: true,
+	}
+
+	index, err := ie.CollectWithOptions(eh.FirstIfdOffset, co)
+	log.PanicIf(err)
+
+	b := new(bytes.Buffer)
+	index.RootIfd.FprintIfdTree(b)
+
+	expected := ` Ifd<ID=(0) IFD-PATH=[IFD] INDEX=(0) COUNT=(1) OFF=(0x0008) CHILDREN=(1) PARENT=(0x0000) NEXT-IFD=(0x0000)>
+   Ifd<ID=(1) IFD-PATH=[IFD/Unknown<0xc634>] INDEX=(0) COUNT=(2) OFF=(0x0026) CHILDREN=(0) PARENT=(0x0008) NEXT-IFD=(0x0000)>
+`
+
+	if b.String() != expected {
+		t.Fatalf("IFD tree not correct:\n%s", b.String())
+	}
+}
+
+func TestIfd_DumpTree__KeepUnknownIfds(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, eh, err := NewIfdEnumerateWithBytes(im, ti, getTestUnknownIfdExifData())
+	log.PanicIf(err)
+
+	co := &CollectOptions{
+		KeepUnknownIfds: true,
+	}
+
+	index, err := ie.CollectWithOptions(eh.FirstIfdOffset, co)
+	log.PanicIf(err)
+
+	actual := index.RootIfd.DumpTree()
+
+	expected := []string{
+		"> IFD [ROOT]->[IFD]:(0) TOP",
+		"  - (0x0112)",
+		"  > IFD [IFD]->[IFD/Unknown<0xc634>]:(0) TOP",
+		"    - (0x0001)",
+		"    - (0x0002)",
+		"  < IFD [IFD]->[IFD/Unknown<0xc634>]:(0) BOTTOM",
+		"< IFD [ROOT]->[IFD]:(0) BOTTOM",
+	}
+
+	if reflect.DeepEqual(actual, expected) == false {
+		t.Fatalf("Tree not correct:\n%v", actual)
+	}
+}
+
+func TestIfd_FprintTagTree__KeepUnknownIfds(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, eh, err := NewIfdEnumerateWithBytes(im, ti, getTestUnknownIfdExifData())
+	log.PanicIf(err)
+
+	co := &CollectOptions{
+		KeepUnknownIfds: true,
+	}
+
+	index, err := ie.CollectWithOptions(eh.FirstIfdOffset, co)
+	log.PanicIf(err)
+
+	b := new(bytes.Buffer)
+	index.RootIfd.FprintTagTree(b, true)
+
+	expected := ` IFD: Ifd<ID=(0) IFD-PATH=[IFD] INDEX=(0) COUNT=(1) OFF=(0x0008) CHILDREN=(1) PARENT=(0x0000) NEXT-IFD=(0x0000)>
+ - TAG: IfdTagEntry<TAG-IFD-PATH=[IFD] TAG-ID=(0x0112) TAG-TYPE=[SHORT] UNIT-COUNT=(1)> NAME=[Orientation] VALUE=[[1]]
+   IFD: Ifd<ID=(1) IFD-PATH=[IFD/Unknown<0xc634>] INDEX=(0) COUNT=(2) OFF=(0x0026) CHILDREN=(0) PARENT=(0x0008) NEXT-IFD=(0x0000)>
+   - TAG: IfdTagEntry<TAG-IFD-PATH=[IFD/Unknown<0xc634>] TAG-ID=(0x0001) TAG-TYPE=[SHORT] UNIT-COUNT=(1)> NAME=[] VALUE=[[4660]]
+   - TAG: IfdTagEntry<TAG-IFD-PATH=[IFD/Unknown<0xc634>] TAG-ID=(0x0002) TAG-TYPE=[UNDEFINED] UNIT-COUNT=(6)> NAME=[] VALUE=[!UNKNOWN]
+`
+
+	if b.String() != expected {
+		t.Fatalf("Tag tree not correct:\n%s", b.String())
+	}
+}
+
+func TestIfd_EnumerateTagsRecursively__KeepUnknownIfds(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, eh, err := NewIfdEnumerateWithBytes(im, ti, getTestUnknownIfdExifData())
+	log.PanicIf(err)
+
+	co := &CollectOptions{
+		KeepUnknownIfds: true,
+	}
+
+	index, err := ie.CollectWithOptions(eh.FirstIfdOffset, co)
+	log.PanicIf(err)
+
+	actual := make([]string, 0)
+
+	visitor := func(ifd *Ifd, ite *IfdTagEntry) error {
+		actual = append(actual, ite.String())
+		return nil
+	}
+
+	err = index.RootIfd.EnumerateTagsRecursively(visitor)
+	log.PanicIf(err)
+
+	expected := []string{
+		"IfdTagEntry<TAG-IFD-PATH=[IFD] TAG-ID=(0x0112) TAG-TYPE=[SHORT] UNIT-COUNT=(1)>",
+		"IfdTagEntry<TAG-IFD-PATH=[IFD/Unknown<0xc634>] TAG-ID=(0x0001) TAG-TYPE=[SHORT] UNIT-COUNT=(1)>",
+		"IfdTagEntry<TAG-IFD-PATH=[IFD/Unknown<0xc634>] TAG-ID=(0x0002) TAG-TYPE=[UNDEFINED] UNIT-COUNT=(6)>",
+	}
+
+	if reflect.DeepEqual(actual, expected) == false {
+		t.Fatalf("Visited tags not correct:\n%v", actual)
+	}
+}
@@ -0,0 +1,123 @@
+package exif
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"unicode/utf16"
+
+	"encoding/binary"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/mschilli/go-exif/v3/common"
+)
+
+const (
+	// UserCommentTagId is the ID of the UserComment tag in the Exif IFD.
+	UserCommentTagId = 0x9286
+
+	userCommentCodeLength = 8
+)
+
+var (
+	// ErrUserCommentNotValid means that the UserComment tag was too short or
+	// had an unrecognized character-code.
+	ErrUserCommentNotValid = errors.New("user comment not valid")
+)
+
+var (
+	userCommentCodeAscii     = []byte{'A', 'S', 'C', 'I', 'I', 0, 0, 0}
+	userCommentCodeJis       = []byte{'J', 'I', 'S', 0, 0, 0, 0, 0}
+	userCommentCodeUnicode   = []byte{'U', 'N', 'I', 'C', 'O', 'D', 'E', 0}
+	userCommentCodeUndefined = []byte{0, 0, 0, 0, 0, 0, 0, 0}
+
+	userCommentBomBigEndian    = []byte{0xfe, 0xff}
+	userCommentBomLittleEndian = []byte{0xff, 0xfe}
+)
+
+// decodeUserComment decodes the raw bytes of a UserComment value. The first
+// eight bytes are the character-code. UNICODE text is decoded as UTF-16 in
+// the byte-order of its byte-order mark, if it starts with one, and otherwise
+// in the given byte-order. ASCII, JIS, and undefined text is returned as-is.
+// Trailing NULs and spaces are trimmed.
+func decodeUserComment(raw []byte, byteOrder binary.ByteOrder) (comment string, err error) {
+	if len(raw) < userCommentCodeLength {
+		return "", ErrUserCommentNotValid
+	}
+
+	code := raw[:userCommentCodeLength]
+	text := raw[userCommentCodeLength:]
+
+	if bytes.EqualFold(code, userCommentCodeUnicode) == true {
+		if bytes.HasPrefix(text, userCommentBomBigEndian) == true {
+			byteOrder = binary.BigEndian
+			text = text[len(userCommentBomBigEndian):]
+		} else if bytes.HasPrefix(text, userCommentBomLittleEndian) == true {
+			byteOrder = binary.LittleEndian
+			text = text[len(userCommentBomLittleEndian):]
+		}
+
+		units := make([]uint16, len(text)/2)
+		for i := range units {
+			units[i] = byteOrder.Uint16(text[i*2:])
+		}
+
+		comment = string(utf16.Decode(units))
+	} else if bytes.Equal(code, userCommentCodeAscii) == true ||
+		bytes.Equal(code, userCommentCodeJis) == true ||
+		bytes.Equal(code, userCommentCodeUndefined) == true {
+		comment = string(text)
+	} else {
+		ifdEnumerateLogger.Warningf(nil, "User-comment character-code not valid: %v", code)
+		return "", ErrUserCommentNotValid
+	}
+
+	return strings.TrimRight(comment, " \000"), nil
+}
+
+// UserComment returns the decoded UserComment tag from the Exif IFD.
+// ErrTagNotFound is returned (unwrapped) if there is no such tag,
+// ErrUserCommentNotValid if it can not be decoded, exifcommon.ErrNotEnoughData
+// if it runs past the end of the data, and ErrValueBudgetExceeded if reading
+// it would exceed the enumerator's limit.
+func (index IfdIndex) UserComment() (comment string, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	exifIfdPath := exifcommon.IfdExifStandardIfdIdentity.String()
+
+	ite, _, err := index.FindTag(exifIfdPath, UserCommentTagId)
+	if err != nil {
+		if err == ErrTagNotFound {
+			return "", err
+		}
+
+		log.Panic(err)
+	}
+
+	// Read the bytes directly since the registered decoder for this tag
+	// drops the text when it doesn't recognize the character-code.
+	raw, err := ite.effectiveValueBytes()
+	if err != nil {
+		if err == exifcommon.ErrNotEnoughData || err == ErrValueBudgetExceeded {
+			return "", err
+		}
+
+		log.Panic(err)
+	}
+
+	comment, err = decodeUserComment(raw, ite.ByteOrder())
+	if err != nil {
+		if err == ErrUserCommentNotValid {
+			return "", err
+		}
+
+		log.Panic(err)
+	}
+
+	return comment, nil
+}
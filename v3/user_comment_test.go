@@ -0,0 +1,143 @@
+package exif
+
+import (
+	"testing"
+
+	"encoding/binary"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/mschilli/go-exif/v3/common"
+)
+
+func TestDecodeUserComment__Ascii(t *testing.T) {
+	raw := append([]byte("ASCII\000\000\000"), []byte("a comment  \000\000")...)
+
+	comment, err := decodeUserComment(raw, binary.BigEndian)
+	log.PanicIf(err)
+
+	if comment != "a comment" {
+		t.Fatalf("Comment not correct: [%s]", comment)
+	}
+}
+
+func TestDecodeUserComment__Unicode(t *testing.T) {
+	raw := []byte("UNICODE\000")
+	raw = append(raw, 0x00, 'h', 0x00, 'i', 0x00, 0xe9, 0x00, 0x00)
+
+	comment, err := decodeUserComment(raw, binary.BigEndian)
+	log.PanicIf(err)
+
+	if comment != "hié" {
+		t.Fatalf("Comment not correct (big-endian): [%s]", comment)
+	}
+
+	raw = []byte("Unicode\000")
+	raw = append(raw, 'h', 0x00, 'i', 0x00)
+
+	comment, err = decodeUserComment(raw, binary.LittleEndian)
+	log.PanicIf(err)
+
+	if comment != "hi" {
+		t.Fatalf("Comment not correct (little-endian): [%s]", comment)
+	}
+}
+
+func TestDecodeUserComment__UnicodeByteOrderMark(t *testing.T) {
+	raw := []byte("UNICODE\000")
+	raw = append(raw, 0xff, 0xfe, 'h', 0x00, 'i', 0x00)
+
+	comment, err := decodeUserComment(raw, binary.BigEndian)
+	log.PanicIf(err)
+
+	if comment != "hi" {
+		t.Fatalf("Comment not correct (little-endian mark): [%s]", comment)
+	}
+
+	raw = []byte("UNICODE\000")
+	raw = append(raw, 0xfe, 0xff, 0x00, 'h', 0x00, 'i')
+
+	comment, err = decodeUserComment(raw, binary.LittleEndian)
+	log.PanicIf(err)
+
+	if comment != "hi" {
+		t.Fatalf("Comment not correct (big-endian mark): [%s]", comment)
+	}
+}
+
+func TestDecodeUserComment__Undefined(t *testing.T) {
+	raw := make([]byte, 16)
+
+	comment, err := decodeUserComment(raw, binary.BigEndian)
+	log.PanicIf(err)
+
+	if comment != "" {
+		t.Fatalf("Comment not correct: [%s]", comment)
+	}
+}
+
+func TestDecodeUserComment__NotValid(t *testing.T) {
+	_, err := decodeUserComment([]byte("BOGUS\000\000\000text"), binary.BigEndian)
+	if err != ErrUserCommentNotValid {
+		t.Fatalf("Expected invalid-comment error for unknown code: %v", err)
+	}
+
+	_, err = decodeUserComment([]byte("ASCII"), binary.BigEndian)
+	if err != ErrUserCommentNotValid {
+		t.Fatalf("Expected invalid-comment error for short value: %v", err)
+	}
+}
+
+func TestIfdIndex_UserComment(t *testing.T) {
+	rawExif, err := SearchFileAndExtractExif(getTestImageFilepath())
+	log.PanicIf(err)
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	_, index, err := Collect(im, ti, rawExif)
+	log.PanicIf(err)
+
+	// The fixture has an undefined-encoding, empty comment.
+	comment, err := index.UserComment()
+	log.PanicIf(err)
+
+	if comment != "" {
+		t.Fatalf("Comment not correct: [%s]", comment)
+	}
+}
+
+func TestIfdIndex_UserComment__NotEnoughData(t *testing.T) {
+	byteOrder := exifcommon.TestDefaultByteOrder
+
+	// IFD0 (at 8) points to the Exif IFD (at 26), whose UserComment claims
+	// far more bytes than there are.
+	exifData := make([]byte, 60)
+	copy(exifData, ExifBigEndianSignature[:])
+	byteOrder.PutUint32(exifData[4:], ExifDefaultFirstIfdOffset)
+
+	byteOrder.PutUint16(exifData[8:], 1)
+	putTestIfdEntry(exifData, byteOrder, 10, exifcommon.IfdExifStandardIfdIdentity.TagId(), exifcommon.TypeLong, 1)
+	byteOrder.PutUint32(exifData[18:], 26)
+	byteOrder.PutUint32(exifData[22:], 0)
+
+	byteOrder.PutUint16(exifData[26:], 1)
+	putTestIfdEntry(exifData, byteOrder, 28, UserCommentTagId, exifcommon.TypeUndefined, 0xf0000000)
+	byteOrder.PutUint32(exifData[36:], 44)
+	byteOrder.PutUint32(exifData[40:], 0)
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	_, index, err := Collect(im, ti, exifData)
+	log.PanicIf(err)
+
+	_, err = index.UserComment()
+	if err != exifcommon.ErrNotEnoughData {
+		t.Fatalf("Expected ErrNotEnoughData: %v", err)
+	}
+}
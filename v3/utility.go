@@ -8,12 +8,12 @@ import (
 	"github.com/dsoprea/go-logging"
 	"github.com/dsoprea/go-utility/v2/filesystem"
 
-	"github.com/dsoprea/go-exif/v3/common"
-	"github.com/dsoprea/go-exif/v3/undefined"
+	"github.com/mschilli/go-exif/v3/common"
+	"github.com/mschilli/go-exif/v3/undefined"
 )
 
 var (
-	utilityLogger = log.NewLogger("exif.utility")
+	utilityLogger = newPackageLogger("exif.utility")
 )
 
 // ExifTag is one simple representation of a tag in a flat list of all of them.
@@ -131,8 +131,11 @@ func getFlatExifDataUniversalSearchWithReadSeeker(rs io.ReadSeeker, so *ScanOpti
 		}
 	}()
 
-	headerData := make([]byte, ExifSignatureLength)
-	if _, err = io.ReadFull(rs, headerData); err != nil {
+	// A BigTIFF header is longer, but the data might be shorter than that.
+	headerData := make([]byte, BigTiffHeaderLength)
+
+	n, err := io.ReadAtLeast(rs, headerData, ExifSignatureLength)
+	if err != nil {
 		if err == io.EOF {
 			return nil, nil, err
 		}
@@ -140,7 +143,7 @@ func getFlatExifDataUniversalSearchWithReadSeeker(rs io.ReadSeeker, so *ScanOpti
 		log.Panic(err)
 	}
 
-	eh, err := ParseExifHeader(headerData)
+	eh, err := ParseExifHeader(headerData[:n])
 	log.PanicIf(err)
 
 	im, err := exifcommon.NewIfdMappingWithStandard()
@@ -0,0 +1,48 @@
+package exif
+
+import (
+	"sync"
+)
+
+// valueBudget is the limit on the total number of value bytes that the tags
+// of one enumerator may read. It is shared by all of those tags, which might
+// be read from several goroutines.
+type valueBudget struct {
+	lock sync.Mutex
+
+	maxValueBytes uint64
+	used          uint64
+}
+
+func newValueBudget(maxValueBytes uint64) *valueBudget {
+	return &valueBudget{
+		maxValueBytes: maxValueBytes,
+	}
+}
+
+// spend charges the size of the tag's value against the budget.
+// ErrValueBudgetExceeded is returned (unwrapped), and nothing is charged, if
+// that would cross the limit. A nil budget has no limit.
+func (vb *valueBudget) spend(ite *IfdTagEntry) (err error) {
+	if vb == nil {
+		return nil
+	}
+
+	if ite.tagType.IsValid() == false {
+		// This is caught when the value is read.
+		return nil
+	}
+
+	byteLength := valueByteLength(ite)
+
+	vb.lock.Lock()
+	defer vb.lock.Unlock()
+
+	if vb.used+byteLength > vb.maxValueBytes {
+		return ErrValueBudgetExceeded
+	}
+
+	vb.used += byteLength
+
+	return nil
+}
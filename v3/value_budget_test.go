@@ -0,0 +1,113 @@
+package exif
+
+import (
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/mschilli/go-exif/v3/common"
+)
+
+func TestIfdEnumerate_SetMaxValueBytes(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, eh, err := NewIfdEnumerateWithBytes(im, ti, getExifSimpleTestIbBytes())
+	log.PanicIf(err)
+
+	// The values are (11) + (2) + (4) + (8) bytes.
+	ie.SetMaxValueBytes(20)
+
+	index, err := ie.Collect(eh.FirstIfdOffset)
+	log.PanicIf(err)
+
+	entries := index.RootIfd.Entries()
+
+	for _, ite := range entries[:3] {
+		_, err := ite.Value()
+		log.PanicIf(err)
+	}
+
+	_, err = entries[3].Value()
+	if err != ErrValueBudgetExceeded {
+		t.Fatalf("Expected ErrValueBudgetExceeded: %v", err)
+	}
+
+	_, err = entries[3].GetRawBytes()
+	if err != ErrValueBudgetExceeded {
+		t.Fatalf("Expected ErrValueBudgetExceeded for raw bytes: %v", err)
+	}
+
+	// The failed reads weren't charged, so a smaller value still fits.
+	_, err = entries[1].Value()
+	log.PanicIf(err)
+
+	_, err = entries[1].Value()
+	if err != ErrValueBudgetExceeded {
+		t.Fatalf("Expected ErrValueBudgetExceeded after the budget was used up: %v", err)
+	}
+}
+
+func TestIfdEnumerate_SetMaxValueBytes__Unlimited(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, eh, err := NewIfdEnumerateWithBytes(im, ti, getExifSimpleTestIbBytes())
+	log.PanicIf(err)
+
+	ie.SetMaxValueBytes(1)
+	ie.SetMaxValueBytes(0)
+
+	index, err := ie.Collect(eh.FirstIfdOffset)
+	log.PanicIf(err)
+
+	for i := 0; i < 10; i++ {
+		for _, ite := range index.RootIfd.Entries() {
+			_, err := ite.Value()
+			log.PanicIf(err)
+		}
+	}
+}
+
+func TestIfdEnumerate_SetMaxValueBytes__CaptureValues(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, eh, err := NewIfdEnumerateWithBytes(im, ti, getExifSimpleTestIbBytes())
+	log.PanicIf(err)
+
+	ie.SetMaxValueBytes(20)
+
+	co := &CollectOptions{
+		CaptureValues: true,
+	}
+
+	_, err = ie.CollectWithOptions(eh.FirstIfdOffset, co)
+	if err != ErrValueBudgetExceeded {
+		t.Fatalf("Expected ErrValueBudgetExceeded: %v", err)
+	}
+}
+
+func TestIfdEnumerate_SetMaxValueBytes__Thumbnail(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, eh, err := NewIfdEnumerateWithBytes(im, ti, getTestExifData())
+	log.PanicIf(err)
+
+	// The thumbnail alone is (21491) bytes and is read while collecting.
+	ie.SetMaxValueBytes(10000)
+
+	_, err = ie.Collect(eh.FirstIfdOffset)
+	if err != ErrValueBudgetExceeded {
+		t.Fatalf("Expected ErrValueBudgetExceeded: %v", err)
+	}
+}
@@ -0,0 +1,232 @@
+package exif
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/mschilli/go-exif/v3/common"
+	"github.com/mschilli/go-exif/v3/undefined"
+)
+
+// valueFormatterKey identifies a tag that has a specialized presentation.
+type valueFormatterKey struct {
+	ifdPath string
+	tagId   uint16
+}
+
+// valueFormatter returns the presentation string for a tag's value or false if
+// the value doesn't have the expected shape (in which case the generic
+// formatting is used).
+type valueFormatter func(value interface{}) (phrase string, ok bool)
+
+var (
+	valueFormatters = map[valueFormatterKey]valueFormatter{
+		// ExposureTime
+		{exifcommon.IfdExifStandardIfdIdentity.UnindexedString(), 0x829a}: formatExposureTime,
+
+		// FNumber
+		{exifcommon.IfdExifStandardIfdIdentity.UnindexedString(), 0x829d}: formatFNumber,
+
+		// FocalLength
+		{exifcommon.IfdExifStandardIfdIdentity.UnindexedString(), 0x920a}: formatFocalLength,
+
+		// GPSLatitude
+		{exifcommon.IfdGpsInfoStandardIfdIdentity.UnindexedString(), 0x0002}: formatGpsDegrees,
+
+		// GPSLongitude
+		{exifcommon.IfdGpsInfoStandardIfdIdentity.UnindexedString(), 0x0004}: formatGpsDegrees,
+	}
+)
+
+// FormatValue returns a human-readable presentation of the tag's value. A
+// handful of well-known tags are formatted by their meaning (e.g. "1/250" for
+// ExposureTime and "f/2.8" for FNumber) and enumerated tags by their labels
+// (see DescribeValue()). Otherwise, ASCII is trimmed,
+// rationals are shown as fractions, and lists are comma-separated.
+// Undefined-type values are formatted like `Format()` does.
+func FormatValue(ite *IfdTagEntry) (phrase string, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	if ite.TagType() == exifcommon.TypeUndefined {
+		phrase, err = ite.Format()
+		log.PanicIf(err)
+
+		return phrase, nil
+	}
+
+	value, err := ite.Value()
+	if err != nil {
+		if err == exifundefined.ErrUnparseableValue {
+			return exifundefined.UnparseableHandledTagValuePlaceholder, nil
+		}
+
+		log.Panic(err)
+	}
+
+	key := valueFormatterKey{
+		ifdPath: ite.ifdIdentity.UnindexedString(),
+		tagId:   ite.TagId(),
+	}
+
+	if label, found := describeSingleValue(key.ifdPath, key.tagId, value); found == true {
+		return label, nil
+	}
+
+	if formatter, found := valueFormatters[key]; found == true {
+		if phrase, ok := formatter(value); ok == true {
+			return phrase, nil
+		}
+	}
+
+	phrase, err = formatGenericValue(value)
+	log.PanicIf(err)
+
+	return phrase, nil
+}
+
+// formatGenericValue formats a value without regard to which tag it came from.
+func formatGenericValue(value interface{}) (phrase string, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	var parts []string
+
+	switch t := value.(type) {
+	case string:
+		return strings.TrimSpace(strings.TrimRight(t, "\000")), nil
+	case []byte:
+		for _, v := range t {
+			parts = append(parts, strconv.FormatUint(uint64(v), 10))
+		}
+	case []int8:
+		for _, v := range t {
+			parts = append(parts, strconv.FormatInt(int64(v), 10))
+		}
+	case []uint16:
+		for _, v := range t {
+			parts = append(parts, strconv.FormatUint(uint64(v), 10))
+		}
+	case []uint32:
+		for _, v := range t {
+			parts = append(parts, strconv.FormatUint(uint64(v), 10))
+		}
+	case []int32:
+		for _, v := range t {
+			parts = append(parts, strconv.FormatInt(int64(v), 10))
+		}
+	case []float32:
+		for _, v := range t {
+			parts = append(parts, strconv.FormatFloat(float64(v), 'f', -1, 32))
+		}
+	case []float64:
+		for _, v := range t {
+			parts = append(parts, strconv.FormatFloat(v, 'f', -1, 64))
+		}
+	case []exifcommon.Rational:
+		for _, v := range t {
+			parts = append(parts, v.String())
+		}
+	case []exifcommon.SignedRational:
+		for _, v := range t {
+			parts = append(parts, v.String())
+		}
+	default:
+		phrase, err := exifcommon.FormatFromType(value, false)
+		log.PanicIf(err)
+
+		return phrase, nil
+	}
+
+	return strings.Join(parts, ", "), nil
+}
+
+// singleRational returns the only rational in the value.
+func singleRational(value interface{}) (r exifcommon.Rational, ok bool) {
+	rationals, ok := value.([]exifcommon.Rational)
+	if ok == false || len(rationals) != 1 || rationals[0].Denominator == 0 {
+		return r, false
+	}
+
+	return rationals[0], true
+}
+
+// hasZeroDenominator returns true if any of the rationals is unknown (has a
+// zero denominator). Rational.Float64() would return zero for these, which
+// isn't a meaningful time or coordinate.
+func hasZeroDenominator(rationals []exifcommon.Rational) bool {
+	for _, r := range rationals {
+		if r.Denominator == 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// formatExposureTime formats the exposure as a fraction of a second (e.g.
+// "1/250") or as whole seconds.
+func formatExposureTime(value interface{}) (phrase string, ok bool) {
+	r, ok := singleRational(value)
+	if ok == false || r.Numerator == 0 {
+		return "", false
+	}
+
+	r = r.Reduce()
+
+	if r.Denominator == 1 {
+		return strconv.FormatUint(uint64(r.Numerator), 10), true
+	} else if r.Numerator == 1 {
+		return r.String(), true
+	}
+
+	return strconv.FormatFloat(r.Float64(), 'f', -1, 64), true
+}
+
+// formatFNumber formats the aperture like "f/2.8".
+func formatFNumber(value interface{}) (phrase string, ok bool) {
+	r, ok := singleRational(value)
+	if ok == false {
+		return "", false
+	}
+
+	fNumber := math.Round(r.Float64()*10) / 10
+	return fmt.Sprintf("f/%s", strconv.FormatFloat(fNumber, 'f', -1, 64)), true
+}
+
+// formatFocalLength formats the focal-length in millimeters.
+func formatFocalLength(value interface{}) (phrase string, ok bool) {
+	r, ok := singleRational(value)
+	if ok == false {
+		return "", false
+	}
+
+	focalLength := math.Round(r.Float64()*10) / 10
+	return fmt.Sprintf("%s mm", strconv.FormatFloat(focalLength, 'f', -1, 64)), true
+}
+
+// formatGpsDegrees formats a degrees/minutes/seconds triplet as decimal
+// degrees. The hemisphere is in a separate tag and is not applied.
+func formatGpsDegrees(value interface{}) (phrase string, ok bool) {
+	rationals, ok := value.([]exifcommon.Rational)
+	if ok == false || len(rationals) != 3 {
+		return "", false
+	}
+
+	if hasZeroDenominator(rationals) == true {
+		return "", false
+	}
+
+	decimal := rationals[0].Float64() + rationals[1].Float64()/60 + rationals[2].Float64()/3600
+	return strconv.FormatFloat(decimal, 'f', 6, 64), true
+}
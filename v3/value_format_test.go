@@ -0,0 +1,92 @@
+package exif
+
+import (
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/mschilli/go-exif/v3/common"
+)
+
+func TestFormatValue(t *testing.T) {
+	rawExif, err := SearchFileAndExtractExif(getTestGpsImageFilepath())
+	log.PanicIf(err)
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	_, index, err := Collect(im, ti, rawExif)
+	log.PanicIf(err)
+
+	exifIfdPath := exifcommon.IfdExifStandardIfdIdentity.String()
+	gpsIfdPath := exifcommon.IfdGpsInfoStandardIfdIdentity.String()
+
+	cases := []struct {
+		fqIfdPath string
+		tagId     uint16
+		expected  string
+	}{
+		// Model
+		{"IFD", 0x0110, "SM-N920T"},
+
+		// XResolution
+		{"IFD", 0x011a, "72/1"},
+
+		// ExposureTime
+		{exifIfdPath, 0x829a, "1/13"},
+
+		// FNumber
+		{exifIfdPath, 0x829d, "f/1.9"},
+
+		// FocalLength
+		{exifIfdPath, 0x920a, "4.3 mm"},
+
+		// BrightnessValue
+		{exifIfdPath, 0x9203, "-57/100"},
+
+		// GPSVersionID
+		{gpsIfdPath, 0x0000, "2, 2, 0, 0"},
+
+		// GPSLatitude
+		{gpsIfdPath, 0x0002, "26.586667"},
+
+		// GPSTimeStamp
+		{gpsIfdPath, 0x0007, "1/1, 22/1, 57/1"},
+	}
+
+	for _, c := range cases {
+		ite, _, err := index.FindTag(c.fqIfdPath, c.tagId)
+		log.PanicIf(err)
+
+		phrase, err := FormatValue(ite)
+		log.PanicIf(err)
+
+		if phrase != c.expected {
+			t.Fatalf("Value for [%s] (0x%04x) not correct: [%s] != [%s]", c.fqIfdPath, c.tagId, phrase, c.expected)
+		}
+	}
+}
+
+func TestFormatExposureTime(t *testing.T) {
+	cases := map[exifcommon.Rational]string{
+		{Numerator: 10, Denominator: 2500}: "1/250",
+		{Numerator: 2, Denominator: 1}:     "2",
+		{Numerator: 3, Denominator: 10}:    "0.3",
+	}
+
+	for r, expected := range cases {
+		phrase, ok := formatExposureTime([]exifcommon.Rational{r})
+		if ok != true {
+			t.Fatalf("Exposure not formatted: %v", r)
+		} else if phrase != expected {
+			t.Fatalf("Exposure not correct: [%s] != [%s]", phrase, expected)
+		}
+	}
+
+	_, ok := formatExposureTime([]exifcommon.Rational{{Numerator: 1, Denominator: 0}})
+	if ok != false {
+		t.Fatalf("Expected zero denominator to fall back to generic formatting.")
+	}
+}
@@ -0,0 +1,156 @@
+package exif
+
+import (
+	"sync"
+
+	"github.com/mschilli/go-exif/v3/common"
+)
+
+var (
+	ifdValueLabelsPath  = exifcommon.IfdStandardIfdIdentity.UnindexedString()
+	exifValueLabelsPath = exifcommon.IfdExifStandardIfdIdentity.UnindexedString()
+)
+
+var (
+	// valueLabels maps the values of enumerated tags to their labels. The
+	// keys use the unindexed IFD-path, so IFD0 and IFD1 share them.
+	valueLabels = map[valueFormatterKey]map[uint32]string{
+		// ResolutionUnit
+		{ifdValueLabelsPath, 0x0128}: {
+			1: "none",
+			2: "inches",
+			3: "centimeters",
+		},
+
+		// ExposureProgram
+		{exifValueLabelsPath, 0x8822}: {
+			0: "not defined",
+			1: "manual",
+			2: "normal program",
+			3: "aperture priority",
+			4: "shutter priority",
+			5: "creative program",
+			6: "action program",
+			7: "portrait mode",
+			8: "landscape mode",
+		},
+
+		// MeteringMode
+		{exifValueLabelsPath, 0x9207}: {
+			0:   "unknown",
+			1:   "average",
+			2:   "center-weighted average",
+			3:   "spot",
+			4:   "multi-spot",
+			5:   "pattern",
+			6:   "partial",
+			255: "other",
+		},
+
+		// Flash
+		{exifValueLabelsPath, 0x9209}: {
+			0x00: "flash did not fire",
+			0x01: "flash fired",
+			0x05: "flash fired, return light not detected",
+			0x07: "flash fired, return light detected",
+			0x08: "flash did not fire, compulsory flash mode",
+			0x09: "flash fired, compulsory flash mode",
+			0x0d: "flash fired, compulsory flash mode, return light not detected",
+			0x0f: "flash fired, compulsory flash mode, return light detected",
+			0x10: "flash did not fire, compulsory flash suppression mode",
+			0x18: "flash did not fire, auto mode",
+			0x19: "flash fired, auto mode",
+			0x1d: "flash fired, auto mode, return light not detected",
+			0x1f: "flash fired, auto mode, return light detected",
+			0x20: "no flash function",
+			0x41: "flash fired, red-eye reduction mode",
+			0x45: "flash fired, red-eye reduction mode, return light not detected",
+			0x47: "flash fired, red-eye reduction mode, return light detected",
+			0x49: "flash fired, compulsory flash mode, red-eye reduction mode",
+			0x4d: "flash fired, compulsory flash mode, red-eye reduction mode, return light not detected",
+			0x4f: "flash fired, compulsory flash mode, red-eye reduction mode, return light detected",
+			0x59: "flash fired, auto mode, red-eye reduction mode",
+			0x5d: "flash fired, auto mode, return light not detected, red-eye reduction mode",
+			0x5f: "flash fired, auto mode, return light detected, red-eye reduction mode",
+		},
+
+		// WhiteBalance
+		{exifValueLabelsPath, 0xa403}: {
+			0: "auto",
+			1: "manual",
+		},
+	}
+
+	valueLabelsLock sync.RWMutex
+)
+
+// RegisterValueLabels adds labels for the values of an enumerated tag (e.g. a
+// vendor tag), replacing any that the value already had. `ifdName` is the
+// unindexed IFD-path (e.g. "IFD/Exif").
+func RegisterValueLabels(ifdName string, tagId uint16, labels map[uint32]string) {
+	valueLabelsLock.Lock()
+	defer valueLabelsLock.Unlock()
+
+	key := valueFormatterKey{
+		ifdPath: ifdName,
+		tagId:   tagId,
+	}
+
+	tagLabels, found := valueLabels[key]
+	if found == false {
+		tagLabels = make(map[uint32]string)
+		valueLabels[key] = tagLabels
+	}
+
+	for value, label := range labels {
+		tagLabels[value] = label
+	}
+}
+
+// DescribeValue returns the label for a value of an enumerated tag (e.g.
+// "inches" for a ResolutionUnit of 2). `ifdName` is the unindexed IFD-path
+// (e.g. "IFD" or "IFD/Exif"). False is returned if the tag isn't enumerated
+// or the value doesn't have a label.
+func DescribeValue(ifdName string, tagId uint16, raw uint32) (label string, found bool) {
+	valueLabelsLock.RLock()
+	defer valueLabelsLock.RUnlock()
+
+	key := valueFormatterKey{
+		ifdPath: ifdName,
+		tagId:   tagId,
+	}
+
+	label, found = valueLabels[key][raw]
+	return label, found
+}
+
+// describeSingleValue returns the label for the value if it is a single
+// integer of an enumerated tag.
+func describeSingleValue(ifdName string, tagId uint16, value interface{}) (label string, found bool) {
+	var raw uint32
+
+	switch t := value.(type) {
+	case []byte:
+		if len(t) != 1 {
+			return "", false
+		}
+
+		raw = uint32(t[0])
+	case []uint16:
+		if len(t) != 1 {
+			return "", false
+		}
+
+		raw = uint32(t[0])
+	case []uint32:
+		if len(t) != 1 {
+			return "", false
+		}
+
+		raw = t[0]
+	default:
+		return "", false
+	}
+
+	return DescribeValue(ifdName, tagId, raw)
+}
@@ -0,0 +1,94 @@
+package exif
+
+import (
+	"testing"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/mschilli/go-exif/v3/common"
+)
+
+func TestDescribeValue(t *testing.T) {
+	label, found := DescribeValue("IFD", 0x0128, 2)
+	if found != true {
+		t.Fatalf("ResolutionUnit label not found.")
+	} else if label != "inches" {
+		t.Fatalf("ResolutionUnit label not correct: [%s]", label)
+	}
+
+	label, found = DescribeValue("IFD/Exif", 0x9207, 255)
+	if found != true || label != "other" {
+		t.Fatalf("MeteringMode label not correct: [%s] %v", label, found)
+	}
+
+	if _, found := DescribeValue("IFD", 0x0128, 99); found != false {
+		t.Fatalf("Expected no label for an unknown value.")
+	} else if _, found := DescribeValue("IFD/Exif", 0x0128, 2); found != false {
+		t.Fatalf("Expected no label for a tag in the wrong IFD.")
+	} else if _, found := DescribeValue("IFD", 0x0110, 0); found != false {
+		t.Fatalf("Expected no label for a tag that isn't enumerated.")
+	}
+}
+
+func TestRegisterValueLabels(t *testing.T) {
+	ifdName := "IFD/Exif"
+	tagId := uint16(0xfffe)
+
+	key := valueFormatterKey{
+		ifdPath: ifdName,
+		tagId:   tagId,
+	}
+
+	defer func() {
+		valueLabelsLock.Lock()
+		defer valueLabelsLock.Unlock()
+
+		delete(valueLabels, key)
+	}()
+
+	RegisterValueLabels(ifdName, tagId, map[uint32]string{1: "one", 2: "two"})
+	RegisterValueLabels(ifdName, tagId, map[uint32]string{2: "second"})
+
+	if label, found := DescribeValue(ifdName, tagId, 1); found != true || label != "one" {
+		t.Fatalf("First label not correct: [%s]", label)
+	} else if label, found := DescribeValue(ifdName, tagId, 2); found != true || label != "second" {
+		t.Fatalf("Replaced label not correct: [%s]", label)
+	}
+}
+
+func TestFormatValue__Enumerated(t *testing.T) {
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	_, index, err := Collect(im, ti, getTestExifData())
+	log.PanicIf(err)
+
+	cases := []struct {
+		fqIfdPath string
+		tagId     uint16
+		expected  string
+	}{
+		// ResolutionUnit
+		{"IFD", 0x0128, "inches"},
+
+		// ResolutionUnit (in IFD1)
+		{"IFD1", 0x0128, "inches"},
+
+		// Flash
+		{"IFD/Exif", 0x9209, "flash did not fire, compulsory flash suppression mode"},
+	}
+
+	for _, c := range cases {
+		ite, _, err := index.FindTag(c.fqIfdPath, c.tagId)
+		log.PanicIf(err)
+
+		phrase, err := FormatValue(ite)
+		log.PanicIf(err)
+
+		if phrase != c.expected {
+			t.Fatalf("Value for [%s] (0x%04x) not correct: [%s] != [%s]", c.fqIfdPath, c.tagId, phrase, c.expected)
+		}
+	}
+}
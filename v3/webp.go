@@ -0,0 +1,126 @@
+package exif
+
+import (
+	"bytes"
+	"errors"
+
+	"encoding/binary"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/mschilli/go-exif/v3/common"
+)
+
+var (
+	webpLogger = newPackageLogger("exif.webp")
+)
+
+var (
+	// ErrNotWebp means that the data does not start with the RIFF/WEBP
+	// header.
+	ErrNotWebp = errors.New("not a WebP")
+)
+
+const (
+	// webpHeaderSize is the size of the "RIFF" signature, the RIFF size, and
+	// the "WEBP" form type.
+	webpHeaderSize = 12
+
+	// webpChunkHeaderSize is the size of the type and length at the front of
+	// each chunk.
+	webpChunkHeaderSize = 8
+)
+
+var (
+	webpRiffSignature = [4]byte{'R', 'I', 'F', 'F'}
+	webpFormType      = [4]byte{'W', 'E', 'B', 'P'}
+	webpExifChunkType = [4]byte{'E', 'X', 'I', 'F'}
+)
+
+// SearchWebpAndExtractExif returns the EXIF data from the EXIF chunk of a
+// WebP. The chunk payload is normally the raw TIFF stream, but some writers
+// put the "Exif\0\0" identifier from JPEG in front of it. That is detected
+// and stripped, so the data always starts at the TIFF header. ErrNotWebp is
+// returned (unwrapped) if the data is not a WebP and ErrNoExif if there is no
+// EXIF chunk.
+func SearchWebpAndExtractExif(webpData []byte) (rawExif []byte, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	if len(webpData) < webpHeaderSize || bytes.Equal(webpData[:4], webpRiffSignature[:]) == false || bytes.Equal(webpData[8:12], webpFormType[:]) == false {
+		return nil, ErrNotWebp
+	}
+
+	// The RIFF size covers everything after the size field itself.
+	end := len(webpData)
+	if riffEnd := 8 + int64(binary.LittleEndian.Uint32(webpData[4:8])); riffEnd < int64(end) {
+		end = int(riffEnd)
+	}
+
+	// Each chunk is a four-byte type, a four-byte (little-endian) length, and
+	// the data, which is padded to an even length.
+
+	offset := webpHeaderSize
+	for offset+webpChunkHeaderSize <= end {
+		var chunkType [4]byte
+		copy(chunkType[:], webpData[offset:offset+4])
+
+		length := int64(binary.LittleEndian.Uint32(webpData[offset+4 : offset+8]))
+
+		dataOffset := offset + webpChunkHeaderSize
+		if int64(dataOffset)+length > int64(end) {
+			webpLogger.Warningf(nil, "WebP chunk [%s] at offset (%d) runs past the end of the data.", string(chunkType[:]), offset)
+			break
+		}
+
+		if chunkType == webpExifChunkType {
+			rawExif = webpData[dataOffset : dataOffset+int(length)]
+
+			if bytes.HasPrefix(rawExif, JpegExifPrefix) == true {
+				rawExif = rawExif[len(JpegExifPrefix):]
+			}
+
+			return rawExif, nil
+		}
+
+		offset = dataOffset + int(length) + int(length%2)
+	}
+
+	return nil, ErrNoExif
+}
+
+// NewIfdEnumerateFromWebp returns an IfdEnumerate for the EXIF data in the
+// EXIF chunk of the given WebP. The byte-order is taken from the TIFF header
+// at the front of the chunk, and the header is returned in order to provide
+// the offset of the first IFD. ErrNotWebp or ErrNoExif is returned
+// (unwrapped) if there is no usable EXIF.
+func NewIfdEnumerateFromWebp(ifdMapping *exifcommon.IfdMapping, tagIndex *TagIndex, webpData []byte) (ie *IfdEnumerate, eh ExifHeader, err error) {
+	defer func() {
+		if state := recover(); state != nil {
+			err = log.Wrap(state.(error))
+		}
+	}()
+
+	rawExif, err := SearchWebpAndExtractExif(webpData)
+	if err != nil {
+		if err == ErrNotWebp || err == ErrNoExif {
+			return nil, eh, err
+		}
+
+		log.Panic(err)
+	}
+
+	ie, eh, err = NewIfdEnumerateWithBytes(ifdMapping, tagIndex, rawExif)
+	if err != nil {
+		if err == ErrNoExif {
+			return nil, eh, err
+		}
+
+		log.Panic(err)
+	}
+
+	return ie, eh, nil
+}
@@ -0,0 +1,128 @@
+package exif
+
+import (
+	"bytes"
+	"testing"
+
+	"encoding/binary"
+
+	"github.com/dsoprea/go-logging"
+
+	"github.com/mschilli/go-exif/v3/common"
+)
+
+func getTestWebpData(chunks ...[]interface{}) []byte {
+	body := new(bytes.Buffer)
+	body.WriteString("WEBP")
+
+	for _, chunk := range chunks {
+		chunkType := chunk[0].(string)
+		data := chunk[1].([]byte)
+
+		body.WriteString(chunkType)
+
+		err := binary.Write(body, binary.LittleEndian, uint32(len(data)))
+		log.PanicIf(err)
+
+		body.Write(data)
+
+		if len(data)%2 == 1 {
+			body.WriteByte(0)
+		}
+	}
+
+	b := new(bytes.Buffer)
+	b.WriteString("RIFF")
+
+	err := binary.Write(b, binary.LittleEndian, uint32(body.Len()))
+	log.PanicIf(err)
+
+	b.Write(body.Bytes())
+
+	return b.Bytes()
+}
+
+func TestSearchWebpAndExtractExif(t *testing.T) {
+	exifData := getTestExifData()
+
+	webpData := getTestWebpData(
+		[]interface{}{"VP8X", make([]byte, 10)},
+		[]interface{}{"ICCP", make([]byte, 3)},
+		[]interface{}{"VP8 ", make([]byte, 20)},
+		[]interface{}{"EXIF", exifData})
+
+	rawExif, err := SearchWebpAndExtractExif(webpData)
+	log.PanicIf(err)
+
+	if bytes.Equal(rawExif, exifData) != true {
+		t.Fatalf("EXIF data not correct.")
+	}
+}
+
+func TestSearchWebpAndExtractExif__ExifPrefix(t *testing.T) {
+	exifData := getTestExifData()
+
+	webpData := getTestWebpData(
+		[]interface{}{"VP8X", make([]byte, 10)},
+		[]interface{}{"EXIF", append(append([]byte{}, JpegExifPrefix...), exifData...)})
+
+	rawExif, err := SearchWebpAndExtractExif(webpData)
+	log.PanicIf(err)
+
+	if bytes.Equal(rawExif, exifData) != true {
+		t.Fatalf("EXIF data not correct.")
+	}
+}
+
+func TestSearchWebpAndExtractExif__NoExif(t *testing.T) {
+	webpData := getTestWebpData(
+		[]interface{}{"VP8 ", make([]byte, 20)})
+
+	_, err := SearchWebpAndExtractExif(webpData)
+	if err != ErrNoExif {
+		t.Fatalf("Expected no-EXIF error: %v", err)
+	}
+}
+
+func TestSearchWebpAndExtractExif__NotWebp(t *testing.T) {
+	_, err := SearchWebpAndExtractExif(getTestExifData())
+	if err != ErrNotWebp {
+		t.Fatalf("Expected not-WebP error: %v", err)
+	}
+}
+
+func TestSearchWebpAndExtractExif__Truncated(t *testing.T) {
+	webpData := getTestWebpData(
+		[]interface{}{"VP8X", make([]byte, 10)},
+		[]interface{}{"EXIF", getTestExifData()})
+
+	_, err := SearchWebpAndExtractExif(webpData[:len(webpData)-10])
+	if err != ErrNoExif {
+		t.Fatalf("Expected no-EXIF error: %v", err)
+	}
+}
+
+func TestNewIfdEnumerateFromWebp(t *testing.T) {
+	webpData := getTestWebpData(
+		[]interface{}{"VP8X", make([]byte, 10)},
+		[]interface{}{"EXIF", getTestExifData()})
+
+	im, err := exifcommon.NewIfdMappingWithStandard()
+	log.PanicIf(err)
+
+	ti := NewTagIndex()
+
+	ie, eh, err := NewIfdEnumerateFromWebp(im, ti, webpData)
+	log.PanicIf(err)
+
+	if eh.ByteOrder != binary.LittleEndian {
+		t.Fatalf("Byte-order not correct.")
+	}
+
+	index, err := ie.Collect(eh.FirstIfdOffset)
+	log.PanicIf(err)
+
+	if len(index.Ifds) != 5 {
+		t.Fatalf("IFD count not correct: (%d)", len(index.Ifds))
+	}
+}